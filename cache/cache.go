@@ -0,0 +1,117 @@
+// Package cache menyediakan cache in-process untuk hasil query yang mahal,
+// misal agregasi landing page. Nilai yang disimpan dipakai bersama oleh
+// semua request, jadi pemanggil tidak boleh mengubahnya.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache menyimpan nilai per key. Implementasinya harus aman dipakai
+// bersamaan oleh banyak goroutine.
+type Cache interface {
+	// Get mengembalikan nilai yang belum kedaluwarsa.
+	Get(key string) (any, bool)
+	// Set menyimpan value jika Purge belum dipanggil sejak generation
+	// didapat dari Generation. Dengan begitu hasil query yang dimulai
+	// sebelum sebuah update tidak tersimpan setelah cache dikosongkan.
+	Set(key string, value any, generation uint64)
+	Generation() uint64
+	// Purge mengosongkan cache.
+	Purge()
+}
+
+// LRU adalah Cache dengan jumlah entry terbatas: entry yang paling lama
+// tidak dibaca dibuang lebih dulu, dan setiap entry kedaluwarsa setelah ttl.
+type LRU struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu         sync.Mutex
+	items      map[string]*list.Element
+	order      *list.List // Depan = paling baru dibaca
+	generation uint64
+}
+
+type entry struct {
+	key     string
+	value   any
+	expires time.Time
+}
+
+// NewLRU membuat LRU dengan paling banyak size entry.
+func NewLRU(size int, ttl time.Duration) *LRU {
+	return &LRU{
+		size:  size,
+		ttl:   ttl,
+		now:   time.Now,
+		items: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+func (c *LRU) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !c.now().Before(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *LRU) Set(key string, value any, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value, e.expires = value, expires
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRU) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+func (c *LRU) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+	c.generation++
+}
+
+// Len mengembalikan jumlah entry, termasuk yang sudah kedaluwarsa tetapi
+// belum dibuang.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove membuang satu entry. Pemanggil harus memegang lock.
+func (c *LRU) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry).key)
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Now()
+	c := NewLRU(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	gen := c.Generation()
+	c.Set("a", 1, gen)
+	c.Set("b", 2, gen)
+	c.Get("a") // b menjadi yang paling lama tidak dibaca
+	c.Set("c", 3, gen)
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Get(a) = %v, %v; want 1, true", v, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a should have expired")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1 after removing the expired entry", c.Len())
+	}
+
+	// Hasil query yang dimulai sebelum Purge tidak boleh tersimpan
+	stale := c.Generation()
+	c.Purge()
+	c.Set("d", 4, stale)
+	if _, ok := c.Get("d"); ok {
+		t.Error("Set with a generation from before Purge was stored")
+	}
+	c.Set("d", 4, c.Generation())
+	if _, ok := c.Get("d"); !ok {
+		t.Error("Set with the current generation was not stored")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/migrations"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// check adalah satu hitungan dokumen di sebuah koleksi. Jika problem true,
+// hitungan lebih dari nol dianggap data yang perlu diperbaiki.
+type check struct {
+	label   string
+	filter  bson.M
+	problem bool
+}
+
+var healthChecks = []struct {
+	collection string
+	checks     []check
+}{
+	{store.CollectionUsers, []check{
+		{"unverified email", bson.M{"verified_email": bson.M{"$ne": true}}, false},
+		{"role not assigned", bson.M{"is_role_assigned": bson.M{"$ne": true}}, false},
+		{"admins", bson.M{"role": "admin"}, false},
+		{"owners", bson.M{"role": "owner"}, false},
+	}},
+	{store.CollectionBoardingHouses, []check{
+		{"missing slug", bson.M{"slug": nil}, true},
+		{"missing owner_id", bson.M{"owner_id": nil}, true},
+	}},
+	{store.CollectionRooms, []check{
+		{"negative number_available", bson.M{"number_available": bson.M{"$lt": 0}}, true},
+		{"missing capacity", bson.M{"capacity": nil}, false},
+		{"missing boarding_house_id", bson.M{"boarding_house_id": nil}, true},
+	}},
+	{store.CollectionTransactions, []check{
+		{"pending", bson.M{"payment_status": "pending"}, false},
+		{"pending > 24h", bson.M{"payment_status": "pending", "created_at": bson.M{"$lt": time.Now().Add(-24 * time.Hour)}}, true},
+		{"missing transaction_code", bson.M{"transaction_code": nil}, true},
+	}},
+	{store.CollectionFacilities, nil},
+	{store.CollectionCategories, nil},
+	{store.CollectionCustomFacilities, []check{
+		{"missing owner_id", bson.M{"owner_id": nil}, true},
+	}},
+	{store.CollectionIdempotencyKeys, []check{
+		{"expired", bson.M{"expires_at": bson.M{"$lt": time.Now().Add(-time.Hour)}}, false},
+	}},
+	{store.CollectionSessions, []check{
+		{"active", bson.M{"revoked_at": nil, "expires_at": bson.M{"$gt": time.Now()}}, false},
+		{"expired", bson.M{"expires_at": bson.M{"$lt": time.Now().Add(-time.Hour)}}, false},
+	}},
+	{store.CollectionPasswordResets, []check{
+		{"pending", bson.M{"used_at": nil, "expires_at": bson.M{"$gt": time.Now()}}, false},
+		{"expired", bson.M{"expires_at": bson.M{"$lt": time.Now().Add(-time.Hour)}}, false},
+	}},
+	{store.CollectionOAuthStates, []check{
+		{"expired", bson.M{"expires_at": bson.M{"$lt": time.Now().Add(-time.Hour)}}, false},
+	}},
+}
+
+// health mencetak jumlah dokumen, index dan hasil pengecekan per koleksi.
+// Exit code 1 jika ada masalah, agar bisa dipakai di cron atau CI.
+func health(ctx context.Context, a *app, args []string) error {
+	fs := flags("health")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := a.stores.Ping(ctx); err != nil {
+		return fmt.Errorf("database is not reachable: %w", err)
+	}
+
+	var problems int
+	w := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "COLLECTION\tCHECK\tCOUNT\t")
+	for _, hc := range healthChecks {
+		coll := a.db.Collection(hc.collection)
+		total, err := coll.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			return fmt.Errorf("%s: %w", hc.collection, err)
+		}
+		fmt.Fprintf(w, "%s\tdocuments\t%d\t\n", hc.collection, total)
+
+		indexes, err := coll.Indexes().ListSpecifications(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", hc.collection, err)
+		}
+		fmt.Fprintf(w, "\tindexes\t%d\t\n", len(indexes))
+
+		// Seharusnya sudah diisi migrasi backfill timestamp
+		checks := append([]check{{"missing created_at", bson.M{"created_at": nil}, true}}, hc.checks...)
+		for _, c := range checks {
+			n, err := coll.CountDocuments(ctx, c.filter)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", hc.collection, c.label, err)
+			}
+			mark := ""
+			if c.problem && n > 0 {
+				mark = "!"
+				problems++
+			}
+			fmt.Fprintf(w, "\t%s\t%d\t%s\n", c.label, n, mark)
+		}
+	}
+
+	statuses, err := migrations.Statuses(ctx, a.db, migrations.All)
+	if err != nil {
+		return err
+	}
+	var pending int
+	for _, s := range statuses {
+		if !s.Applied {
+			pending++
+		}
+	}
+	mark := ""
+	if pending > 0 {
+		mark = "!"
+		problems++
+	}
+	fmt.Fprintf(w, "%s\tpending migrations\t%d\t%s\n", migrations.Collection, pending, mark)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d checks need attention (marked !)", problems)
+	}
+	fmt.Fprintln(a.out, "all checks passed")
+	return nil
+}
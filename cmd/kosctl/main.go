@@ -0,0 +1,129 @@
+// Command kosctl menjalankan tugas operasional yang sebelumnya dikerjakan
+// manual lewat Mongo shell. Konfigurasi dibaca sama seperti cmd/server
+// (CONFIG_FILE, environment variable dan .env).
+//
+//	go run ./cmd/kosctl <command> [flags]
+//
+// Jalankan tanpa argumen untuk melihat daftar command.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/server"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// app adalah dependensi yang dipakai semua command.
+type app struct {
+	cfg    *config.Config
+	db     *mongo.Database
+	stores *store.Stores
+	in     io.Reader
+	out    io.Writer
+}
+
+type command struct {
+	args    string
+	summary string
+	run     func(ctx context.Context, a *app, args []string) error
+}
+
+var commands = map[string]command{
+	"create-admin":           {"-email EMAIL -name NAME [-password PASSWORD]", "buat admin baru atau jadikan user yang sudah ada admin", createAdmin},
+	"set-password":           {"-email EMAIL [-password PASSWORD]", "ganti password user", setPassword},
+	"set-role":               {"-email EMAIL -role user|owner|admin", "ganti role user", setRole},
+	"resend-verification":    {"-email EMAIL | -all", "kirim ulang email verifikasi", resendVerification},
+	"migrate":                {"[-status]", "jalankan migrasi yang belum tercatat atau tampilkan status-nya", migrate},
+	"recompute-availability": {"[-room ID] [-dry-run]", "hitung ulang number_available kamar dari transaksi aktif", recomputeAvailability},
+	"health":                 {"", "ringkasan data dan masalah per koleksi", health},
+	"seed":                   {"[-file FILE] [-force]", "isi database dengan data contoh dari fixtures", seed},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage(os.Stdout)
+		return
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "kosctl: unknown command %q\n\n", name)
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	if err := run(cmd, os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "kosctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: kosctl <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Password yang tidak diberikan lewat -password dibaca dari baris pertama stdin.")
+	fmt.Fprintln(w)
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n      %s\n", strings.TrimSpace(name+" "+commands[name].args), commands[name].summary)
+	}
+}
+
+func run(cmd command, args []string) error {
+	// Load .env jika ada, digunakan hanya jika di run secara local
+	_ = godotenv.Load()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	// Log ke stderr agar output command tetap bersih untuk di-pipe
+	slog.SetDefault(server.NewLogger(os.Stderr, cfg.LogLevel))
+
+	client, err := config.ConnectDB(cfg.Mongo)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = client.Disconnect(ctx)
+	}()
+
+	a := &app{
+		cfg:    cfg,
+		db:     config.DB,
+		stores: store.NewMongo(config.DB, cfg.Timeouts.Database),
+		in:     os.Stdin,
+		out:    os.Stdout,
+	}
+	return cmd.run(context.Background(), a, args)
+}
+
+// flags membuat FlagSet untuk satu command. Error parsing dikembalikan,
+// bukan exit, agar pesan error konsisten.
+func flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("kosctl "+name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
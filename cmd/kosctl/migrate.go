@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/migrations"
+)
+
+func migrate(ctx context.Context, a *app, args []string) error {
+	fs := flags("migrate")
+	status := fs.Bool("status", false, "tampilkan migrasi yang sudah dan belum dijalankan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *status {
+		statuses, err := migrations.Statuses(ctx, a.db, migrations.All)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tAPPLIED\tDESCRIPTION")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.Applied {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, applied, s.Description)
+		}
+		return w.Flush()
+	}
+
+	ran, err := migrations.Run(ctx, a.db)
+	if err != nil {
+		return err
+	}
+	if len(ran) == 0 {
+		fmt.Fprintln(a.out, "database is up to date")
+		return nil
+	}
+	fmt.Fprintf(a.out, "applied migrations %v\n", ran)
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recomputeAvailability menghitung ulang number_available = capacity -
+// transaksi aktif. CreateTransaction mengurangi number_available tetapi
+// tidak ada yang mengembalikannya saat transaksi expire atau dibatalkan.
+//
+// Kamar lama yang belum punya capacity dianggap capacity = number_available
+// + jumlah semua transaksinya (setiap transaksi pernah mengurangi satu),
+// lalu capacity tersebut disimpan.
+func recomputeAvailability(ctx context.Context, a *app, args []string) error {
+	fs := flags("recompute-availability")
+	roomID := fs.String("room", "", "hanya kamar dengan ID ini")
+	dryRun := fs.Bool("dry-run", false, "tampilkan perubahan tanpa menyimpan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var rooms []models.Room
+	if *roomID != "" {
+		id, err := primitive.ObjectIDFromHex(*roomID)
+		if err != nil {
+			return fmt.Errorf("-room %q is not a valid ObjectID", *roomID)
+		}
+		room, err := a.stores.Rooms.FindByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("room %s: %w", *roomID, err)
+		}
+		rooms = append(rooms, *room)
+	} else {
+		all, err := a.stores.Rooms.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		rooms = all
+	}
+
+	w := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ROOM\tCAPACITY\tACTIVE\tBEFORE\tAFTER")
+	var changed int
+	for _, room := range rooms {
+		transactions, err := a.stores.Transactions.FindByRoom(ctx, room.RoomID)
+		if err != nil {
+			return fmt.Errorf("room %s: %w", room.RoomID.Hex(), err)
+		}
+		capacity := room.Capacity
+		if capacity == 0 {
+			capacity = room.NumberAvailable + len(transactions)
+		}
+		active := store.CountActive(transactions)
+		available := capacity - active
+		if available < 0 {
+			available = 0
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", room.RoomID.Hex(), capacity, active, room.NumberAvailable, available)
+
+		if available == room.NumberAvailable && capacity == room.Capacity {
+			continue
+		}
+		changed++
+		if *dryRun {
+			continue
+		}
+		err = a.stores.Rooms.Update(ctx, room.RoomID, bson.M{
+			"capacity":         capacity,
+			"number_available": available,
+			"status":           map[bool]string{true: "Tersedia", false: "Tidak Tersedia"}[available > 0],
+			"updated_at":       time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("room %s: %w", room.RoomID.Hex(), err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Fprintf(a.out, "%d of %d rooms would change (dry run)\n", changed, len(rooms))
+		return nil
+	}
+	fmt.Fprintf(a.out, "%d of %d rooms updated\n", changed, len(rooms))
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRecomputeAvailability(t *testing.T) {
+	ctx := context.Background()
+	stores := store.NewMemory()
+	var out bytes.Buffer
+	a := &app{stores: stores, out: &out}
+
+	// legacy: belum punya capacity, dua transaksi pernah mengurangi number_available
+	legacy := models.Room{RoomID: primitive.NewObjectID(), NumberAvailable: 1}
+	// drifted: transaksi expire tidak mengembalikan number_available
+	drifted := models.Room{RoomID: primitive.NewObjectID(), Capacity: 3, NumberAvailable: 0}
+	steady := models.Room{RoomID: primitive.NewObjectID(), Capacity: 2, NumberAvailable: 2, Status: "Tersedia"}
+	for _, room := range []*models.Room{&legacy, &drifted, &steady} {
+		if err := stores.Rooms.Create(ctx, room); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tx := range []struct {
+		room   primitive.ObjectID
+		status string
+	}{
+		{legacy.RoomID, "pending"},
+		{legacy.RoomID, "expire"},
+		{drifted.RoomID, "settlement"},
+		{drifted.RoomID, "expire"},
+		{drifted.RoomID, "cancelled"},
+	} {
+		err := stores.Transactions.Create(ctx, &models.Transaction{TransactionID: primitive.NewObjectID(), RoomID: tx.room, PaymentStatus: tx.status})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	room := func(id primitive.ObjectID) *models.Room {
+		t.Helper()
+		r, err := stores.Rooms.FindByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	run := func(args ...string) string {
+		t.Helper()
+		out.Reset()
+		if err := recomputeAvailability(ctx, a, args); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	if got := run("-dry-run"); !strings.Contains(got, "2 of 3 rooms would change") {
+		t.Errorf("dry run output:\n%s", got)
+	}
+	if r := room(legacy.RoomID); r.Capacity != 0 || r.NumberAvailable != 1 {
+		t.Errorf("dry run changed the legacy room: %+v", r)
+	}
+
+	if got := run(); !strings.Contains(got, "2 of 3 rooms updated") {
+		t.Errorf("output:\n%s", got)
+	}
+	for _, tc := range []struct {
+		name                string
+		id                  primitive.ObjectID
+		capacity, available int
+		status              string
+	}{
+		// capacity = number_available + semua transaksi = 1 + 2; satu masih aktif
+		{"legacy", legacy.RoomID, 3, 2, "Tersedia"},
+		{"drifted", drifted.RoomID, 3, 2, "Tersedia"},
+		{"steady", steady.RoomID, 2, 2, "Tersedia"},
+	} {
+		if r := room(tc.id); r.Capacity != tc.capacity || r.NumberAvailable != tc.available || r.Status != tc.status {
+			t.Errorf("%s: capacity %d, available %d, status %q; want %d, %d, %q",
+				tc.name, r.Capacity, r.NumberAvailable, r.Status, tc.capacity, tc.available, tc.status)
+		}
+	}
+
+	// Capacity yang sudah tersimpan dipakai apa adanya, jadi hasilnya stabil
+	if got := run(); !strings.Contains(got, "0 of 3 rooms updated") {
+		t.Errorf("second run output:\n%s", got)
+	}
+
+	if err := recomputeAvailability(ctx, a, []string{"-room", "not-an-id"}); err == nil {
+		t.Error("invalid -room accepted")
+	}
+	if got := run("-room", steady.RoomID.Hex()); !strings.Contains(got, "0 of 1 rooms updated") {
+		t.Errorf("-room output:\n%s", got)
+	}
+}
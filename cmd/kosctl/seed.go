@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/organisasi/kosconnectbackend/fixtures"
+	"github.com/organisasi/kosconnectbackend/store"
+)
+
+// seed memuat dataset fixture ke database. Dokumen yang sudah ada dilewati,
+// jadi seed aman dijalankan ulang setelah file fixture ditambah.
+func seed(ctx context.Context, a *app, args []string) error {
+	fs := flags("seed")
+	file := fs.String("file", "", "file fixture .yaml/.yml/.json (default: dataset bawaan "+fixtures.DefaultFile+")")
+	force := fs.Bool("force", false, "tetap jalan walaupun midtrans.production aktif")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// Data contoh tidak boleh masuk ke database production tanpa sengaja
+	if a.cfg.Midtrans.Production && !*force {
+		return errors.New("midtrans.production is enabled, refusing to seed (use -force if this really is a development database)")
+	}
+
+	ds, err := fixtures.Default()
+	if *file != "" {
+		ds, err = fixtures.ReadFile(*file)
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := fixtures.Load(ctx, a.stores, ds)
+	if res != nil {
+		w := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "COLLECTION\tINSERTED\tSKIPPED")
+		for _, name := range []string{
+			store.CollectionCategories,
+			store.CollectionFacilities,
+			store.CollectionUsers,
+			store.CollectionCustomFacilities,
+			store.CollectionBoardingHouses,
+			store.CollectionRooms,
+			store.CollectionTransactions,
+		} {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", name, res.Inserted[name], res.Skipped[name])
+		}
+		w.Flush()
+	}
+	return err
+}
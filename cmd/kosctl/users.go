@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/helper"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var roles = []string{"user", "owner", "admin"}
+
+// createAdmin membuat admin pertama. Jika email sudah terdaftar, user
+// tersebut dijadikan admin tanpa mengubah password-nya.
+func createAdmin(ctx context.Context, a *app, args []string) error {
+	fs := flags("create-admin")
+	email := fs.String("email", "", "email admin")
+	name := fs.String("name", "", "nama lengkap admin")
+	password := fs.String("password", "", "password admin (default: dibaca dari stdin)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkEmail(*email); err != nil {
+		return err
+	}
+
+	existing, err := a.stores.Users.FindByEmail(ctx, *email)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	if existing != nil {
+		if err := a.stores.Users.Update(ctx, existing.UserID, bson.M{
+			"role":             "admin",
+			"is_role_assigned": true,
+			"updated_at":       time.Now(),
+		}); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "%s already exists, role changed from %q to \"admin\"\n", *email, existing.Role)
+		return nil
+	}
+
+	if strings.TrimSpace(*name) == "" {
+		return errors.New("-name is required for a new admin")
+	}
+	hash, err := readPassword(a, *password)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	user := models.User{
+		UserID:         primitive.NewObjectID(),
+		FullName:       *name,
+		Email:          *email,
+		Role:           "admin",
+		Password:       hash,
+		VerifiedEmail:  true, // Dibuat oleh operator, tidak perlu verifikasi
+		IsRoleAssigned: true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := a.stores.Users.Create(ctx, &user); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "admin %s created with id %s\n", user.Email, user.UserID.Hex())
+	return nil
+}
+
+func setPassword(ctx context.Context, a *app, args []string) error {
+	fs := flags("set-password")
+	email := fs.String("email", "", "email user")
+	password := fs.String("password", "", "password baru (default: dibaca dari stdin)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	user, err := findUser(ctx, a, *email)
+	if err != nil {
+		return err
+	}
+	hash, err := readPassword(a, *password)
+	if err != nil {
+		return err
+	}
+	if err := a.stores.Users.Update(ctx, user.UserID, bson.M{"password": hash, "updated_at": time.Now()}); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "password for %s updated\n", user.Email)
+	return nil
+}
+
+func setRole(ctx context.Context, a *app, args []string) error {
+	fs := flags("set-role")
+	email := fs.String("email", "", "email user")
+	role := fs.String("role", "", "role baru: user, owner atau admin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !contains(roles, *role) {
+		return fmt.Errorf("-role must be one of %s", strings.Join(roles, ", "))
+	}
+	user, err := findUser(ctx, a, *email)
+	if err != nil {
+		return err
+	}
+	if err := a.stores.Users.Update(ctx, user.UserID, bson.M{
+		"role":             *role,
+		"is_role_assigned": true,
+		"updated_at":       time.Now(),
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "role for %s changed from %q to %q\n", user.Email, user.Role, *role)
+	return nil
+}
+
+// resendVerification mengirim ulang email verifikasi dengan token baru;
+// link dari email sebelumnya tidak berlaku lagi.
+func resendVerification(ctx context.Context, a *app, args []string) error {
+	fs := flags("resend-verification")
+	email := fs.String("email", "", "email user")
+	all := fs.Bool("all", false, "kirim ke semua user yang belum verifikasi")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*email == "") == !*all {
+		return errors.New("use either -email or -all")
+	}
+
+	var users []models.User
+	if *all {
+		everyone, err := a.stores.Users.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, u := range everyone {
+			// User Google tidak punya password dan emailnya sudah diverifikasi Google
+			if !u.VerifiedEmail && u.Password != "" {
+				users = append(users, u)
+			}
+		}
+	} else {
+		user, err := findUser(ctx, a, *email)
+		if err != nil {
+			return err
+		}
+		if user.VerifiedEmail {
+			return fmt.Errorf("%s is already verified", user.Email)
+		}
+		users = append(users, *user)
+	}
+
+	var failed int
+	for _, user := range users {
+		if err := sendVerification(ctx, a, user); err != nil {
+			failed++
+			fmt.Fprintf(a.out, "%s: %v\n", user.Email, err)
+			continue
+		}
+		fmt.Fprintf(a.out, "%s: sent\n", user.Email)
+	}
+	fmt.Fprintf(a.out, "%d sent, %d failed\n", len(users)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d verification emails failed", failed)
+	}
+	return nil
+}
+
+func sendVerification(ctx context.Context, a *app, user models.User) error {
+	token := helper.RandomToken()
+	if err := a.stores.Users.SetVerificationToken(ctx, user.UserID, token, time.Now().Add(a.cfg.Auth.VerificationTTL)); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, a.cfg.Timeouts.SMTP)
+	defer cancel()
+	return helper.SendVerificationEmail(ctx, a.cfg.SMTP, user.Email, helper.VerificationLink(a.cfg.BaseURL, token), user.FullName)
+}
+
+func findUser(ctx context.Context, a *app, email string) (*models.User, error) {
+	if err := checkEmail(email); err != nil {
+		return nil, err
+	}
+	user, err := a.stores.Users.FindByEmail(ctx, email)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("no user with email %s", email)
+	}
+	return user, err
+}
+
+func checkEmail(email string) error {
+	if email == "" {
+		return errors.New("-email is required")
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return fmt.Errorf("-email %q is not a valid email address", email)
+	}
+	return nil
+}
+
+// readPassword memakai password dari flag, atau baris pertama stdin jika
+// kosong, lalu mengembalikan hash bcrypt-nya. Aturan panjang sama dengan
+// dto.RegisterRequest.
+func readPassword(a *app, password string) (string, error) {
+	if password == "" {
+		line, err := bufio.NewReader(a.in).ReadString('\n')
+		if err != nil && line == "" {
+			return "", errors.New("password is required: use -password or pipe it to stdin")
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if len(password) < 8 || len(password) > 72 {
+		return "", errors.New("password must be 8-72 characters")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// Command server menjalankan API KosConnect sebagai HTTP server biasa,
+// dengan router yang sama seperti entrypoint Vercel. Saat menerima SIGINT
+// atau SIGTERM, server berhenti menerima koneksi baru, menunggu request yang
+// sedang berjalan lalu menutup koneksi MongoDB.
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/migrations"
+	"github.com/organisasi/kosconnectbackend/server"
+	"github.com/organisasi/kosconnectbackend/store"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	// Load .env jika ada, digunakan hanya jika di run secara local
+	envErr := godotenv.Load()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(server.NewLogger(os.Stdout, cfg.LogLevel))
+	if envErr != nil {
+		slog.Info("no .env file found")
+	}
+
+	// Inisialisasi konfigurasi Midtrans
+	config.InitMidtransConfig(cfg.Midtrans)
+
+	// Connect to MongoDB
+	client, err := config.ConnectDB(cfg.Mongo)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := client.Disconnect(ctx); err != nil {
+			slog.Error("failed to disconnect from MongoDB", "error", err)
+			return
+		}
+		slog.Info("disconnected from MongoDB")
+	}()
+
+	if cfg.Mongo.MigrateOnStartup {
+		if _, err := migrations.Run(context.Background(), config.DB); err != nil {
+			return err
+		}
+	}
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           server.NewServer(cfg, store.NewMongo(config.DB, cfg.Timeouts.Database)),
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server is running", "port", cfg.Port)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	// Berhenti menerima koneksi baru dan tunggu request yang sedang berjalan
+	slog.Info("shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
+}
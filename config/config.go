@@ -1,9 +1,514 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
-func GetGitHubToken() string {
-	return os.Getenv("GH_ACCESS_TOKEN")
-}
\ No newline at end of file
+// Config berisi seluruh konfigurasi aplikasi. Nilainya diambil dari file
+// (opsional, lewat CONFIG_FILE) lalu ditimpa oleh environment variable.
+type Config struct {
+	Port           string   `yaml:"port"`
+	JWTSecret      string   `yaml:"jwt_secret"`
+	BaseURL        string   `yaml:"base_url"`     // URL publik backend, dipakai untuk link verifikasi
+	FrontendURL    string   `yaml:"frontend_url"` // Tujuan redirect setelah verifikasi dan login Google
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	LogLevel       string   `yaml:"log_level"` // debug, info, warn atau error
+
+	// IP atau CIDR reverse proxy yang X-Forwarded-For-nya dipercaya untuk
+	// menentukan IP client (rate limit, log). Kosong berarti tidak ada:
+	// IP client adalah alamat koneksi, header dari client diabaikan.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+
+	// Lama response untuk header Idempotency-Key disimpan dan bisa diputar ulang
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl"`
+
+	Auth       AuthConfig       `yaml:"auth"`
+	Server     ServerConfig     `yaml:"server"`
+	Timeouts   TimeoutConfig    `yaml:"timeouts"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Pagination PaginationConfig `yaml:"pagination"`
+	HTTPCache  HTTPCacheConfig  `yaml:"http_cache"`
+	Cache      CacheConfig      `yaml:"cache"`
+	Mongo      MongoConfig      `yaml:"mongo"`
+	Google     GoogleConfig     `yaml:"google"`
+	SMTP       SMTPConfig       `yaml:"smtp"`
+	GitHub     GitHubConfig     `yaml:"github"`
+	Midtrans   MidtransConfig   `yaml:"midtrans"`
+}
+
+// AuthConfig mengatur masa berlaku token login, link reset password dan
+// verifikasi email. Access token (JWT) dibuat pendek karena tidak bisa
+// ditarik sebelum kedaluwarsa selain lewat pengecekan session; refresh
+// token dipakai untuk mendapatkan yang baru.
+type AuthConfig struct {
+	AccessTokenTTL   time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL  time.Duration `yaml:"refresh_token_ttl"`  // Juga lama session tanpa aktivitas
+	PasswordResetTTL time.Duration `yaml:"password_reset_ttl"` // Masa berlaku link dari /auth/forgot-password
+
+	// RequireVerifiedEmail menolak login dengan password sebelum email
+	// diverifikasi. Login Google tidak terpengaruh.
+	RequireVerifiedEmail       bool          `yaml:"require_verified_email"`
+	VerificationTTL            time.Duration `yaml:"verification_ttl"`             // Masa berlaku link verifikasi email
+	VerificationResendInterval time.Duration `yaml:"verification_resend_interval"` // Jeda minimal antar email verifikasi per user
+}
+
+// ServerConfig mengatur timeout http.Server pada cmd/server. Durasi ditulis
+// seperti "30s" atau "2m".
+type ServerConfig struct {
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"` // Batas waktu menunggu request yang sedang berjalan saat SIGTERM
+}
+
+// TimeoutConfig membatasi lama setiap operasi keluar. Operasi yang melewati
+// batas ini dibalas 504 Gateway Timeout.
+type TimeoutConfig struct {
+	Database time.Duration `yaml:"database"` // Per query/aggregate MongoDB
+	Midtrans time.Duration `yaml:"midtrans"`
+	GitHub   time.Duration `yaml:"github"` // Per upload gambar
+	SMTP     time.Duration `yaml:"smtp"`
+}
+
+// RateLimitConfig membatasi jumlah request per client: per user_id jika
+// request membawa JWT, selain itu per IP. Setiap grup punya bucket sendiri.
+type RateLimitConfig struct {
+	Enabled bool      `yaml:"enabled"`
+	Auth    RateLimit `yaml:"auth"`    // /auth/login, /auth/register, /auth/refresh, /auth/resend-verification, /auth/*-password, /auth/googleauth
+	Booking RateLimit `yaml:"booking"` // POST /api/transaction
+}
+
+// RateLimit adalah token bucket: Requests token diisi ulang merata selama
+// Per, dengan isi maksimal Burst (0 berarti sama dengan Requests).
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+}
+
+// PaginationConfig mengatur ?limit= pada endpoint list: DefaultLimit
+// dipakai jika limit tidak dikirim, limit di atas MaxLimit ditolak.
+type PaginationConfig struct {
+	DefaultLimit int `yaml:"default_limit"`
+	MaxLimit     int `yaml:"max_limit"`
+}
+
+// HTTPCacheConfig berisi header Cache-Control untuk endpoint publik yang
+// memakai ETag. Kosong berarti "no-cache" (selalu revalidasi dengan
+// If-None-Match). s-maxage hanya berlaku di edge cache Vercel.
+type HTTPCacheConfig struct {
+	LandingPage   string `yaml:"landing_page"`   // GET /api/rooms/home
+	RoomPage      string `yaml:"room_page"`      // GET /api/rooms/:id/pages
+	BoardingHouse string `yaml:"boarding_house"` // GET /api/boardingHouses/:id
+	Categories    string `yaml:"categories"`     // GET /api/categories/
+}
+
+// CacheConfig mengatur cache in-process (LRU) untuk agregasi landing page
+// dan detail kamar/kos. Cache dikosongkan setiap ada perubahan data lewat
+// API di instance yang sama; instance lain baru melihatnya setelah TTL.
+type CacheConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Size    int           `yaml:"size"` // Jumlah entry maksimal
+	TTL     time.Duration `yaml:"ttl"`
+}
+
+type MongoConfig struct {
+	URI      string `yaml:"uri"`
+	Database string `yaml:"database"`
+	// Jalankan migrasi (index dan backfill data) yang belum tercatat saat
+	// startup. Matikan jika migrasi dijalankan terpisah lewat kosctl migrate.
+	MigrateOnStartup bool `yaml:"migrate_on_startup"`
+}
+
+type GoogleConfig struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	RedirectURL  string `yaml:"redirect_url"`
+
+	// StateTTL adalah batas waktu antara /auth/google/login dan
+	// /auth/callback (masa berlaku cookie state dan PKCE verifier).
+	StateTTL time.Duration `yaml:"state_ttl"`
+	// CertsURL adalah JWKS untuk memverifikasi ID token di /auth/googleauth.
+	CertsURL string `yaml:"certs_url"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Sender   string `yaml:"sender"`
+	Password string `yaml:"password"`
+}
+
+// Addr mengembalikan alamat host:port untuk smtp.SendMail
+func (s SMTPConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", s.Host, s.Port)
+}
+
+// GitHubConfig menentukan repository tempat gambar kos dan kamar diupload
+type GitHubConfig struct {
+	Token       string `yaml:"token"`
+	Org         string `yaml:"org"`
+	Repo        string `yaml:"repo"`
+	AuthorName  string `yaml:"author_name"`
+	AuthorEmail string `yaml:"author_email"`
+}
+
+type MidtransConfig struct {
+	ServerKey  string `yaml:"server_key"`
+	Production bool   `yaml:"production"` // false = Sandbox
+}
+
+// Default mengembalikan konfigurasi dengan nilai yang selama ini dipakai di production
+func Default() *Config {
+	return &Config{
+		Port:        "8080",
+		LogLevel:    "info",
+		BaseURL:     "https://kosconnect-server.vercel.app",
+		FrontendURL: "https://kosconnect.github.io",
+		AllowedOrigins: []string{
+			"https://kosconnect.github.io",
+			"http://localhost:8080",                // Testing dengan localhost
+			"https://accounts.google.com",          // Google OAuth origin
+			"https://kosconnect-server.vercel.app", // Backend utama
+			"http://127.0.0.1:5504",                //go live fe
+			"http://127.0.0.1:5500",                //alternative go live fe
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:   15 * time.Minute,
+			RefreshTokenTTL:  7 * 24 * time.Hour, // Sama dengan cookie authToken sebelumnya
+			PasswordResetTTL: time.Hour,
+
+			RequireVerifiedEmail:       true,
+			VerificationTTL:            24 * time.Hour,
+			VerificationResendInterval: time.Minute,
+		},
+		Server: ServerConfig{
+			ReadTimeout:       30 * time.Second,
+			ReadHeaderTimeout: 10 * time.Second,
+			WriteTimeout:      60 * time.Second, // Upload gambar ke GitHub bisa lama
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   15 * time.Second,
+		},
+		Timeouts: TimeoutConfig{
+			Database: 10 * time.Second,
+			Midtrans: 15 * time.Second,
+			GitHub:   30 * time.Second,
+			SMTP:     15 * time.Second,
+		},
+		IdempotencyTTL: 24 * time.Hour,
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Auth:    RateLimit{Requests: 10, Per: time.Minute, Burst: 5},
+			Booking: RateLimit{Requests: 5, Per: time.Minute},
+		},
+		Pagination: PaginationConfig{DefaultLimit: 20, MaxLimit: 100},
+		HTTPCache: HTTPCacheConfig{
+			LandingPage:   "public, max-age=0, s-maxage=60, stale-while-revalidate=300",
+			RoomPage:      "public, max-age=0, s-maxage=60, stale-while-revalidate=300",
+			BoardingHouse: "public, max-age=0, s-maxage=60, stale-while-revalidate=300",
+			Categories:    "public, max-age=300, stale-while-revalidate=3600",
+		},
+		Cache:  CacheConfig{Enabled: true, Size: 1000, TTL: 30 * time.Second},
+		Mongo:  MongoConfig{Database: "kosconnect", MigrateOnStartup: true},
+		Google: GoogleConfig{StateTTL: 10 * time.Minute, CertsURL: "https://www.googleapis.com/oauth2/v3/certs"},
+		SMTP: SMTPConfig{
+			Host:   "smtp.gmail.com",
+			Port:   587,
+			Sender: "kosconnect2@gmail.com",
+		},
+		GitHub: GitHubConfig{
+			Org:         "kosconnect",
+			Repo:        "img",
+			AuthorName:  "Balqis Rosa Sekamayang",
+			AuthorEmail: "balqisrosasekamayang@gmail.com",
+		},
+	}
+}
+
+// Load membaca konfigurasi dari CONFIG_FILE (YAML atau JSON, opsional) dan
+// environment variable, lalu memvalidasinya. Error dikembalikan jika ada
+// nilai wajib yang kosong sehingga aplikasi gagal saat startup.
+func Load() (*Config, error) {
+	cfg := Default()
+	if file := os.Getenv("CONFIG_FILE"); file != "" {
+		if err := cfg.loadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+	if cfg.Google.RedirectURL == "" {
+		cfg.Google.RedirectURL = strings.TrimSuffix(cfg.BaseURL, "/") + "/auth/callback"
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) loadFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("config: read %s: %w", file, err)
+	}
+	// JSON adalah subset YAML, jadi keduanya dibaca dengan parser YAML
+	// (termasuk durasi berbentuk string seperti "30s")
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return fmt.Errorf("config: unsupported file type %s (use .yaml, .yml or .json)", file)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("config: parse %s: %w", file, err)
+	}
+	return nil
+}
+
+// loadEnv menimpa nilai dari file dengan environment variable yang di-set.
+// Nama variabel lama (MONGOSTRING, GH_ACCESS_TOKEN, APP_PASSWORD, ...) tetap dipakai.
+func (cfg *Config) loadEnv() error {
+	setString(&cfg.Port, "PORT")
+	setString(&cfg.JWTSecret, "JWT_SECRET")
+	setString(&cfg.BaseURL, "BASE_URL")
+	setString(&cfg.FrontendURL, "FRONTEND_URL")
+	setString(&cfg.LogLevel, "LOG_LEVEL")
+	setList(&cfg.AllowedOrigins, "CORS_ALLOWED_ORIGINS")
+	setList(&cfg.TrustedProxies, "TRUSTED_PROXIES")
+
+	for _, d := range []struct {
+		dst *time.Duration
+		key string
+	}{
+		{&cfg.Auth.AccessTokenTTL, "ACCESS_TOKEN_TTL"},
+		{&cfg.Auth.RefreshTokenTTL, "REFRESH_TOKEN_TTL"},
+		{&cfg.Auth.PasswordResetTTL, "PASSWORD_RESET_TTL"},
+		{&cfg.Auth.VerificationTTL, "VERIFICATION_TTL"},
+		{&cfg.Auth.VerificationResendInterval, "VERIFICATION_RESEND_INTERVAL"},
+		{&cfg.Google.StateTTL, "GOOGLE_STATE_TTL"},
+		{&cfg.Server.ReadTimeout, "SERVER_READ_TIMEOUT"},
+		{&cfg.Server.ReadHeaderTimeout, "SERVER_READ_HEADER_TIMEOUT"},
+		{&cfg.Server.WriteTimeout, "SERVER_WRITE_TIMEOUT"},
+		{&cfg.Server.IdleTimeout, "SERVER_IDLE_TIMEOUT"},
+		{&cfg.Server.ShutdownTimeout, "SERVER_SHUTDOWN_TIMEOUT"},
+		{&cfg.Timeouts.Database, "DB_TIMEOUT"},
+		{&cfg.Timeouts.Midtrans, "MIDTRANS_TIMEOUT"},
+		{&cfg.Timeouts.GitHub, "GITHUB_TIMEOUT"},
+		{&cfg.Timeouts.SMTP, "SMTP_TIMEOUT"},
+		{&cfg.IdempotencyTTL, "IDEMPOTENCY_TTL"},
+		{&cfg.Cache.TTL, "CACHE_TTL"},
+	} {
+		if err := setDuration(d.dst, d.key); err != nil {
+			return err
+		}
+	}
+
+	if require := os.Getenv("REQUIRE_VERIFIED_EMAIL"); require != "" {
+		b, err := strconv.ParseBool(require)
+		if err != nil {
+			return fmt.Errorf("config: REQUIRE_VERIFIED_EMAIL must be true or false: %w", err)
+		}
+		cfg.Auth.RequireVerifiedEmail = b
+	}
+	if enabled := os.Getenv("RATE_LIMIT_ENABLED"); enabled != "" {
+		b, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return fmt.Errorf("config: RATE_LIMIT_ENABLED must be true or false: %w", err)
+		}
+		cfg.RateLimit.Enabled = b
+	}
+
+	if enabled := os.Getenv("CACHE_ENABLED"); enabled != "" {
+		b, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return fmt.Errorf("config: CACHE_ENABLED must be true or false: %w", err)
+		}
+		cfg.Cache.Enabled = b
+	}
+	if size := os.Getenv("CACHE_SIZE"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil {
+			return fmt.Errorf("config: CACHE_SIZE must be a number: %w", err)
+		}
+		cfg.Cache.Size = n
+	}
+
+	setString(&cfg.HTTPCache.LandingPage, "HTTP_CACHE_LANDING_PAGE")
+	setString(&cfg.HTTPCache.RoomPage, "HTTP_CACHE_ROOM_PAGE")
+	setString(&cfg.HTTPCache.BoardingHouse, "HTTP_CACHE_BOARDING_HOUSE")
+	setString(&cfg.HTTPCache.Categories, "HTTP_CACHE_CATEGORIES")
+
+	setString(&cfg.Mongo.URI, "MONGOSTRING")
+	setString(&cfg.Mongo.Database, "MONGO_DATABASE")
+	if migrate := os.Getenv("MONGO_MIGRATE_ON_STARTUP"); migrate != "" {
+		b, err := strconv.ParseBool(migrate)
+		if err != nil {
+			return fmt.Errorf("config: MONGO_MIGRATE_ON_STARTUP must be true or false: %w", err)
+		}
+		cfg.Mongo.MigrateOnStartup = b
+	}
+
+	setString(&cfg.Google.ClientID, "GOOGLE_CLIENT_ID")
+	setString(&cfg.Google.ClientSecret, "GOOGLE_CLIENT_SECRET")
+	setString(&cfg.Google.RedirectURL, "GOOGLE_REDIRECT_URL")
+	setString(&cfg.Google.CertsURL, "GOOGLE_CERTS_URL")
+
+	setString(&cfg.SMTP.Host, "SMTP_HOST")
+	setString(&cfg.SMTP.Sender, "SMTP_SENDER")
+	setString(&cfg.SMTP.Password, "APP_PASSWORD")
+	if port := os.Getenv("SMTP_PORT"); port != "" {
+		n, err := strconv.Atoi(port)
+		if err != nil {
+			return fmt.Errorf("config: SMTP_PORT must be a number: %w", err)
+		}
+		cfg.SMTP.Port = n
+	}
+
+	setString(&cfg.GitHub.Token, "GH_ACCESS_TOKEN")
+	setString(&cfg.GitHub.Org, "GH_ORG")
+	setString(&cfg.GitHub.Repo, "GH_REPO")
+	setString(&cfg.GitHub.AuthorName, "GH_AUTHOR_NAME")
+	setString(&cfg.GitHub.AuthorEmail, "GH_AUTHOR_EMAIL")
+
+	setString(&cfg.Midtrans.ServerKey, "MIDTRANS_SERVER_KEY")
+	if production := os.Getenv("MIDTRANS_PRODUCTION"); production != "" {
+		b, err := strconv.ParseBool(production)
+		if err != nil {
+			return fmt.Errorf("config: MIDTRANS_PRODUCTION must be true or false: %w", err)
+		}
+		cfg.Midtrans.Production = b
+	}
+	return nil
+}
+
+func setString(dst *string, key string) {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		*dst = v
+	}
+}
+
+// setList membaca daftar yang dipisah koma; item kosong dilewati.
+func setList(dst *[]string, key string) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	*dst = nil
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*dst = append(*dst, item)
+		}
+	}
+}
+
+func setDuration(dst *time.Duration, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("config: %s must be a duration like 30s: %w", key, err)
+	}
+	*dst = d
+	return nil
+}
+
+// Validate memastikan nilai wajib terisi dan URL valid. Semua masalah
+// dilaporkan sekaligus agar mudah diperbaiki.
+func (cfg *Config) Validate() error {
+	var errs []error
+	for _, field := range []struct{ name, value string }{
+		{"jwt_secret (JWT_SECRET)", cfg.JWTSecret},
+		{"mongo.uri (MONGOSTRING)", cfg.Mongo.URI},
+		{"mongo.database (MONGO_DATABASE)", cfg.Mongo.Database},
+		{"midtrans.server_key (MIDTRANS_SERVER_KEY)", cfg.Midtrans.ServerKey},
+		{"smtp.host (SMTP_HOST)", cfg.SMTP.Host},
+		{"smtp.sender (SMTP_SENDER)", cfg.SMTP.Sender},
+		{"github.org (GH_ORG)", cfg.GitHub.Org},
+		{"github.repo (GH_REPO)", cfg.GitHub.Repo},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			errs = append(errs, fmt.Errorf("%s is required", field.name))
+		}
+	}
+	for _, field := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"auth.access_token_ttl (ACCESS_TOKEN_TTL)", cfg.Auth.AccessTokenTTL},
+		{"auth.refresh_token_ttl (REFRESH_TOKEN_TTL)", cfg.Auth.RefreshTokenTTL},
+		{"auth.password_reset_ttl (PASSWORD_RESET_TTL)", cfg.Auth.PasswordResetTTL},
+		{"auth.verification_ttl (VERIFICATION_TTL)", cfg.Auth.VerificationTTL},
+		{"google.state_ttl (GOOGLE_STATE_TTL)", cfg.Google.StateTTL},
+		{"server.shutdown_timeout (SERVER_SHUTDOWN_TIMEOUT)", cfg.Server.ShutdownTimeout},
+		{"timeouts.database (DB_TIMEOUT)", cfg.Timeouts.Database},
+		{"timeouts.midtrans (MIDTRANS_TIMEOUT)", cfg.Timeouts.Midtrans},
+		{"timeouts.github (GITHUB_TIMEOUT)", cfg.Timeouts.GitHub},
+		{"timeouts.smtp (SMTP_TIMEOUT)", cfg.Timeouts.SMTP},
+		{"idempotency_ttl (IDEMPOTENCY_TTL)", cfg.IdempotencyTTL},
+	} {
+		if field.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", field.name))
+		}
+	}
+	if cfg.RateLimit.Enabled {
+		for _, limit := range []struct {
+			name string
+			RateLimit
+		}{
+			{"rate_limit.auth", cfg.RateLimit.Auth},
+			{"rate_limit.booking", cfg.RateLimit.Booking},
+		} {
+			if limit.Requests <= 0 || limit.Per <= 0 || limit.Burst < 0 {
+				errs = append(errs, fmt.Errorf("%s needs positive requests and per, got %d per %s", limit.name, limit.Requests, limit.Per))
+			}
+		}
+	}
+	if p := cfg.Pagination; p.DefaultLimit < 1 || p.MaxLimit < p.DefaultLimit {
+		errs = append(errs, fmt.Errorf("pagination needs 1 <= default_limit <= max_limit, got %d and %d", p.DefaultLimit, p.MaxLimit))
+	}
+	if c := cfg.Cache; c.Enabled && (c.Size <= 0 || c.TTL <= 0) {
+		errs = append(errs, fmt.Errorf("cache needs positive size (CACHE_SIZE) and ttl (CACHE_TTL), got %d and %s", c.Size, c.TTL))
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		errs = append(errs, fmt.Errorf("log_level (LOG_LEVEL) must be debug, info, warn or error, got %q", cfg.LogLevel))
+	}
+	if cfg.SMTP.Port <= 0 {
+		errs = append(errs, errors.New("smtp.port (SMTP_PORT) must be positive"))
+	}
+	for _, field := range []struct{ name, value string }{
+		{"base_url (BASE_URL)", cfg.BaseURL},
+		{"frontend_url (FRONTEND_URL)", cfg.FrontendURL},
+		{"google.redirect_url (GOOGLE_REDIRECT_URL)", cfg.Google.RedirectURL},
+		{"google.certs_url (GOOGLE_CERTS_URL)", cfg.Google.CertsURL},
+	} {
+		if u, err := url.Parse(field.value); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s must be an absolute URL, got %q", field.name, field.value))
+		}
+	}
+	for _, proxy := range cfg.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				errs = append(errs, fmt.Errorf("trusted_proxies (TRUSTED_PROXIES) must be IPs or CIDRs, got %q", proxy))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("config: invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv mengosongkan environment selama test; Load menganggap variabel
+// kosong sama dengan tidak di-set.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		t.Setenv(key, "")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// validConfig mengisi nilai wajib yang tidak punya default.
+func validConfig() *Config {
+	cfg := Default()
+	cfg.JWTSecret = "secret"
+	cfg.Mongo.URI = "mongodb://localhost:27017"
+	cfg.Midtrans.ServerKey = "server-key"
+	cfg.Google.RedirectURL = cfg.BaseURL + "/auth/callback"
+	return cfg
+}
+
+func TestLoad(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.yaml", `
+port: "9000"
+jwt_secret: dari-file
+base_url: https://api.example.com/
+log_level: debug
+allowed_origins: [https://file.example.com]
+timeouts:
+  database: 3s
+mongo:
+  uri: mongodb://file:27017
+  database: dari-file
+midtrans:
+  server_key: file-key
+`))
+	// Environment menimpa file; yang tidak di-set tetap dari file atau default
+	t.Setenv("JWT_SECRET", "dari-env")
+	t.Setenv("MONGO_DATABASE", "dari-env")
+	t.Setenv("DB_TIMEOUT", "5s")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com, ,https://b.example.com")
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("CACHE_ENABLED", "false")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ name, got, want string }{
+		{"port", cfg.Port, "9000"},
+		{"jwt_secret", cfg.JWTSecret, "dari-env"},
+		{"log_level", cfg.LogLevel, "debug"},
+		{"mongo.uri", cfg.Mongo.URI, "mongodb://file:27017"},
+		{"mongo.database", cfg.Mongo.Database, "dari-env"},
+		{"midtrans.server_key", cfg.Midtrans.ServerKey, "file-key"},
+		{"smtp.host", cfg.SMTP.Host, "smtp.gmail.com"},
+		{"allowed_origins", strings.Join(cfg.AllowedOrigins, " "), "https://a.example.com https://b.example.com"},
+		// Tanpa GOOGLE_REDIRECT_URL, redirect diturunkan dari base_url
+		{"google.redirect_url", cfg.Google.RedirectURL, "https://api.example.com/auth/callback"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.name, tc.got, tc.want)
+		}
+	}
+	if cfg.Timeouts.Database != 5*time.Second || cfg.Timeouts.SMTP != 15*time.Second {
+		t.Errorf("timeouts = %+v, want database from env and smtp from default", cfg.Timeouts)
+	}
+	if cfg.SMTP.Port != 2525 || cfg.Cache.Enabled {
+		t.Errorf("smtp.port %d, cache.enabled %v; want 2525, false", cfg.SMTP.Port, cfg.Cache.Enabled)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.json", `{
+		"jwt_secret": "json", "mongo": {"uri": "mongodb://json"},
+		"midtrans": {"server_key": "k"}, "idempotency_ttl": "2h"
+	}`))
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.JWTSecret != "json" || cfg.IdempotencyTTL != 2*time.Hour {
+		t.Errorf("jwt_secret %q, idempotency_ttl %s", cfg.JWTSecret, cfg.IdempotencyTTL)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"missing file", map[string]string{"CONFIG_FILE": filepath.Join(t.TempDir(), "missing.yaml")}, "read"},
+		{"unsupported file", map[string]string{"CONFIG_FILE": writeFile(t, "config.toml", "port = 1")}, "unsupported file type"},
+		{"invalid yaml", map[string]string{"CONFIG_FILE": writeFile(t, "config.yaml", "port: [")}, "parse"},
+		{"invalid duration", map[string]string{"DB_TIMEOUT": "lama"}, "DB_TIMEOUT"},
+		{"invalid bool", map[string]string{"RATE_LIMIT_ENABLED": "ya"}, "RATE_LIMIT_ENABLED"},
+		{"invalid number", map[string]string{"SMTP_PORT": "smtp"}, "SMTP_PORT"},
+		{"missing required", map[string]string{}, "jwt_secret (JWT_SECRET) is required"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clearEnv(t)
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Load() error = %v, want it to mention %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("valid config: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		change func(*Config)
+		want   string
+	}{
+		{"jwt secret", func(c *Config) { c.JWTSecret = " " }, "jwt_secret (JWT_SECRET) is required"},
+		{"mongo uri", func(c *Config) { c.Mongo.URI = "" }, "mongo.uri (MONGOSTRING) is required"},
+		{"mongo database", func(c *Config) { c.Mongo.Database = "" }, "mongo.database (MONGO_DATABASE) is required"},
+		{"midtrans key", func(c *Config) { c.Midtrans.ServerKey = "" }, "midtrans.server_key (MIDTRANS_SERVER_KEY) is required"},
+		{"smtp host", func(c *Config) { c.SMTP.Host = "" }, "smtp.host (SMTP_HOST) is required"},
+		{"smtp sender", func(c *Config) { c.SMTP.Sender = "" }, "smtp.sender (SMTP_SENDER) is required"},
+		{"github org", func(c *Config) { c.GitHub.Org = "" }, "github.org (GH_ORG) is required"},
+		{"github repo", func(c *Config) { c.GitHub.Repo = "" }, "github.repo (GH_REPO) is required"},
+		{"access token ttl", func(c *Config) { c.Auth.AccessTokenTTL = 0 }, "auth.access_token_ttl (ACCESS_TOKEN_TTL) must be positive"},
+		{"refresh token ttl", func(c *Config) { c.Auth.RefreshTokenTTL = -time.Second }, "auth.refresh_token_ttl (REFRESH_TOKEN_TTL) must be positive"},
+		{"password reset ttl", func(c *Config) { c.Auth.PasswordResetTTL = 0 }, "auth.password_reset_ttl (PASSWORD_RESET_TTL) must be positive"},
+		{"verification ttl", func(c *Config) { c.Auth.VerificationTTL = 0 }, "auth.verification_ttl (VERIFICATION_TTL) must be positive"},
+		{"google state ttl", func(c *Config) { c.Google.StateTTL = 0 }, "google.state_ttl (GOOGLE_STATE_TTL) must be positive"},
+		{"shutdown timeout", func(c *Config) { c.Server.ShutdownTimeout = 0 }, "server.shutdown_timeout (SERVER_SHUTDOWN_TIMEOUT) must be positive"},
+		{"database timeout", func(c *Config) { c.Timeouts.Database = 0 }, "timeouts.database (DB_TIMEOUT) must be positive"},
+		{"midtrans timeout", func(c *Config) { c.Timeouts.Midtrans = 0 }, "timeouts.midtrans (MIDTRANS_TIMEOUT) must be positive"},
+		{"github timeout", func(c *Config) { c.Timeouts.GitHub = 0 }, "timeouts.github (GITHUB_TIMEOUT) must be positive"},
+		{"smtp timeout", func(c *Config) { c.Timeouts.SMTP = 0 }, "timeouts.smtp (SMTP_TIMEOUT) must be positive"},
+		{"idempotency ttl", func(c *Config) { c.IdempotencyTTL = 0 }, "idempotency_ttl (IDEMPOTENCY_TTL) must be positive"},
+		{"auth rate limit", func(c *Config) { c.RateLimit.Auth.Requests = 0 }, "rate_limit.auth needs positive requests and per"},
+		{"booking rate limit", func(c *Config) { c.RateLimit.Booking.Per = 0 }, "rate_limit.booking needs positive requests and per"},
+		{"negative burst", func(c *Config) { c.RateLimit.Auth.Burst = -1 }, "rate_limit.auth needs positive requests and per"},
+		{"pagination default", func(c *Config) { c.Pagination.DefaultLimit = 0 }, "pagination needs 1 <= default_limit <= max_limit"},
+		{"pagination max", func(c *Config) { c.Pagination.MaxLimit = c.Pagination.DefaultLimit - 1 }, "pagination needs 1 <= default_limit <= max_limit"},
+		{"cache size", func(c *Config) { c.Cache.Size = 0 }, "cache needs positive size"},
+		{"cache ttl", func(c *Config) { c.Cache.TTL = 0 }, "cache needs positive size"},
+		{"log level", func(c *Config) { c.LogLevel = "verbose" }, "log_level (LOG_LEVEL) must be debug, info, warn or error"},
+		{"smtp port", func(c *Config) { c.SMTP.Port = 0 }, "smtp.port (SMTP_PORT) must be positive"},
+		{"base url", func(c *Config) { c.BaseURL = "kosconnect-server.vercel.app" }, "base_url (BASE_URL) must be an absolute URL"},
+		{"frontend url", func(c *Config) { c.FrontendURL = "/" }, "frontend_url (FRONTEND_URL) must be an absolute URL"},
+		{"redirect url", func(c *Config) { c.Google.RedirectURL = "" }, "google.redirect_url (GOOGLE_REDIRECT_URL) must be an absolute URL"},
+		{"certs url", func(c *Config) { c.Google.CertsURL = "certs" }, "google.certs_url (GOOGLE_CERTS_URL) must be an absolute URL"},
+		{"trusted proxy", func(c *Config) { c.TrustedProxies = []string{"10.0.0.0/8", "proxy.local"} }, `trusted_proxies (TRUSTED_PROXIES) must be IPs or CIDRs, got "proxy.local"`},
+	} {
+		cfg := validConfig()
+		tc.change(cfg)
+		err := cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: Validate() = %v, want it to mention %q", tc.name, err, tc.want)
+		}
+	}
+
+	// Rate limit dan cache yang dimatikan tidak divalidasi
+	cfg := validConfig()
+	cfg.RateLimit = RateLimitConfig{}
+	cfg.Cache = CacheConfig{}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("disabled rate limit and cache: %v", err)
+	}
+
+	// Semua masalah dilaporkan sekaligus
+	cfg = validConfig()
+	cfg.JWTSecret, cfg.SMTP.Port = "", 0
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "jwt_secret") || !strings.Contains(err.Error(), "smtp.port") {
+		t.Errorf("Validate() = %v, want both problems reported", err)
+	}
+}
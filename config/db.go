@@ -2,8 +2,8 @@ package config
 
 import (
 	"context"
-	"log"
-	"os"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -12,21 +12,18 @@ import (
 
 var DB *mongo.Database
 
-func ConnectDB() {
-	mongoURI := os.Getenv("MONGOSTRING")
-	client, err := mongo.NewClient(options.Client().ApplyURI(mongoURI))
-	if err != nil {
-		log.Fatal("Failed to create MongoDB client:", err)
-	}
-
+// ConnectDB membuka koneksi MongoDB dan mengisi DB. Client dikembalikan agar
+// pemanggil bisa memanggil Disconnect saat shutdown.
+func ConnectDB(cfg MongoConfig) (*mongo.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	err = client.Connect(ctx)
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.URI))
 	if err != nil {
-		log.Fatal("Failed to connect to MongoDB:", err)
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
 
-	DB = client.Database("kosconnect")
-	log.Println("Connected to MongoDB!")
+	DB = client.Database(cfg.Database)
+	slog.Info("connected to MongoDB", "database", cfg.Database)
+	return client, nil
 }
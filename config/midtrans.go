@@ -1,10 +1,12 @@
 package config
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/midtrans/midtrans-go/snap"
-	"os"
 )
 
 // Inisialisasi SnapClient dan CoreAPIClient
@@ -14,16 +16,39 @@ var (
 )
 
 // InitMidtransConfig menginisialisasi konfigurasi Midtrans
-func InitMidtransConfig() {
-	// Mengambil Server Key dari environment variable
-	serverKey := os.Getenv("MIDTRANS_SERVER_KEY")
-
-	// Pastikan serverKey tidak kosong
-	if serverKey == "" {
-		panic("MIDTRANS_SERVER_KEY environment variable is not set")
+// Server key sudah divalidasi oleh Config.Validate
+func InitMidtransConfig(cfg MidtransConfig) {
+	// Sandbox untuk testing, Production jika midtrans.production = true
+	env := midtrans.Sandbox
+	if cfg.Production {
+		env = midtrans.Production
 	}
 
 	// Inisialisasi SnapClient dan CoreAPIClient dengan Server Key dan Environment
-	SnapClient.New(serverKey, midtrans.Sandbox) // Gunakan Sandbox untuk testing
-	CoreAPIClient.New(serverKey, midtrans.Sandbox)
+	SnapClient.New(cfg.ServerKey, env)
+	CoreAPIClient.New(cfg.ServerKey, env)
+}
+
+// CreateSnapTransaction memanggil Snap API dan berhenti ketika ctx selesai.
+// midtrans-go tidak memakai ConfigOptions.Ctx (hasil req.WithContext dibuang),
+// jadi ctx dipasang lewat transport HTTP milik salinan SnapClient.
+func CreateSnapTransaction(ctx context.Context, req *snap.Request) (*snap.Response, *midtrans.Error) {
+	client := SnapClient
+	client.HttpClient = &midtrans.HttpClientImplementation{
+		HttpClient: &http.Client{
+			Timeout:   midtrans.DefaultHttpTimeout,
+			Transport: contextTransport{ctx: ctx, base: http.DefaultTransport},
+		},
+		Logger: midtrans.GetDefaultLogger(client.Env),
+	}
+	return client.CreateTransaction(req)
+}
+
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
 }
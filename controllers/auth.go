@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"context"
-	"crypto/rand"
 	// "os/user"
 
 	// "fmt"
@@ -12,174 +11,246 @@ import (
 	// "os/user"
 
 	// "regexp"
-	"encoding/base64"
 	"encoding/json"
-	"os"
+	"errors"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/dto"
+	"github.com/organisasi/kosconnectbackend/googleid"
 	"github.com/organisasi/kosconnectbackend/helper"
+	"github.com/organisasi/kosconnectbackend/middlewares"
 	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 )
 
-// Secret key for signing JWTs
-var jwtSecret = []byte("your_secret_key")
-
-func generateToken(userID primitive.ObjectID, role string) (string, error) {
+// generateToken membuat access token JWT untuk session sessionID yang
+// ditandatangani dengan secret dari config (lihat auth.access_token_ttl)
+func (ctrl *Controller) generateToken(userID primitive.ObjectID, role string, sessionID primitive.ObjectID) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID.Hex(),
 		"role":    role,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(), // Token expires in 24 hours
-		"iat":     time.Now().Unix(),                     // Issued at
+		"sid":     sessionID.Hex(),                                        // Dicek JWTAuthMiddleware, dicabut saat logout
+		"exp":     time.Now().Add(ctrl.Config.Auth.AccessTokenTTL).Unix(), // Token expires
+		"iat":     time.Now().Unix(),                                      // Issued at
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return token.SignedString([]byte(ctrl.Config.JWTSecret))
 }
 
 // Register handles user registration SMTP
-func Register(c *gin.Context) {
-	var user models.User
-
-	// Validasi input JSON
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+func (ctrl *Controller) Register(c *gin.Context) {
+	var req dto.RegisterRequest
+	if !bind(c, &req, binding.JSON) {
 		return
 	}
 
 	// Validasi email yang sudah terdaftar
-	collection := config.DB.Collection("users")
-	emailExists := collection.FindOne(context.TODO(), bson.M{"email": user.Email}).Err() == nil
+	_, err := ctrl.Store.Users.FindByEmail(c.Request.Context(), req.Email)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to register user", err)
+		return
+	}
+	emailExists := err == nil
 	if emailExists {
-		c.JSON(http.StatusConflict, gin.H{"error": "Email already in use"})
+		response.Fail(c, http.StatusConflict, response.CodeEmailAlreadyExists, "Email already in use")
 		return
 	}
 
 	// Hash password
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
-	user.Password = string(hashedPassword)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to hash password", err)
+		return
+	}
 
 	// Set default values
-	user.UserID = primitive.NewObjectID()
-	user.CreatedAt = time.Now()
-	user.UpdatedAt = time.Now()
-	user.VerifiedEmail = false  // Email belum diverifikasi
-	user.IsRoleAssigned = false // Default untuk role
+	user := models.User{
+		UserID:         primitive.NewObjectID(),
+		FullName:       req.FullName,
+		Email:          req.Email,
+		PhoneNumber:    req.PhoneNumber,
+		Password:       string(hashedPassword),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+		VerifiedEmail:  false, // Email belum diverifikasi
+		IsRoleAssigned: false, // Default untuk role
+	}
 
 	// Generate verification token
-	verifyToken := generateVerificationToken()
+	verifyToken := helper.RandomToken()
+	expiresAt := user.CreatedAt.Add(ctrl.Config.Auth.VerificationTTL)
 
 	// Menambahkan token verifikasi ke user
 	user.VerificationToken = verifyToken
+	user.VerificationExpiresAt = &expiresAt
+	user.VerificationSentAt = &user.CreatedAt
 
 	// Simpan user ke database
-	_, err := collection.InsertOne(context.TODO(), user)
+	err = ctrl.Store.Users.Create(c.Request.Context(), &user)
+	if errors.Is(err, store.ErrDuplicate) {
+		// Email didaftarkan bersamaan oleh request lain, ditolak unique index
+		response.Fail(c, http.StatusConflict, response.CodeEmailAlreadyExists, "Email already in use")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to register user", err)
 		return
 	}
 
 	// Kirim email verifikasi
-	verificationLink := "https://kosconnect-server.vercel.app/auth/verify?token=" + verifyToken
-	err = helper.SendVerificationEmail(user.Email, verificationLink, user.FullName)
+	verificationLink := helper.VerificationLink(ctrl.Config.BaseURL, verifyToken)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctrl.Config.Timeouts.SMTP)
+	defer cancel()
+	err = helper.SendVerificationEmail(ctx, ctrl.Config.SMTP, user.Email, verificationLink, user.FullName)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
+		respondError(c, http.StatusInternalServerError, response.CodeEmailFailed, "Failed to send verification email", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Registration successful. Please check your email to verify your account.",
-	})
+	response.Message(c, http.StatusOK, nil, "Registration successful. Please check your email to verify your account.")
 }
 
-// Fungsi untuk membuat token verifikasi unik
-func generateVerificationToken() string {
-	bytes := make([]byte, 32)                       // Membuat byte array dengan panjang 32
-	rand.Read(bytes)                                // Mengisi byte array dengan nilai acak
-	return base64.URLEncoding.EncodeToString(bytes) // Mengubah byte array menjadi string URL-safe
-}
+// VerifyEmail dibuka dari link di email, jadi hasilnya selalu berupa
+// redirect ke halaman login frontend:
+//   - ?verified=true: berhasil
+//   - ?verified=true&reason=already_verified: link sudah pernah dipakai
+//   - ?verified=false&reason=expired: minta link baru lewat /auth/resend-verification
+//   - ?verified=false&reason=invalid: token tidak dikenal
+func (ctrl *Controller) VerifyEmail(c *gin.Context) {
+	redirect := func(query string) {
+		c.Redirect(http.StatusFound, ctrl.Config.FrontendURL+"/login?"+query)
+	}
 
-func VerifyEmail(c *gin.Context) {
-    token := c.DefaultQuery("token", "")
-
-    if token == "" {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
-        return
-    }
-
-    // Cari user berdasarkan token verifikasi
-    collection := config.DB.Collection("users")
-    var user models.User
-    err := collection.FindOne(context.TODO(), bson.M{"verification_token": token}).Decode(&user)
-    if err != nil {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-        return
-    }
-
-    // Update status email pengguna menjadi terverifikasi dan hapus token
-    _, err = collection.UpdateOne(context.TODO(), bson.M{"_id": user.UserID}, bson.M{
-        "$set": bson.M{"verified_email": true},
-        "$unset": bson.M{"verification_token": ""},
-    })
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user verification"})
-        return
-    }
-
-    // Redirect ke halaman login
-    c.Redirect(http.StatusFound, "https://kosconnect.github.io/login?verified=true")
-}
+	token := c.Query("token")
+	if token == "" {
+		redirect("verified=false&reason=invalid")
+		return
+	}
+
+	// Cari user berdasarkan token verifikasi
+	user, err := ctrl.Store.Users.FindByVerificationToken(c.Request.Context(), token)
+	if errors.Is(err, store.ErrNotFound) {
+		redirect("verified=false&reason=invalid")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to verify email", err)
+		return
+	}
+	if user.VerifiedEmail {
+		redirect("verified=true&reason=already_verified")
+		return
+	}
+	if user.VerificationExpired(time.Now(), ctrl.Config.Auth.VerificationTTL) {
+		redirect("verified=false&reason=expired")
+		return
+	}
 
-// Google OAuth Configuration
-var googleOauthConfig = oauth2.Config{
-	RedirectURL:  "https://kosconnect-server.vercel.app/auth/callback", // Sesuaikan dengan konfigurasi Anda
-	ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
-	ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
-	Scopes:       []string{"https://www.googleapis.com/auth/userinfo.profile", "https://www.googleapis.com/auth/userinfo.email"},
-	Endpoint:     google.Endpoint,
+	// Update status email pengguna menjadi terverifikasi
+	err = ctrl.Store.Users.MarkEmailVerified(c.Request.Context(), user.UserID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update user verification", err)
+		return
+	}
+
+	// Redirect ke halaman login
+	redirect("verified=true")
 }
 
-var oauthStateString = generateStateOauthCookie()
+// ResendVerification mengirim ulang email verifikasi dengan link baru.
+// Seperti ForgotPassword, response-nya tidak membedakan email yang tidak
+// terdaftar, sudah terverifikasi, atau baru saja dikirimi email (dalam
+// auth.verification_resend_interval).
+func (ctrl *Controller) ResendVerification(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if !bind(c, &req, binding.JSON) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := ctrl.Store.Users.FindByEmail(ctx, req.Email)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to process request", err)
+		return
+	}
+	now := time.Now()
+	// User Google tidak punya password dan emailnya sudah diverifikasi Google
+	send := err == nil && !user.VerifiedEmail && user.Password != "" &&
+		(user.VerificationSentAt == nil || now.Sub(*user.VerificationSentAt) >= ctrl.Config.Auth.VerificationResendInterval)
+	if send {
+		token := helper.RandomToken()
+		if err := ctrl.Store.Users.SetVerificationToken(ctx, user.UserID, token, now.Add(ctrl.Config.Auth.VerificationTTL)); err != nil {
+			respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to process request", err)
+			return
+		}
+
+		logger := middlewares.Logger(c)
+		link := helper.VerificationLink(ctrl.Config.BaseURL, token)
+		// Dikirim setelah response, sama seperti sendPasswordReset
+		sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ctrl.Config.Timeouts.SMTP)
+		go func() {
+			defer cancel()
+			if err := helper.SendVerificationEmail(sendCtx, ctrl.Config.SMTP, user.Email, link, user.FullName); err != nil {
+				logger.Error("failed to resend verification email", "user_id", user.UserID.Hex(), "error", err)
+			}
+		}()
+	}
 
-// Generate random state for security
-func generateStateOauthCookie() string {
-	b := make([]byte, 16)
-	rand.Read(b)
-	return base64.URLEncoding.EncodeToString(b)
+	response.Message(c, http.StatusOK, nil, "If the email is registered and not yet verified, a new verification link has been sent.")
 }
 
-// HandleGoogleLogin redirects the user to the Google login page
-func HandleGoogleLogin(c *gin.Context) {
-	url := googleOauthConfig.AuthCodeURL(oauthStateString, oauth2.AccessTypeOffline)
+// HandleGoogleLogin redirects the user to the Google login page dengan
+// state dan PKCE challenge baru (lihat newOAuthState)
+func (ctrl *Controller) HandleGoogleLogin(c *gin.Context) {
+	state, verifier, err := ctrl.newOAuthState(c)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to start Google login", err)
+		return
+	}
+	url := ctrl.googleOauthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
 	c.Redirect(http.StatusTemporaryRedirect, url)
 }
 
-func HandleGoogleCallback(c *gin.Context) {
+// HandleGoogleCallback menerima redirect dari Google. State harus cocok
+// dengan cookie dari HandleGoogleLogin dan belum pernah dipakai, dan code
+// ditukar bersama PKCE verifier-nya.
+func (ctrl *Controller) HandleGoogleCallback(c *gin.Context) {
+	verifier, err := ctrl.useOAuthState(c, c.Query("state"))
+	if errors.Is(err, errInvalidOAuthState) {
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidToken, "Invalid or expired OAuth state, please log in again")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to verify OAuth state", err)
+		return
+	}
+
 	code := c.Query("code")
 	if code == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Code not found"})
+		response.Fail(c, http.StatusBadRequest, response.CodeValidationFailed, "Code not found")
 		return
 	}
 
 	// Exchange the code for a token
-	token, err := googleOauthConfig.Exchange(context.Background(), code)
+	token, err := ctrl.googleOauthConfig.Exchange(c.Request.Context(), code, oauth2.VerifierOption(verifier))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to exchange token: " + err.Error()})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to exchange token", err)
 		return
 	}
 
 	// Fetch user info from Google
-	client := googleOauthConfig.Client(context.Background(), token)
+	client := ctrl.googleOauthConfig.Client(c.Request.Context(), token)
 	resp, err := client.Get("https://www.googleapis.com/oauth2/v1/userinfo?alt=json")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user info: " + err.Error()})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to get user info", err)
 		return
 	}
 	defer resp.Body.Close()
@@ -190,16 +261,14 @@ func HandleGoogleCallback(c *gin.Context) {
 		VerifiedEmail bool   `json:"verified_email"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&userInfo); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode user info: " + err.Error()})
+		response.Fail(c, http.StatusInternalServerError, response.CodeInternal, "Failed to decode user info")
 		return
 	}
 
 	// Check if user exists in database
-	var user models.User
-	collection := config.DB.Collection("users")
-	err = collection.FindOne(context.TODO(), bson.M{"email": userInfo.Email}).Decode(&user)
+	user, err := ctrl.Store.Users.FindByEmail(c.Request.Context(), userInfo.Email)
 
-	if err == mongo.ErrNoDocuments {
+	if errors.Is(err, store.ErrNotFound) {
 		// Create new user
 		newUser := models.User{
 			UserID:        primitive.NewObjectID(),
@@ -208,236 +277,191 @@ func HandleGoogleCallback(c *gin.Context) {
 			Role:          "", // Role belum ditentukan
 			VerifiedEmail: userInfo.VerifiedEmail,
 		}
-		_, err = collection.InsertOne(context.TODO(), newUser)
+		err = ctrl.Store.Users.Create(c.Request.Context(), &newUser)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+			respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to create user", err)
 			return
 		}
 
 		// Redirect to role assignment page
-		c.Redirect(http.StatusFound, "https://kosconnect.github.io/auth-assign-role?email="+userInfo.Email+"&id="+newUser.UserID.Hex())
+		c.Redirect(http.StatusFound, ctrl.Config.FrontendURL+"/auth-assign-role?email="+userInfo.Email+"&id="+newUser.UserID.Hex())
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Database error", err)
 		return
 	}
 
 	// If role is not assigned
 	if user.Role == "" {
-		c.Redirect(http.StatusFound, "https://kosconnect.github.io/auth-assign-role?email="+user.Email+"&id="+user.UserID.Hex())
+		c.Redirect(http.StatusFound, ctrl.Config.FrontendURL+"/auth-assign-role?email="+user.Email+"&id="+user.UserID.Hex())
 		return
 	}
 
 	// Redirect user based on role
-	c.Redirect(http.StatusFound, "https://kosconnect.github.io/auth?email="+user.Email+"&role="+user.Role+"&id="+user.UserID.Hex())
+	c.Redirect(http.StatusFound, ctrl.Config.FrontendURL+"/auth?email="+user.Email+"&role="+user.Role+"&id="+user.UserID.Hex())
 }
 
-func AssignRole(c *gin.Context) {
-	var payload struct {
-		Email string `json:"email"`
-		Role  string `json:"role"`
-	}
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+func (ctrl *Controller) AssignRole(c *gin.Context) {
+	var payload dto.AssignRoleRequest
+	if !bind(c, &payload, binding.JSON) {
 		return
 	}
 
-	// Validasi role
-	if payload.Role != "user" && payload.Role != "owner" && payload.Role != "admin" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+	// Route tanpa login: hanya akun yang belum punya role yang boleh memilih,
+	// agar role akun lain tidak bisa diubah lewat email-nya
+	user, err := ctrl.Store.Users.FindByEmail(c.Request.Context(), payload.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeUserNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update role", err)
+		return
+	}
+	if user.Role != "" {
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Role has already been assigned")
 		return
 	}
 
 	// Update role di database
-	collection := config.DB.Collection("users")
-	_, err := collection.UpdateOne(context.TODO(), bson.M{"email": payload.Email}, bson.M{"$set": bson.M{"role": payload.Role}})
+	err = ctrl.Store.Users.Update(c.Request.Context(), user.UserID, bson.M{"role": payload.Role, "is_role_assigned": true, "updated_at": time.Now()})
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeUserNotFound, "User not found")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update role", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Role assigned successfully"})
+	response.Message(c, http.StatusOK, nil, "Role assigned successfully")
 }
 
-func GoogleAuth(c *gin.Context) {
-	var payload struct {
-		Email string `json:"email" binding:"required,email"`
-		Role  string `json:"role" binding:"required"`
+// GoogleAuth login dengan ID token dari Google Identity Services. Token
+// diverifikasi terhadap JWKS Google (tanda tangan, aud, iss, exp dan
+// email_verified); user dicari atau dibuat hanya dari claims token itu.
+func (ctrl *Controller) GoogleAuth(c *gin.Context) {
+	var payload dto.GoogleAuthRequest
+	if !bind(c, &payload, binding.JSON) {
+		return
 	}
 
-	// Validasi input payload
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	ctx := c.Request.Context()
+	claims, err := ctrl.googleIDTokens.Verify(ctx, payload.IDToken)
+	if errors.Is(err, googleid.ErrInvalidToken) {
+		middlewares.Logger(c).Info("google id token rejected", "error", err)
+		response.Fail(c, http.StatusUnauthorized, response.CodeInvalidToken, "Invalid Google ID token")
 		return
 	}
-
-	// Validasi role
-	if payload.Role != "user" && payload.Role != "owner" && payload.Role != "admin" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to verify Google ID token", err)
 		return
 	}
 
-	// Cari user berdasarkan email
-	collection := config.DB.Collection("users")
-	var user models.User
-	err := collection.FindOne(context.TODO(), bson.M{"email": payload.Email}).Decode(&user)
+	// Cari user berdasarkan email dari token, buat baru jika belum ada
+	user, err := ctrl.Store.Users.FindByEmail(ctx, claims.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		user, err = ctrl.createGoogleUser(ctx, claims, payload.Role)
+	}
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to log in with Google", err)
 		return
 	}
 
-	// Generate JWT token
-	token, err := generateToken(user.UserID, user.Role)
+	set := bson.M{}
+	// Google sudah memverifikasi email ini
+	if !user.VerifiedEmail {
+		user.VerifiedEmail = true
+		set["verified_email"] = true
+	}
+	if user.Role == "" && payload.Role != "" {
+		user.Role, user.IsRoleAssigned = payload.Role, true
+		set["role"], set["is_role_assigned"] = payload.Role, true
+	}
+	if len(set) > 0 {
+		set["updated_at"] = time.Now()
+		if err := ctrl.Store.Users.Update(ctx, user.UserID, set); err != nil {
+			respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to log in with Google", err)
+			return
+		}
+	}
+
+	// Buat session baru beserta access token dan refresh token
+	tokens, err := ctrl.startSession(c, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to generate token", err)
 		return
 	}
 
-	// Set cookies (secure cookie untuk HTTPS)
-	c.SetCookie("authToken", token, 3600*24*7, "/", "", true, true)
-
-	// Response sukses
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"role":    user.Role,
-		"token":   token,
-	})
+	// Token di body dan cookie (secure cookie untuk HTTPS); role kosong berarti frontend harus ke assign-role
+	ctrl.respondLogin(c, user, tokens, "Login successful")
 }
 
-//SEMENTARA
-func Login(c *gin.Context) {
-	var loginData struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+// createGoogleUser membuat user baru dari claims ID token Google. Jika
+// email yang sama dibuat bersamaan oleh request lain, user itu yang dipakai.
+func (ctrl *Controller) createGoogleUser(ctx context.Context, claims *googleid.Claims, role string) (*models.User, error) {
+	now := time.Now()
+	user := models.User{
+		UserID:         primitive.NewObjectID(),
+		FullName:       claims.Name,
+		Email:          claims.Email,
+		Picture:        claims.Picture,
+		Role:           role,
+		VerifiedEmail:  true,
+		IsRoleAssigned: role != "",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	err := ctrl.Store.Users.Create(ctx, &user)
+	if errors.Is(err, store.ErrDuplicate) {
+		return ctrl.Store.Users.FindByEmail(ctx, claims.Email)
+	}
+	if err != nil {
+		return nil, err
 	}
+	return &user, nil
+}
 
-	// Bind JSON input
-	if err := c.ShouldBindJSON(&loginData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+// Login dengan email dan password. Jika auth.require_verified_email aktif,
+// user yang belum verifikasi email ditolak setelah password-nya dicek.
+func (ctrl *Controller) Login(c *gin.Context) {
+	var loginData dto.LoginRequest
+	if !bind(c, &loginData, binding.JSON) {
 		return
 	}
 
 	// Cari user berdasarkan email
-	collection := config.DB.Collection("users")
-	var user models.User
-	err := collection.FindOne(context.TODO(), bson.M{"email": loginData.Email}).Decode(&user)
+	user, err := ctrl.Store.Users.FindByEmail(c.Request.Context(), loginData.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusUnauthorized, response.CodeInvalidCredentials, "Invalid email or password")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		// Database bermasalah bukan salah password: 500, atau 504 jika timeout
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to log in", err)
 		return
 	}
 
 	// Cek password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginData.Password))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		response.Fail(c, http.StatusUnauthorized, response.CodeInvalidCredentials, "Invalid email or password")
+		return
+	}
+
+	// Cek apakah email sudah diverifikasi
+	if ctrl.Config.Auth.RequireVerifiedEmail && !user.VerifiedEmail {
+		response.Fail(c, http.StatusForbidden, response.CodeEmailNotVerified, "Email not verified. Please check your email or request a new verification link.")
 		return
 	}
 
-	// Generate JWT token
-	token, err := generateToken(user.UserID, user.Role)
+	// Buat session baru beserta access token dan refresh token
+	tokens, err := ctrl.startSession(c, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
-		return
-	}
-
-	// Set token sebagai cookie
-	c.SetCookie(
-		"authToken", // Cookie name
-		token,       // Value
-		3600*24*7,   // Expiry time in seconds (7 days)
-		"/",         // Path
-		"",          // Domain (empty means same as the server's domain)
-		true,        // Secure (true for HTTPS only)
-		true,        // HttpOnly (true prevents JavaScript access)
-	)
-
-	// Set role sebagai cookie
-	c.SetCookie(
-		"userRole", // Cookie name
-		user.Role,  // Value
-		3600*24*7,  // Expiry time in seconds (7 days)
-		"/",        // Path
-		"",         // Domain (empty means same as the server's domain)
-		true,       // Secure (true for HTTPS only)
-		false,      // HttpOnly (false to allow JavaScript access)
-	)
-
-	// Kirim respon sukses
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   token,
-		"role":    user.Role,
-	})
-}
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to generate token", err)
+		return
+	}
 
-//YANG BENER
-// func Login(c *gin.Context) {
-// 	var loginData struct {
-// 		Email    string `json:"email"`
-// 		Password string `json:"password"`
-// 	}
-
-// 	// Bind JSON input
-// 	if err := c.ShouldBindJSON(&loginData); err != nil {
-// 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
-// 		return
-// 	}
-
-// 	// Cari user berdasarkan email
-// 	collection := config.DB.Collection("users")
-// 	var user models.User
-// 	err := collection.FindOne(context.TODO(), bson.M{"email": loginData.Email}).Decode(&user)
-// 	if err != nil {
-// 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
-// 		return
-// 	}
-
-// 	// Cek apakah email sudah diverifikasi
-// 	if !user.VerifiedEmail {
-// 		c.JSON(http.StatusForbidden, gin.H{"error": "Email belum diverifikasi. Silakan verifikasi email Anda."})
-// 		return
-// 	}
-
-// 	// Cek password
-// 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginData.Password))
-// 	if err != nil {
-// 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
-// 		return
-// 	}
-
-// 	// Generate JWT token
-// 	token, err := generateToken(user.UserID, user.Role)
-// 	if err != nil {
-// 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
-// 		return
-// 	}
-
-// 	// Set token sebagai cookie
-// 	c.SetCookie(
-// 		"authToken", // Cookie name
-// 		token,       // Value
-// 		3600*24*7,   // Expiry time in seconds (7 days)
-// 		"/",         // Path
-// 		"",          // Domain (empty means same as the server's domain)
-// 		true,        // Secure (true for HTTPS only)
-// 		true,        // HttpOnly (true prevents JavaScript access)
-// 	)
-
-// 	// Set role sebagai cookie
-// 	c.SetCookie(
-// 		"userRole", // Cookie name
-// 		user.Role,  // Value
-// 		3600*24*7,  // Expiry time in seconds (7 days)
-// 		"/",        // Path
-// 		"",         // Domain (empty means same as the server's domain)
-// 		true,       // Secure (true for HTTPS only)
-// 		false,      // HttpOnly (false to allow JavaScript access)
-// 	)
-
-// 	// Kirim respon sukses
-// 	c.JSON(http.StatusOK, gin.H{
-// 		"message": "Login successful",
-// 		"token":   token,
-// 		"role":    user.Role,
-// 	})
-// }
\ No newline at end of file
+	// Kirim token di body, authToken, refreshToken dan userRole di cookie
+	ctrl.respondLogin(c, user, tokens, "Login successful")
+}
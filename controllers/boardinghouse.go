@@ -2,34 +2,37 @@ package controllers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"path"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"github.com/organisasi/kosconnectbackend/config"
-	"github.com/organisasi/kosconnectbackend/helper"
+	"github.com/organisasi/kosconnectbackend/dto"
 	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
+	"github.com/organisasi/kosconnectbackend/validation"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // CREATE / POST
-func CreateBoardingHouse(c *gin.Context) {
+func (ctrl *Controller) CreateBoardingHouse(c *gin.Context) {
 	// Ambil klaim user dari JWT
 	claims := c.MustGet("user").(jwt.MapClaims)
 
 	// Ambil role dan user ID dari klaim
 	role, ok := claims["role"].(string)
 	if !ok || (role != "admin" && role != "owner") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins or owners can create boarding houses"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only admins or owners can create boarding houses")
 		return
 	}
 
@@ -38,77 +41,50 @@ func CreateBoardingHouse(c *gin.Context) {
 	// Parse form-data
 	err := c.Request.ParseMultipartForm(10 << 20)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse form-data"})
+		response.Fail(c, http.StatusBadRequest, response.CodeValidationFailed, "Failed to parse form-data")
 		return
 	}
 
-	// Extract fields from form-data
-	name := c.PostForm("name")
-	address := c.PostForm("address")
-	description := c.PostForm("description")
-	rules := c.PostForm("rules")
-	categoryID, _ := primitive.ObjectIDFromHex(c.PostForm("category_id"))
-
-	if name == "" || address == "" || description == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Name, address, and description are required"})
-		return
-	}
-
-	if categoryID.IsZero() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing category_id"})
+	// Extract dan validasi fields dari form-data
+	var req dto.BoardingHouseForm
+	if !bind(c, &req, binding.FormMultipart) {
 		return
 	}
+	name := req.Name
+	address := req.Address
+	description := req.Description
+	rules := req.Rules
+	categoryID, _ := primitive.ObjectIDFromHex(req.CategoryID)
 
 	// Validasi OwnerID untuk admin
 	var ownerObjectID primitive.ObjectID
 	if role == "admin" {
-		ownerIDFromForm := c.PostForm("owner_id")
-		if ownerIDFromForm == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "OwnerID is required for admin"})
-			return
-		}
-
-		var err error
-		ownerObjectID, err = primitive.ObjectIDFromHex(ownerIDFromForm)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OwnerID format"})
+		if req.OwnerID == "" {
+			failValidation(c, validation.FieldError{Field: "owner_id", Rule: "required", Message: "is required for admin"})
 			return
 		}
+		ownerObjectID, _ = primitive.ObjectIDFromHex(req.OwnerID)
 	} else if role == "owner" {
 		var err error
 		ownerObjectID, err = primitive.ObjectIDFromHex(ownerID)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OwnerID format"})
+			response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid OwnerID format")
 			return
 		}
 	}
 
 	// Validasi fasilitas
-	facilitiesJSON := c.PostForm("facilities")
-	var facilitiesIDs []string
-	if err := json.Unmarshal([]byte(facilitiesJSON), &facilitiesIDs); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid facilities format"})
+	facilitiesIDs, ok := parseObjectIDs(c, "facilities", req.Facilities)
+	if !ok {
 		return
 	}
 
 	// Validasi setiap fasilitas di database
-	collectionFacilities := config.DB.Collection("facilities")
 	validFacilities := []primitive.ObjectID{}
-	for _, facilityID := range facilitiesIDs {
-		facilityObjectID, err := primitive.ObjectIDFromHex(facilityID)
+	for _, facilityObjectID := range facilitiesIDs {
+		_, err = ctrl.Store.Facilities.FindByIDAndType(c.Request.Context(), facilityObjectID, "boarding_house")
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid facility ID"})
-			return
-		}
-
-		var facility models.CustomFacility
-		err = collectionFacilities.FindOne(context.TODO(), bson.M{
-			"_id":  facilityObjectID,
-			"type": "boarding_house",
-		}).Decode(&facility)
-
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid facility type or facility does not exist"})
+			respondError(c, http.StatusBadRequest, response.CodeValidationFailed, "Invalid facility type or facility does not exist", err)
 			return
 		}
 
@@ -119,7 +95,7 @@ func CreateBoardingHouse(c *gin.Context) {
 	var boardinghouseImageURL []string
 	form, err := c.MultipartForm()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse form-data"})
+		response.Fail(c, http.StatusBadRequest, response.CodeValidationFailed, "Failed to parse form-data")
 		return
 	}
 
@@ -127,34 +103,23 @@ func CreateBoardingHouse(c *gin.Context) {
 	for _, fileHeader := range files {
 		file, err := fileHeader.Open()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+			response.Fail(c, http.StatusInternalServerError, response.CodeUploadFailed, "Failed to read uploaded file")
 			return
 		}
 		defer file.Close()
 
 		content, err := io.ReadAll(file)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file content"})
+			response.Fail(c, http.StatusInternalServerError, response.CodeInternal, "Failed to read file content")
 			return
 		}
 
 		ext := path.Ext(fileHeader.Filename)
 		uniqueFilename := fmt.Sprintf("BoardingHouseImages/%s%s", uuid.New().String(), ext)
 
-		githubConfig := helper.GitHubConfig{
-			AccessToken: config.GetGitHubToken(),
-			AuthorName:  "Balqis Rosa Sekamayang",
-			AuthorEmail: "balqisrosasekamayang@gmail.com",
-			Org:         "kosconnect",
-			Repo:        "img",
-			FilePath:    uniqueFilename,
-			FileContent: content,
-			Replace:     true,
-		}
-
-		resp, err := helper.UploadFile(context.Background(), githubConfig)
+		resp, err := ctrl.uploadImage(c.Request.Context(), uniqueFilename, content)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to upload file to GitHub: %v", err)})
+			respondError(c, http.StatusInternalServerError, response.CodeUploadFailed, "Failed to upload file to GitHub", err)
 			return
 		}
 
@@ -179,19 +144,39 @@ func CreateBoardingHouse(c *gin.Context) {
 		Facilities:      validFacilities,
 		Images:          boardinghouseImageURL,
 		Rules:           rules,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
 
-	collection := config.DB.Collection("boardinghouses")
-	_, err = collection.InsertOne(context.Background(), boardingHouse)
+	err = ctrl.Store.BoardingHouses.Create(c.Request.Context(), &boardingHouse)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save boarding house to database"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to save boarding house to database", err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Boarding house created successfully",
-		"data":    boardingHouse,
-	})
+	response.Message(c, http.StatusCreated, boardingHouse, "Boarding house created successfully")
+}
+
+// ownerName mengembalikan nama owner, atau "Unknown" jika user tidak ditemukan atau bukan owner
+func (ctrl *Controller) ownerName(ctx context.Context, ownerID primitive.ObjectID) string {
+	owner, err := ctrl.Store.Users.FindByID(ctx, ownerID)
+	if err != nil || owner.Role != "owner" {
+		return "Unknown"
+	}
+	return owner.FullName
+}
+
+// facilityNames mengambil nama fasilitas, fasilitas yang tidak ditemukan dilewati
+func (ctrl *Controller) facilityNames(ctx context.Context, facilityIDs []primitive.ObjectID) []string {
+	var names []string
+	for _, facilityID := range facilityIDs {
+		facility, err := ctrl.Store.Facilities.FindByID(ctx, facilityID)
+		if err != nil {
+			continue
+		}
+		names = append(names, facility.Name)
+	}
+	return names
 }
 
 // generateSlug generates a URL-friendly slug from the given name
@@ -202,210 +187,111 @@ func generateSlug(name string) string {
 	return reg.ReplaceAllString(slug, "") + "-" + uuid.NewString()
 }
 
-func GetAllBoardingHouse(c *gin.Context) {
-	collection := config.DB.Collection("boardinghouses")
-
-	// Ambil semua data boarding house dari database
-	cursor, err := collection.Find(context.TODO(), bson.M{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch boarding houses"})
+func (ctrl *Controller) GetAllBoardingHouse(c *gin.Context) {
+	q, ok := ctrl.parseQuery(c, dto.BoardingHouseListQuery)
+	if !ok {
 		return
 	}
-	defer cursor.Close(context.TODO())
 
-	// Simpan hasilnya dalam slice
-	var boardingHouses []models.BoardingHouse
-	if err = cursor.All(context.TODO(), &boardingHouses); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse boarding houses"})
+	// Ambil satu halaman data boarding house dari database
+	page, err := ctrl.Store.BoardingHouses.List(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch boarding houses", err)
 		return
 	}
 
 	// Kembalikan data tanpa memodifikasi
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Boarding houses fetched successfully",
-		"data":    boardingHouses,
-	})
+	respondPage(c, q, page, page.Items)
 }
 
-func GetBoardingHouseDetails(c *gin.Context) {
+func (ctrl *Controller) GetBoardingHouseDetails(c *gin.Context) {
 	boardingHouseID := c.Param("id")
 	objectID, err := primitive.ObjectIDFromHex(boardingHouseID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid boarding house ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid boarding house ID")
 		return
 	}
 
-	boardingHouseCollection := config.DB.Collection("boardinghouses")
-
-	// Pipeline untuk mengambil category_id, owner_id, dan facilities
-	pipeline := mongo.Pipeline{
-		{
-			{Key: "$match", Value: bson.D{
-				{Key: "_id", Value: objectID}, // Filter berdasarkan BoardingHouse ID
-			}},
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "categories"},            // Gabungkan dengan koleksi Categories
-				{Key: "localField", Value: "category_id"},    // Field referensi dari BoardingHouse
-				{Key: "foreignField", Value: "_id"},          // Field referensi di koleksi Categories
-				{Key: "as", Value: "category"},               // Hasil join disimpan dalam field category
-			}},
-		},
-		{
-			{Key: "$unwind", Value: bson.D{
-				{Key: "path", Value: "$category"}, // Unwind untuk mengubah array menjadi objek
-			}},
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "users"},                 // Gabungkan dengan koleksi Users
-				{Key: "localField", Value: "owner_id"},       // Field referensi dari BoardingHouse
-				{Key: "foreignField", Value: "_id"},         // Field referensi di koleksi Users
-				{Key: "as", Value: "owner"},                 // Hasil join disimpan dalam field owner
-			}},
-		},
-		{
-			{Key: "$unwind", Value: bson.D{
-				{Key: "path", Value: "$owner"}, // Unwind untuk mengubah array menjadi objek
-			}},
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "facilities"},           // Gabungkan dengan koleksi Facilities
-				{Key: "localField", Value: "facilities_id"},  // Field referensi array dari BoardingHouse
-				{Key: "foreignField", Value: "_id"},         // Field referensi di koleksi Facilities
-				{Key: "as", Value: "facilities"},            // Hasil join disimpan dalam field facilities
-			}},
-		},
-		{
-			{Key: "$project", Value: bson.D{
-				{Key: "category_name", Value: "$category.name"}, // Ambil nama kategori
-				{Key: "owner_fullname", Value: "$owner.fullname"}, // Ambil fullname owner
-				{Key: "facilities", Value: "$facilities.name"},    // Ambil nama fasilitas
-			}},
-		},
-	}
-
-	// Eksekusi pipeline
-	cursor, err := boardingHouseCollection.Aggregate(context.TODO(), pipeline)
+	// Ambil nama kategori, owner, dan fasilitas kos
+	boardingHouseDetails, err := ctrl.Store.BoardingHouses.Details(c.Request.Context(), objectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch data", err)
 		return
 	}
 
-	// Decode hasil query
-	var boardingHouseDetails []bson.M
-	if err := cursor.All(context.TODO(), &boardingHouseDetails); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode data"})
+	// Pipeline mengembalikan paling banyak satu dokumen
+	if len(boardingHouseDetails) == 0 {
+		response.Fail(c, http.StatusNotFound, response.CodeBoardingHouseNotFound, "Boarding house not found")
 		return
 	}
-
-	// Kirimkan response JSON
-	c.JSON(http.StatusOK, boardingHouseDetails)
+	response.OK(c, boardingHouseDetails[0])
 }
 
 // GetBoardingHouseByID retrieves a boarding house by ID along with its associated facility names, category name, and owner name
-func GetBoardingHouseByID(c *gin.Context) {
+func (ctrl *Controller) GetBoardingHouseByID(c *gin.Context) {
 	id := c.Param("id")
 	boardingHouseID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid boarding house ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid boarding house ID")
 		return
 	}
 
 	// Retrieve the boarding house
-	collection := config.DB.Collection("boardinghouses")
-	var boardingHouse models.BoardingHouse
-	err = collection.FindOne(context.TODO(), bson.M{"_id": boardingHouseID}).Decode(&boardingHouse)
+	boardingHouse, err := ctrl.Store.BoardingHouses.FindByID(c.Request.Context(), boardingHouseID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Boarding house not found"})
+		respondError(c, http.StatusNotFound, response.CodeBoardingHouseNotFound, "Boarding house not found", err)
 		return
 	}
 
 	// Fetch the associated category name
-	collectionCategories := config.DB.Collection("categories")
-	var category models.Category
-	err = collectionCategories.FindOne(context.TODO(), bson.M{"_id": boardingHouse.CategoryID}).Decode(&category)
+	category, err := ctrl.Store.Categories.FindByID(c.Request.Context(), boardingHouse.CategoryID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		respondError(c, http.StatusNotFound, response.CodeCategoryNotFound, "Category not found", err)
 		return
 	}
 
-	// Fetch the associated owner name
-	collectionUsers := config.DB.Collection("users")
-	var owner models.User
-	err = collectionUsers.FindOne(context.TODO(), bson.M{
-		"_id":  boardingHouse.OwnerID,
-		"role": "owner", // Ensure the user is an owner
-	}).Decode(&owner)
-	if err != nil {
-		owner.FullName = "Unknown" // If the owner is not found or does not have the "owner" role, set to "Unknown"
-	}
-
-	// Fetch the associated facility names
-	collectionFacilities := config.DB.Collection("facilities")
-	var facilityNames []string
-	for _, facilityID := range boardingHouse.Facilities {
-		var facility models.Facility
-		err = collectionFacilities.FindOne(context.TODO(), bson.M{"_id": facilityID}).Decode(&facility)
-		if err != nil {
-			continue // Skip if the facility is not found
-		}
-		facilityNames = append(facilityNames, facility.Name)
-	}
+	// Fetch the associated owner name and facility names
+	ownerName := ctrl.ownerName(c.Request.Context(), boardingHouse.OwnerID)
+	facilityNames := ctrl.facilityNames(c.Request.Context(), boardingHouse.Facilities)
 
 	// Respond with boarding house data including category, owner name, and facility names
-	c.JSON(http.StatusOK, gin.H{
-		"boardingHouse": boardingHouse,
-		"category":      category.Name,  // Include the category name
-		"owner":         owner.FullName, // Include the owner name
-		"facilities":    facilityNames,
+	response.OK(c, gin.H{
+		"boarding_house": boardingHouse,
+		"category":       category.Name, // Include the category name
+		"owner":          ownerName,     // Include the owner name
+		"facilities":     facilityNames,
 	})
 }
 
 // INI BUAT PEMILIK KOS
-func GetBoardingHouseByOwnerID(c *gin.Context) {
+func (ctrl *Controller) GetBoardingHouseByOwnerID(c *gin.Context) {
 	claims := c.MustGet("user").(jwt.MapClaims)
 
 	if role, ok := claims["role"].(string); !ok || role != "owner" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only owners can view their boarding houses"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only owners can view their boarding houses")
 		return
 	}
 
 	ownerID := claims["user_id"].(string)
 	ownerObjectID, _ := primitive.ObjectIDFromHex(ownerID)
 
-	collection := config.DB.Collection("boardinghouses")
-	var boardingHouses []models.BoardingHouse
-
-	cursor, err := collection.Find(context.Background(), bson.M{"owner_id": ownerObjectID})
+	boardingHouses, err := ctrl.Store.BoardingHouses.FindByOwner(c.Request.Context(), ownerObjectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch boarding houses"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch boarding houses", err)
 		return
 	}
-	defer cursor.Close(context.Background())
 
-	for cursor.Next(context.Background()) {
-		var boardingHouse models.BoardingHouse
-		if err := cursor.Decode(&boardingHouse); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding boarding house"})
-			return
-		}
-		boardingHouses = append(boardingHouses, boardingHouse)
-	}
-
-	c.JSON(http.StatusOK, gin.H{"data": boardingHouses})
+	response.OK(c, boardingHouses)
 }
 
 // UPDATE / PATCH
-func UpdateBoardingHouse(c *gin.Context) {
+func (ctrl *Controller) UpdateBoardingHouse(c *gin.Context) {
 	claims := c.MustGet("user").(jwt.MapClaims)
 
 	// Ambil role dan user_id dari klaim JWT
 	role, ok := claims["role"].(string)
 	if !ok || (role != "owner" && role != "admin") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only owners or admins can update boarding houses"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only owners or admins can update boarding houses")
 		return
 	}
 
@@ -415,7 +301,7 @@ func UpdateBoardingHouse(c *gin.Context) {
 		var err error
 		ownerID, err = primitive.ObjectIDFromHex(claims["user_id"].(string))
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner ID"})
+			response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid owner ID")
 			return
 		}
 	}
@@ -424,53 +310,45 @@ func UpdateBoardingHouse(c *gin.Context) {
 	boardingHouseID := c.Param("id")
 	objectID, err := primitive.ObjectIDFromHex(boardingHouseID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid boarding house ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid boarding house ID")
 		return
 	}
 
-	// Filter query untuk memastikan hanya owner yang memiliki boarding house tersebut dapat mengupdate
-	filter := bson.M{"_id": objectID}
-	if role == "owner" {
-		filter["owner_id"] = ownerID
-	}
-
 	// Parse form-data
 	err = c.Request.ParseMultipartForm(10 << 20)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse form-data"})
+		response.Fail(c, http.StatusBadRequest, response.CodeValidationFailed, "Failed to parse form-data")
 		return
 	}
 
-	// Extract fields to update
+	// Extract dan validasi fields to update
+	var req dto.UpdateBoardingHouseForm
+	if !bind(c, &req, binding.FormMultipart) {
+		return
+	}
 	updateFields := bson.M{}
 
-	if name := c.PostForm("name"); name != "" {
-		updateFields["name"] = name
-		updateFields["slug"] = generateSlug(name)
+	if req.Name != "" {
+		updateFields["name"] = req.Name
+		updateFields["slug"] = generateSlug(req.Name)
 	}
-	if address := c.PostForm("address"); address != "" {
-		updateFields["address"] = address
+	if req.Address != "" {
+		updateFields["address"] = req.Address
 	}
-	if description := c.PostForm("description"); description != "" {
-		updateFields["description"] = description
+	if req.Description != "" {
+		updateFields["description"] = req.Description
 	}
-	if rules := c.PostForm("rules"); rules != "" {
-		updateFields["rules"] = rules
+	if req.Rules != "" {
+		updateFields["rules"] = req.Rules
 	}
-	if categoryID := c.PostForm("category_id"); categoryID != "" {
-		id, err := primitive.ObjectIDFromHex(categoryID)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
-			return
-		}
-		updateFields["category_id"] = id
+	if req.CategoryID != "" {
+		updateFields["category_id"], _ = primitive.ObjectIDFromHex(req.CategoryID)
 	}
 
 	// Update facilities
-	if facilitiesJSON := c.PostForm("facilities"); facilitiesJSON != "" {
-		var facilities []primitive.ObjectID
-		if err := json.Unmarshal([]byte(facilitiesJSON), &facilities); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid facilities format"})
+	if req.Facilities != "" {
+		facilities, ok := parseObjectIDs(c, "facilities", req.Facilities)
+		if !ok {
 			return
 		}
 		updateFields["facilities_id"] = facilities
@@ -484,34 +362,23 @@ func UpdateBoardingHouse(c *gin.Context) {
 		for _, fileHeader := range files {
 			file, err := fileHeader.Open()
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+				response.Fail(c, http.StatusInternalServerError, response.CodeUploadFailed, "Failed to read uploaded file")
 				return
 			}
 			defer file.Close()
 
 			content, err := io.ReadAll(file)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file content"})
+				response.Fail(c, http.StatusInternalServerError, response.CodeInternal, "Failed to read file content")
 				return
 			}
 
 			ext := path.Ext(fileHeader.Filename)
 			uniqueFilename := fmt.Sprintf("BoardingHouseImages/%s%s", uuid.New().String(), ext)
 
-			githubConfig := helper.GitHubConfig{
-				AccessToken: config.GetGitHubToken(),
-				AuthorName:  "Balqis Rosa Sekamayang",
-				AuthorEmail: "balqisrosasekamayang@gmail.com",
-				Org:         "kosconnect",
-				Repo:        "img",
-				FilePath:    uniqueFilename,
-				FileContent: content,
-				Replace:     true,
-			}
-
-			resp, err := helper.UploadFile(context.Background(), githubConfig)
+			resp, err := ctrl.uploadImage(c.Request.Context(), uniqueFilename, content)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to upload file to GitHub: %v", err)})
+				respondError(c, http.StatusInternalServerError, response.CodeUploadFailed, "Failed to upload file to GitHub", err)
 				return
 			}
 
@@ -532,97 +399,68 @@ func UpdateBoardingHouse(c *gin.Context) {
 
 	// Periksa apakah ada field yang di-update
 	if len(updateFields) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		response.Fail(c, http.StatusBadRequest, response.CodeValidationFailed, "No fields to update")
 		return
 	}
+	updateFields["updated_at"] = time.Now() // Dipakai ETag endpoint publik
 
-	// Update database
-	collection := config.DB.Collection("boardinghouses")
-	res, err := collection.UpdateOne(
-		context.Background(),
-		filter, // Filter berdasarkan role
-		bson.M{"$set": updateFields},
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update boarding house"})
-		return
-	}
+	// Update database, owner hanya bisa mengupdate boarding house miliknya (ownerID kosong untuk admin)
+	err = ctrl.Store.BoardingHouses.Update(c.Request.Context(), objectID, ownerID, updateFields)
 
 	// Validasi apakah ada dokumen yang di-update
-	if res.MatchedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No boarding house found or unauthorized"})
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeBoardingHouseNotFound, "No boarding house found or unauthorized")
 		return
 	}
-
-	// Ambil data boarding house terbaru untuk response
-	var updatedBoardingHouse models.BoardingHouse
-	err = collection.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&updatedBoardingHouse)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated boarding house"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update boarding house", err)
 		return
 	}
 
-	// Fetch associated category, owner, and facilities
-	collectionCategories := config.DB.Collection("categories")
-	var category models.Category
-	err = collectionCategories.FindOne(context.TODO(), bson.M{"_id": updatedBoardingHouse.CategoryID}).Decode(&category)
-	if err != nil {
-		category.Name = "Unknown"
-	}
-
-	collectionUsers := config.DB.Collection("users")
-	var owner models.User
-	err = collectionUsers.FindOne(context.TODO(), bson.M{
-		"_id":  updatedBoardingHouse.OwnerID,
-		"role": "owner",
-	}).Decode(&owner)
+	// Ambil data boarding house terbaru untuk response
+	updatedBoardingHouse, err := ctrl.Store.BoardingHouses.FindByID(c.Request.Context(), objectID)
 	if err != nil {
-		owner.FullName = "Unknown"
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch updated boarding house", err)
+		return
 	}
 
-	collectionFacilities := config.DB.Collection("facilities")
-	var facilityNames []string
-	for _, facilityID := range updatedBoardingHouse.Facilities {
-		var facility models.Facility
-		err = collectionFacilities.FindOne(context.TODO(), bson.M{"_id": facilityID}).Decode(&facility)
-		if err != nil {
-			continue
-		}
-		facilityNames = append(facilityNames, facility.Name)
+	// Fetch associated category, owner, and facilities
+	categoryName := "Unknown"
+	if category, err := ctrl.Store.Categories.FindByID(c.Request.Context(), updatedBoardingHouse.CategoryID); err == nil {
+		categoryName = category.Name
 	}
+	ownerName := ctrl.ownerName(c.Request.Context(), updatedBoardingHouse.OwnerID)
+	facilityNames := ctrl.facilityNames(c.Request.Context(), updatedBoardingHouse.Facilities)
 
 	// Return the updated boarding house data
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Boarding house updated successfully",
-		"data": gin.H{
-			"boarding_house": updatedBoardingHouse,
-			"category":       category.Name,
-			"owner":          owner.FullName,
-			"facilities":     facilityNames,
-		},
-	})
+	response.Message(c, http.StatusOK, gin.H{
+		"boarding_house": updatedBoardingHouse,
+		"category":       categoryName,
+		"owner":          ownerName,
+		"facilities":     facilityNames,
+	}, "Boarding house updated successfully")
 }
 
 // DELETE OLEH OWNER DAN ADMIN
-func DeleteBoardingHouse(c *gin.Context) {
+func (ctrl *Controller) DeleteBoardingHouse(c *gin.Context) {
 	claims := c.MustGet("user").(jwt.MapClaims)
 
 	// Validate if the user role is 'owner' or 'admin'
 	role, ok := claims["role"].(string)
 	if !ok || (role != "owner" && role != "admin") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only owners or admins can delete boarding houses"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only owners or admins can delete boarding houses")
 		return
 	}
 
 	// Get the owner ID from the claims and validate it's a valid ObjectID
 	ownerID, ok := claims["user_id"].(string)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid owner ID")
 		return
 	}
 	ownerObjectID, err := primitive.ObjectIDFromHex(ownerID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner ID format"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid owner ID format")
 		return
 	}
 
@@ -630,28 +468,23 @@ func DeleteBoardingHouse(c *gin.Context) {
 	id := c.Param("id")
 	boardingHouseID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid boarding house ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid boarding house ID")
 		return
 	}
 
 	// If the user is an admin, they can delete any boarding house
 	// If the user is an owner, they can only delete their own boarding house
-	filter := bson.M{"_id": boardingHouseID}
-	if role == "owner" {
-		filter["owner_id"] = ownerObjectID
+	if role != "owner" {
+		ownerObjectID = primitive.NilObjectID
 	}
 
 	// Perform the deletion
-	collection := config.DB.Collection("boardinghouses")
-	result, err := collection.DeleteOne(
-		context.Background(),
-		filter,
-	)
-	if err != nil || result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Boarding house not found or unauthorized"})
+	err = ctrl.Store.BoardingHouses.Delete(c.Request.Context(), boardingHouseID, ownerObjectID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, response.CodeBoardingHouseNotFound, "Boarding house not found or unauthorized", err)
 		return
 	}
 
 	// Return success message if deletion is successful
-	c.JSON(http.StatusOK, gin.H{"message": "Boarding house deleted successfully"})
+	response.Message(c, http.StatusOK, nil, "Boarding house deleted successfully")
 }
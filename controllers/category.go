@@ -1,116 +1,117 @@
 package controllers
 
 import (
-	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/organisasi/kosconnectbackend/dto"
 	"github.com/organisasi/kosconnectbackend/models"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Create Category
-func CreateCategory(c *gin.Context) {
-	var category models.Category
-	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+func (ctrl *Controller) CreateCategory(c *gin.Context) {
+	var req dto.CategoryRequest
+	if !bind(c, &req, binding.JSON) {
 		return
 	}
 
 	// Generate slug from name aslinya udh di hapus tapi jaga jaga aja
-	category.CategoryID = primitive.NewObjectID()
+	category := models.Category{
+		CategoryID: primitive.NewObjectID(),
+		Name:       req.Name,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
 
-	collection := config.DB.Collection("categories")
-	_, err := collection.InsertOne(context.TODO(), category)
+	err := ctrl.Store.Categories.Create(c.Request.Context(), &category)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to create category", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Category created successfully"})
+	response.Message(c, http.StatusOK, category, "Category created successfully")
 }
 
 // Get All Categories
-func GetAllCategories(c *gin.Context) {
-	collection := config.DB.Collection("categories")
-	cursor, err := collection.Find(context.TODO(), bson.M{})
+func (ctrl *Controller) GetAllCategories(c *gin.Context) {
+	categories, err := ctrl.Store.Categories.FindAll(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
-		return
-	}
-	defer cursor.Close(context.TODO())
-
-	var categories []models.Category
-	if err := cursor.All(context.TODO(), &categories); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse categories"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch categories", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, categories)
+	response.OK(c, categories)
 }
 
 // Get Category by ID
-func GetCategoryByID(c *gin.Context) {
+func (ctrl *Controller) GetCategoryByID(c *gin.Context) {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid category ID")
 		return
 	}
 
-	collection := config.DB.Collection("categories")
-	var category models.Category
-	err = collection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&category)
+	category, err := ctrl.Store.Categories.FindByID(c.Request.Context(), objID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		respondError(c, http.StatusNotFound, response.CodeCategoryNotFound, "Category not found", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, category)
+	response.OK(c, category)
 }
 
 // Update Category
-func UpdateCategory(c *gin.Context) {
+func (ctrl *Controller) UpdateCategory(c *gin.Context) {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid category ID")
 		return
 	}
 
-	var category models.Category
-	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	var req dto.CategoryRequest
+	if !bind(c, &req, binding.JSON) {
 		return
 	}
 
-	collection := config.DB.Collection("categories")
-	_, err = collection.UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": category})
+	err = ctrl.Store.Categories.Update(c.Request.Context(), objID, models.Category{Name: req.Name, UpdatedAt: time.Now()})
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeCategoryNotFound, "Category not found")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update category", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Category updated successfully"})
+	response.Message(c, http.StatusOK, nil, "Category updated successfully")
 }
 
 // Delete Category
-func DeleteCategory(c *gin.Context) {
+func (ctrl *Controller) DeleteCategory(c *gin.Context) {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid category ID")
 		return
 	}
 
-	collection := config.DB.Collection("categories")
-	_, err = collection.DeleteOne(context.TODO(), bson.M{"_id": objID})
+	err = ctrl.Store.Categories.Delete(c.Request.Context(), objID)
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeCategoryNotFound, "Category not found")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to delete category", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+	response.Message(c, http.StatusOK, nil, "Category deleted successfully")
 }
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/go-github/v68/github"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/googleid"
+	"github.com/organisasi/kosconnectbackend/helper"
+	"github.com/organisasi/kosconnectbackend/middlewares"
+	"github.com/organisasi/kosconnectbackend/query"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
+	"github.com/organisasi/kosconnectbackend/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// Controller menampung dependensi yang dipakai semua handler HTTP.
+type Controller struct {
+	Config *config.Config
+	Store  *store.Stores
+
+	// RateLimiter dipakai routes untuk membatasi grup auth dan booking.
+	// Default in-memory; ganti store-nya sebelum route didaftarkan jika
+	// berjalan di lebih dari satu instance.
+	RateLimiter *middlewares.RateLimiter
+
+	googleOauthConfig oauth2.Config
+	googleIDTokens    *googleid.Verifier
+}
+
+// New membuat Controller dengan config dan store yang diberikan (Mongo atau in-memory).
+func New(cfg *config.Config, stores *store.Stores) *Controller {
+	return &Controller{
+		Config:      cfg,
+		Store:       stores,
+		RateLimiter: middlewares.NewRateLimiter(cfg.RateLimit, middlewares.NewMemoryRateLimitStore()),
+		// Google OAuth Configuration
+		googleOauthConfig: oauth2.Config{
+			RedirectURL:  cfg.Google.RedirectURL,
+			ClientID:     cfg.Google.ClientID,
+			ClientSecret: cfg.Google.ClientSecret,
+			Scopes:       []string{"https://www.googleapis.com/auth/userinfo.profile", "https://www.googleapis.com/auth/userinfo.email"},
+			Endpoint:     google.Endpoint,
+		},
+		googleIDTokens: googleid.NewVerifier(cfg.Google.ClientID, googleid.NewRemoteKeys(cfg.Google.CertsURL)),
+	}
+}
+
+// githubConfig menyiapkan upload gambar ke repository GitHub dari config
+func (ctrl *Controller) githubConfig(filePath string, content []byte) helper.GitHubConfig {
+	return helper.GitHubConfig{
+		AccessToken: ctrl.Config.GitHub.Token,
+		AuthorName:  ctrl.Config.GitHub.AuthorName,
+		AuthorEmail: ctrl.Config.GitHub.AuthorEmail,
+		Org:         ctrl.Config.GitHub.Org,
+		Repo:        ctrl.Config.GitHub.Repo,
+		FilePath:    filePath,
+		FileContent: content,
+		Replace:     true,
+	}
+}
+
+// uploadImage mengupload gambar ke GitHub dengan batas waktu timeouts.github
+func (ctrl *Controller) uploadImage(ctx context.Context, filePath string, content []byte) (*github.RepositoryContentResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, ctrl.Config.Timeouts.GitHub)
+	defer cancel()
+	return helper.UploadFile(ctx, ctrl.githubConfig(filePath, content))
+}
+
+// respondError mengirim response error. Jika penyebabnya operasi yang melewati
+// batas waktu (database, Midtrans, GitHub, SMTP), response menjadi 504 TIMEOUT.
+// Error server dicatat dengan logger milik request; detail err tidak dikirim ke client.
+func respondError(c *gin.Context, status int, code response.Code, message string, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		middlewares.Logger(c).Warn("operation timed out", "error", err)
+		response.Fail(c, http.StatusGatewayTimeout, response.CodeTimeout, "Request timed out")
+		return
+	}
+	if status >= http.StatusInternalServerError {
+		middlewares.Logger(c).Error(message, "error", err)
+	}
+	response.Fail(c, status, code, message)
+}
+
+// bind mengisi dst (DTO dari package dto) dengan binding b lalu menjalankan
+// validasi tag `binding`. Jika gagal, response 400 VALIDATION_FAILED dengan
+// error per field sudah dikirim dan bind mengembalikan false.
+func bind(c *gin.Context, dst any, b binding.Binding) bool {
+	if err := c.ShouldBindWith(dst, b); err != nil {
+		failValidation(c, validation.Errors(err)...)
+		return false
+	}
+	return true
+}
+
+// failValidation mengirim 400 VALIDATION_FAILED dengan daftar error per field
+// di error.details.fields, format yang sama dengan kegagalan bind.
+func failValidation(c *gin.Context, fields ...validation.FieldError) {
+	response.FailDetails(c, http.StatusBadRequest, response.CodeValidationFailed, "Invalid input", gin.H{"fields": fields})
+}
+
+// parseQuery membaca page, limit, cursor, sort dan filter sesuai spec
+// (lihat dto/query.go). Jika gagal, response 400 sudah dikirim.
+func (ctrl *Controller) parseQuery(c *gin.Context, spec query.Spec) (query.Query, bool) {
+	limits := query.Limits{Default: ctrl.Config.Pagination.DefaultLimit, Max: ctrl.Config.Pagination.MaxLimit}
+	q, errs := query.Parse(c.Request.URL.Query(), spec, limits)
+	if len(errs) > 0 {
+		failValidation(c, errs...)
+		return q, false
+	}
+	return q, true
+}
+
+// respondPage mengirim data satu halaman dengan total, limit dan
+// next_cursor di meta. data biasanya page.Items atau hasil mapper dto.
+func respondPage[T any](c *gin.Context, q query.Query, page query.Page[T], data any) {
+	total := page.Total
+	response.Success(c, http.StatusOK, data, &response.Meta{
+		Total:      &total,
+		Page:       q.Page,
+		Limit:      q.Limit,
+		NextCursor: page.NextCursor,
+	})
+}
+
+// parseObjectIDs membaca field form berupa JSON array ObjectID hex, misal
+// facilities=["6756b8e4a1b2c3d4e5f60718"]. Jika gagal, response 400 sudah dikirim.
+func parseObjectIDs(c *gin.Context, field, raw string) ([]primitive.ObjectID, bool) {
+	var ids []primitive.ObjectID
+	if err := json.Unmarshal([]byte(raw), &ids); err != nil {
+		failValidation(c, validation.FieldError{Field: field, Rule: "objectid", Message: "must be a JSON array of 24-character hex ObjectIDs"})
+		return nil, false
+	}
+	return ids, true
+}
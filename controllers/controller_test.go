@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
+)
+
+// blockingUsers meniru database yang tidak menjawab: FindByEmail menunggu
+// sampai context request selesai. Jika err diisi, FindByEmail langsung
+// mengembalikan err.
+type blockingUsers struct {
+	store.UserStore
+	err error
+}
+
+func (u blockingUsers) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	<-ctx.Done()
+	return nil, fmt.Errorf("find user %s: %w", email, ctx.Err())
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) response.Envelope {
+	t.Helper()
+	var env response.Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("body %s: %v", rec.Body, err)
+	}
+	return env
+}
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, tc := range []struct {
+		name     string
+		status   int
+		code     response.Code
+		err      error
+		wantCode int
+		want     response.Code
+	}{
+		{"timeout", http.StatusInternalServerError, response.CodeInternal, fmt.Errorf("find: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, response.CodeTimeout},
+		{"timeout overrides client error", http.StatusUnauthorized, response.CodeInvalidCredentials, context.DeadlineExceeded, http.StatusGatewayTimeout, response.CodeTimeout},
+		{"server error", http.StatusInternalServerError, response.CodeInternal, errors.New("connection refused"), http.StatusInternalServerError, response.CodeInternal},
+		{"canceled is not a timeout", http.StatusInternalServerError, response.CodeInternal, context.Canceled, http.StatusInternalServerError, response.CodeInternal},
+		{"client error", http.StatusNotFound, response.CodeUserNotFound, store.ErrNotFound, http.StatusNotFound, response.CodeUserNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		respondError(c, tc.status, tc.code, "message", tc.err)
+
+		env := decode(t, rec)
+		if rec.Code != tc.wantCode || env.Error == nil || env.Error.Code != tc.want {
+			t.Errorf("%s: got %d %s, want %d %s", tc.name, rec.Code, rec.Body, tc.wantCode, tc.want)
+		}
+		if strings.Contains(rec.Body.String(), fmt.Sprint(tc.err)) {
+			t.Errorf("%s: response leaks the error: %s", tc.name, rec.Body)
+		}
+	}
+}
+
+// TestLoginStoreErrors memastikan hanya user yang tidak ditemukan yang
+// dibalas INVALID_CREDENTIALS; database yang gagal atau timeout tidak
+// ditampilkan sebagai password salah.
+func TestLoginStoreErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, tc := range []struct {
+		name     string
+		users    blockingUsers
+		timeout  time.Duration
+		wantCode int
+		want     response.Code
+	}{
+		{"unknown email", blockingUsers{err: store.ErrNotFound}, 0, http.StatusUnauthorized, response.CodeInvalidCredentials},
+		{"database down", blockingUsers{err: errors.New("server selection error")}, 0, http.StatusInternalServerError, response.CodeInternal},
+		{"database timeout", blockingUsers{}, 20 * time.Millisecond, http.StatusGatewayTimeout, response.CodeTimeout},
+	} {
+		stores := store.NewMemory()
+		tc.users.UserStore = stores.Users
+		stores.Users = tc.users
+		ctrl := New(config.Default(), stores)
+		router := gin.New()
+		router.POST("/auth/login", ctrl.Login)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"email":"a@example.com","password":"password123"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if tc.timeout > 0 {
+			ctx, cancel := context.WithTimeout(req.Context(), tc.timeout)
+			defer cancel()
+			req = req.WithContext(ctx)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if env := decode(t, rec); rec.Code != tc.wantCode || env.Error == nil || env.Error.Code != tc.want {
+			t.Errorf("%s: got %d %s, want %d %s", tc.name, rec.Code, rec.Body, tc.wantCode, tc.want)
+		}
+	}
+}
@@ -1,253 +1,228 @@
 package controllers
 
 import (
-	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/dto"
 	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
+	"github.com/organisasi/kosconnectbackend/validation"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Create CustomFacility
-func CreateCustomFacility(c *gin.Context) {
+func (ctrl *Controller) CreateCustomFacility(c *gin.Context) {
 	claims := c.MustGet("user").(jwt.MapClaims)
 	role, _ := claims["role"].(string)
 
-	var facility models.CustomFacility
-	if err := c.ShouldBindJSON(&facility); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	var req dto.CustomFacilityRequest
+	if !bind(c, &req, binding.JSON) {
 		return
 	}
 
+	facility := models.CustomFacility{
+		CustomFacilityID: primitive.NewObjectID(),
+		Name:             req.Name,
+		Price:            req.Price,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
 	if role == "admin" {
-		if facility.OwnerID == primitive.NilObjectID {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "OwnerID is required for admin"})
+		if req.OwnerID == "" {
+			failValidation(c, validation.FieldError{Field: "owner_id", Rule: "required", Message: "is required for admin"})
 			return
 		}
+		facility.OwnerID, _ = primitive.ObjectIDFromHex(req.OwnerID)
 	} else if role == "owner" {
 		ownerID, _ := primitive.ObjectIDFromHex(claims["user_id"].(string))
 		facility.OwnerID = ownerID
 	}
-
-	facility.CustomFacilityID = primitive.NewObjectID()
-	collection := config.DB.Collection("customFacility")
-	if _, err := collection.InsertOne(context.TODO(), facility); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create custom facility"})
+	if err := ctrl.Store.CustomFacilities.Create(c.Request.Context(), &facility); err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to create custom facility", err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Custom facility created successfully",
-		"data":    facility,
-	})
+	response.Message(c, http.StatusCreated, facility, "Custom facility created successfully")
 }
 
 // Get All CustomFacilities
-func GetAllCustomFacilities(c *gin.Context) {
-	collection := config.DB.Collection("customFacility")
-	var facilities []models.CustomFacility
-
-	cursor, err := collection.Find(context.TODO(), bson.M{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom facilities"})
+func (ctrl *Controller) GetAllCustomFacilities(c *gin.Context) {
+	q, ok := ctrl.parseQuery(c, dto.CustomFacilityListQuery)
+	if !ok {
 		return
 	}
-	defer cursor.Close(context.TODO())
 
-	if err := cursor.All(context.TODO(), &facilities); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse custom facilities"})
+	page, err := ctrl.Store.CustomFacilities.List(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch custom facilities", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, facilities)
+	respondPage(c, q, page, page.Items)
 }
 
 // Get CustomFacility by ID
-func GetCustomFacilityByID(c *gin.Context) {
+func (ctrl *Controller) GetCustomFacilityByID(c *gin.Context) {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid facility ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid facility ID")
 		return
 	}
 
-	collection := config.DB.Collection("customFacility")
-	var facility models.CustomFacility
-	if err := collection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&facility); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Custom facility not found"})
+	facility, err := ctrl.Store.CustomFacilities.FindByID(c.Request.Context(), objID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, response.CodeCustomFacilityNotFound, "Custom facility not found", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, facility)
+	response.OK(c, facility)
 }
 
 // Get CustomFacilities by OwnerID
-func GetCustomFacilitiesByOwnerID(c *gin.Context) {
+func (ctrl *Controller) GetCustomFacilitiesByOwnerID(c *gin.Context) {
 	// Ambil klaim user dari JWT
 	claims := c.MustGet("user").(jwt.MapClaims)
 
 	if role, ok := claims["role"].(string); !ok || role != "owner" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only owners can access their custom facilities"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only owners can access their custom facilities")
 		return
 	}
 
 	ownerID, err := primitive.ObjectIDFromHex(claims["user_id"].(string))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid owner ID")
 		return
 	}
 
-	collection := config.DB.Collection("customFacility")
-	var facilities []models.CustomFacility
-
-	cursor, err := collection.Find(context.TODO(), bson.M{"owner_id": ownerID})
+	facilities, err := ctrl.Store.CustomFacilities.FindByOwner(c.Request.Context(), ownerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom facilities"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch custom facilities", err)
 		return
 	}
-	defer cursor.Close(context.TODO())
 
-	for cursor.Next(context.TODO()) {
-		var facility models.CustomFacility
-		if err := cursor.Decode(&facility); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding custom facility"})
-			return
-		}
-		facilities = append(facilities, facility)
-	}
-
-	c.JSON(http.StatusOK, facilities)
+	response.OK(c, facilities)
 }
 
 // Get CustomFacilities by OwnerID (Admin - via Query Parameter)
-func GetCustomFacilitiesByOwnerIDAdmin(c *gin.Context) {
+func (ctrl *Controller) GetCustomFacilitiesByOwnerIDAdmin(c *gin.Context) {
 	claims := c.MustGet("user").(jwt.MapClaims)
 	role, _ := claims["role"].(string)
 
 	if role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can access this"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only admins can access this")
 		return
 	}
 
 	ownerIDStr := c.Query("owner_id")
 	if ownerIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "owner_id is required"})
+		response.Fail(c, http.StatusBadRequest, response.CodeValidationFailed, "owner_id is required")
 		return
 	}
 
 	ownerID, err := primitive.ObjectIDFromHex(ownerIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid owner ID")
 		return
 	}
 
-	collection := config.DB.Collection("customFacility")
-	var facilities []models.CustomFacility
-
-	cursor, err := collection.Find(context.TODO(), bson.M{"owner_id": ownerID})
+	facilities, err := ctrl.Store.CustomFacilities.FindByOwner(c.Request.Context(), ownerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom facilities"})
-		return
-	}
-	defer cursor.Close(context.TODO())
-
-	if err := cursor.All(context.TODO(), &facilities); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse custom facilities"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch custom facilities", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, facilities)
+	response.OK(c, facilities)
 }
 
 // Update CustomFacility
-func UpdateCustomFacility(c *gin.Context) {
+func (ctrl *Controller) UpdateCustomFacility(c *gin.Context) {
 	claims := c.MustGet("user").(jwt.MapClaims)
 	role, _ := claims["role"].(string)
 
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid facility ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid facility ID")
 		return
 	}
 
-	var updateData models.CustomFacility
-	if err := c.ShouldBindJSON(&updateData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	var updateData dto.CustomFacilityRequest
+	if !bind(c, &updateData, binding.JSON) {
 		return
 	}
 
-	collection := config.DB.Collection("customFacility")
-	filter := bson.M{"_id": objID}
-
+	// Owner hanya bisa mengupdate fasilitas miliknya sendiri
+	var ownerID primitive.ObjectID
 	if role == "owner" {
-		ownerID, _ := primitive.ObjectIDFromHex(claims["user_id"].(string))
-		filter["owner_id"] = ownerID
+		ownerID, _ = primitive.ObjectIDFromHex(claims["user_id"].(string))
 	}
 
-	update := bson.M{"$set": bson.M{"name": updateData.Name, "price": updateData.Price}}
-	if _, err := collection.UpdateOne(context.TODO(), filter, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update custom facility"})
+	update := bson.M{"name": updateData.Name, "price": updateData.Price, "updated_at": time.Now()}
+	err = ctrl.Store.CustomFacilities.Update(c.Request.Context(), objID, ownerID, update)
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeCustomFacilityNotFound, "Custom facility not found or unauthorized")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update custom facility", err)
 		return
 	}
 
-	var updatedFacility models.CustomFacility
-	if err := collection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&updatedFacility); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated facility"})
+	updatedFacility, err := ctrl.Store.CustomFacilities.FindByID(c.Request.Context(), objID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch updated facility", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Custom facility updated successfully",
-		"data":    updatedFacility,
-	})
+	response.Message(c, http.StatusOK, updatedFacility, "Custom facility updated successfully")
 }
 
 // Delete CustomFacility
-func DeleteCustomFacility(c *gin.Context) {
+func (ctrl *Controller) DeleteCustomFacility(c *gin.Context) {
 	claims := c.MustGet("user").(jwt.MapClaims)
 	role, _ := claims["role"].(string)
 
 	// Hanya admin atau owner yang boleh menghapus
 	if role != "admin" && role != "owner" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized access"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Unauthorized access")
 		return
 	}
 
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid facility ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid facility ID")
 		return
 	}
 
-	// Default filter hanya berdasarkan _id (admin dapat menghapus apa saja)
-	filter := bson.M{"_id": objID}
+	// Default tanpa owner (admin dapat menghapus apa saja)
+	var ownerID primitive.ObjectID
 
 	// Jika role adalah owner, hanya bisa menghapus fasilitas miliknya sendiri
 	if role == "owner" {
-		ownerID, _ := primitive.ObjectIDFromHex(claims["user_id"].(string))
-		filter["owner_id"] = ownerID
+		ownerID, _ = primitive.ObjectIDFromHex(claims["user_id"].(string))
 	}
 
-	collection := config.DB.Collection("customFacility")
-
 	// Lakukan penghapusan berdasarkan filter yang sudah disesuaikan
-	result, err := collection.DeleteOne(context.TODO(), filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete custom facility"})
-		return
-	}
+	err = ctrl.Store.CustomFacilities.Delete(c.Request.Context(), objID, ownerID)
 
 	// Jika tidak ada dokumen yang dihapus, berarti fasilitas tidak ditemukan atau owner mencoba menghapus milik orang lain
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Custom facility not found or unauthorized"})
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeCustomFacilityNotFound, "Custom facility not found or unauthorized")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to delete custom facility", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Custom facility deleted successfully"})
+	response.Message(c, http.StatusOK, nil, "Custom facility deleted successfully")
 }
\ No newline at end of file
@@ -1,174 +1,143 @@
 package controllers
 
 import (
-	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/organisasi/kosconnectbackend/dto"
 	"github.com/organisasi/kosconnectbackend/models"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Create Facility
-func CreateFacility(c *gin.Context) {
-	var facility models.Facility
-	if err := c.ShouldBindJSON(&facility); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
-		return
-	}
-
-	// Validasi field Type
-	if facility.Type != "room" && facility.Type != "boarding_house" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Type must be either 'room' or 'boarding_house'"})
+func (ctrl *Controller) CreateFacility(c *gin.Context) {
+	var req dto.FacilityRequest
+	if !bind(c, &req, binding.JSON) {
 		return
 	}
 
 	// Set ID baru untuk fasilitas
-	facility.FacilityID = primitive.NewObjectID()
+	facility := models.Facility{
+		FacilityID: primitive.NewObjectID(),
+		Name:       req.Name,
+		Type:       req.Type,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
 
 	// Simpan ke database
-	collection := config.DB.Collection("facilities")
-	_, err := collection.InsertOne(context.TODO(), facility)
+	err := ctrl.Store.Facilities.Create(c.Request.Context(), &facility)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create facility"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to create facility", err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Facility created successfully", "data": facility})
+	response.Message(c, http.StatusCreated, facility, "Facility created successfully")
 }
 
 // Get All Facilities
-func GetAllFacilities(c *gin.Context) {
-	collection := config.DB.Collection("facilities")
-	cursor, err := collection.Find(context.TODO(), bson.M{})
+func (ctrl *Controller) GetAllFacilities(c *gin.Context) {
+	facilities, err := ctrl.Store.Facilities.FindAll(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch facilities"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch facilities", err)
 		return
 	}
-	defer cursor.Close(context.TODO())
 
-	var facilities []models.Facility
-	if err := cursor.All(context.TODO(), &facilities); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse facilities"})
-		return
-	}
-
-	c.JSON(http.StatusOK, facilities)
+	response.OK(c, facilities)
 }
 
 // Get Facility by ID
-func GetFacilityByID(c *gin.Context) {
+func (ctrl *Controller) GetFacilityByID(c *gin.Context) {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid facility ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid facility ID")
 		return
 	}
 
-	collection := config.DB.Collection("facilities")
-	var facility models.Facility
-	err = collection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&facility)
+	facility, err := ctrl.Store.Facilities.FindByID(c.Request.Context(), objID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Facility not found"})
+		respondError(c, http.StatusNotFound, response.CodeFacilityNotFound, "Facility not found", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, facility)
+	response.OK(c, facility)
 }
 
 // GetFacilitiesByType retrieves facilities by type (room or boarding_house)
-func GetFacilitiesByType(c *gin.Context) {
+func (ctrl *Controller) GetFacilitiesByType(c *gin.Context) {
 	// Get the 'type' query parameter from the request
 	facilityType := c.DefaultQuery("type", "")
 
 	// Validate the 'type' query parameter
 	if facilityType != "room" && facilityType != "boarding_house" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Type must be either 'room' or 'boarding_house'"})
+		response.Fail(c, http.StatusBadRequest, response.CodeValidationFailed, "Type must be either 'room' or 'boarding_house'")
 		return
 	}
 
 	// Query the database to get the facilities by type
-	collection := config.DB.Collection("facilities")
-	filter := bson.M{"type": facilityType}
-
-	var facilities []models.Facility
-	cursor, err := collection.Find(context.TODO(), filter)
+	facilities, err := ctrl.Store.Facilities.FindByType(c.Request.Context(), facilityType)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve facilities"})
-		return
-	}
-	defer cursor.Close(context.TODO())
-
-	// Loop through the cursor and append each document to the facilities slice
-	for cursor.Next(context.TODO()) {
-		var facility models.Facility
-		if err := cursor.Decode(&facility); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode facility data"})
-			return
-		}
-		facilities = append(facilities, facility)
-	}
-
-	// Check for any cursor error
-	if err := cursor.Err(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Cursor error"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to retrieve facilities", err)
 		return
 	}
 
 	// Return the facilities data
-	c.JSON(http.StatusOK, gin.H{"data": facilities})
+	response.OK(c, facilities)
 }
 
 // Update Facility
-func UpdateFacility(c *gin.Context) {
+func (ctrl *Controller) UpdateFacility(c *gin.Context) {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid facility ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid facility ID")
 		return
 	}
 
-	var facility models.Facility
-	if err := c.ShouldBindJSON(&facility); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	var req dto.FacilityRequest
+	if !bind(c, &req, binding.JSON) {
 		return
 	}
 
-	// Validasi field Type
-	if facility.Type != "room" && facility.Type != "boarding_house" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Type must be either 'room' or 'boarding_house'"})
+	// Update data di database
+	facility := models.Facility{Name: req.Name, Type: req.Type, UpdatedAt: time.Now()}
+	err = ctrl.Store.Facilities.Update(c.Request.Context(), objID, facility)
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeFacilityNotFound, "Facility not found")
 		return
 	}
-
-	// Update data di database
-	collection := config.DB.Collection("facilities")
-	update := bson.M{"$set": facility}
-	_, err = collection.UpdateOne(context.TODO(), bson.M{"_id": objID}, update)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update facility"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update facility", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Facility updated successfully"})
+	response.Message(c, http.StatusOK, nil, "Facility updated successfully")
 }
 
 // Delete Facility
-func DeleteFacility(c *gin.Context) {
+func (ctrl *Controller) DeleteFacility(c *gin.Context) {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid facility ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid facility ID")
 		return
 	}
 
-	collection := config.DB.Collection("facilities")
-	_, err = collection.DeleteOne(context.TODO(), bson.M{"_id": objID})
+	err = ctrl.Store.Facilities.Delete(c.Request.Context(), objID)
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeFacilityNotFound, "Facility not found")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete facility"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to delete facility", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Facility deleted successfully"})
+	response.Message(c, http.StatusOK, nil, "Facility deleted successfully")
 }
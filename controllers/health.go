@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/organisasi/kosconnectbackend/response"
+)
+
+// dependencyStatus adalah hasil pemeriksaan satu dependency di /readyz
+type dependencyStatus struct {
+	Status    string   `json:"status"` // "ok" atau "error"
+	Error     string   `json:"error,omitempty"`
+	LatencyMS *float64 `json:"latency_ms,omitempty"`
+}
+
+// Healthz (liveness) selalu menjawab selama proses masih berjalan
+func (ctrl *Controller) Healthz(c *gin.Context) {
+	response.OK(c, gin.H{"status": "ok"})
+}
+
+// Readyz (readiness) memeriksa MongoDB dan konfigurasi Midtrans serta GitHub.
+// Response 503 NOT_READY jika salah satu dependency bermasalah, dengan hasil
+// pemeriksaan di error.details.
+func (ctrl *Controller) Readyz(c *gin.Context) {
+	checks := map[string]dependencyStatus{
+		"mongodb":  ctrl.checkMongo(c),
+		"midtrans": checkConfigured(ctrl.Config.Midtrans.ServerKey, "MIDTRANS_SERVER_KEY"),
+		"github":   checkConfigured(ctrl.Config.GitHub.Token, "GH_ACCESS_TOKEN"),
+	}
+
+	for _, check := range checks {
+		if check.Status != "ok" {
+			response.FailDetails(c, http.StatusServiceUnavailable, response.CodeNotReady, "One or more dependencies are not ready", gin.H{"checks": checks})
+			return
+		}
+	}
+
+	response.OK(c, gin.H{"status": "ready", "checks": checks})
+}
+
+func (ctrl *Controller) checkMongo(c *gin.Context) dependencyStatus {
+	start := time.Now()
+	err := ctrl.Store.Ping(c.Request.Context())
+	latency := float64(time.Since(start).Microseconds()) / 1000
+
+	if err != nil {
+		return dependencyStatus{Status: "error", Error: err.Error(), LatencyMS: &latency}
+	}
+	return dependencyStatus{Status: "ok", LatencyMS: &latency}
+}
+
+func checkConfigured(value, envName string) dependencyStatus {
+	if value == "" {
+		return dependencyStatus{Status: "error", Error: envName + " is not configured"}
+	}
+	return dependencyStatus{Status: "ok"}
+}
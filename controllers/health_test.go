@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
+)
+
+func TestHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ready := config.Default()
+	ready.Midtrans.ServerKey = "server-key"
+	ready.GitHub.Token = "gh-token"
+	noGitHub := config.Default()
+	noGitHub.Midtrans.ServerKey = "server-key"
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, tc := range []struct {
+		name   string
+		cfg    *config.Config
+		path   string
+		ctx    context.Context
+		status int
+		failed []string // dependency yang harus berstatus error
+	}{
+		{"liveness", noGitHub, "/healthz", context.Background(), http.StatusOK, nil},
+		// Liveness tidak memeriksa dependency sama sekali
+		{"liveness while database is down", noGitHub, "/healthz", canceled, http.StatusOK, nil},
+		{"ready", ready, "/readyz", context.Background(), http.StatusOK, nil},
+		{"missing GitHub token", noGitHub, "/readyz", context.Background(), http.StatusServiceUnavailable, []string{"github"}},
+		{"nothing configured", config.Default(), "/readyz", context.Background(), http.StatusServiceUnavailable, []string{"midtrans", "github"}},
+		// Ping store memori gagal jika context request sudah selesai
+		{"database down", ready, "/readyz", canceled, http.StatusServiceUnavailable, []string{"mongodb"}},
+	} {
+		ctrl := New(tc.cfg, store.NewMemory())
+		router := gin.New()
+		router.GET("/healthz", ctrl.Healthz)
+		router.GET("/readyz", ctrl.Readyz)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil).WithContext(tc.ctx))
+
+		if rec.Code != tc.status {
+			t.Errorf("%s: GET %s = %d, want %d\n%s", tc.name, tc.path, rec.Code, tc.status, rec.Body)
+			continue
+		}
+		if tc.path == "/healthz" {
+			continue
+		}
+
+		// Hasil pemeriksaan ada di data (siap) atau error.details (tidak siap)
+		var body struct {
+			Data  struct{ Checks map[string]dependencyStatus } `json:"data"`
+			Error *struct {
+				Code    response.Code                                `json:"code"`
+				Details struct{ Checks map[string]dependencyStatus } `json:"details"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		checks := body.Data.Checks
+		if body.Error != nil {
+			if body.Error.Code != response.CodeNotReady {
+				t.Errorf("%s: error code %s, want %s", tc.name, body.Error.Code, response.CodeNotReady)
+			}
+			checks = body.Error.Details.Checks
+		}
+		failed := map[string]bool{}
+		for _, name := range tc.failed {
+			failed[name] = true
+		}
+		for _, name := range []string{"mongodb", "midtrans", "github"} {
+			check, ok := checks[name]
+			if !ok {
+				t.Errorf("%s: check %s missing from %s", tc.name, name, rec.Body)
+				continue
+			}
+			if want := map[bool]string{true: "error", false: "ok"}[failed[name]]; check.Status != want {
+				t.Errorf("%s: %s status %q, want %q", tc.name, name, check.Status, want)
+			}
+			if failed[name] && check.Error == "" {
+				t.Errorf("%s: failed check %s has no error message", tc.name, name)
+			}
+		}
+		if checks["mongodb"].LatencyMS == nil {
+			t.Errorf("%s: mongodb check has no latency_ms", tc.name)
+		}
+	}
+}
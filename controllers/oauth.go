@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/organisasi/kosconnectbackend/helper"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/store"
+	"golang.org/x/oauth2"
+)
+
+// oauthStateCookie menyimpan state dan PKCE verifier satu login Google,
+// dari /auth/google/login sampai /auth/callback.
+const (
+	oauthStateCookie   = "oauthState"
+	oauthStateAudience = "oauth_state" // Membedakan cookie ini dari access token
+)
+
+var errInvalidOAuthState = errors.New("invalid or expired oauth state")
+
+// oauthStateClaims adalah isi cookie oauthState, ditandatangani dengan
+// JWTSecret. Verifier tidak pernah dikirim ke Google, hanya challenge-nya.
+type oauthStateClaims struct {
+	State    string `json:"state"`
+	Verifier string `json:"verifier"`
+	jwt.RegisteredClaims
+}
+
+// newOAuthState membuat state dan PKCE verifier baru lalu menyimpannya di
+// cookie oauthState yang hanya dikirim ke /auth.
+func (ctrl *Controller) newOAuthState(c *gin.Context) (state, verifier string, err error) {
+	state, verifier = helper.RandomToken(), oauth2.GenerateVerifier()
+	ttl := ctrl.Config.Google.StateTTL
+	cookie, err := jwt.NewWithClaims(jwt.SigningMethodHS256, oauthStateClaims{
+		State:    state,
+		Verifier: verifier,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oauthStateAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}).SignedString([]byte(ctrl.Config.JWTSecret))
+	if err != nil {
+		return "", "", err
+	}
+
+	// Lax agar cookie ikut terkirim saat Google me-redirect kembali ke callback
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, cookie, int(ttl.Seconds()), "/auth", "", true, true)
+	return state, verifier, nil
+}
+
+// useOAuthState memeriksa state dari query callback terhadap cookie
+// oauthState, mencatat state sebagai terpakai, lalu mengembalikan PKCE
+// verifier-nya. Cookie selalu dihapus, berhasil atau tidak.
+// errInvalidOAuthState jika cookie tidak ada, palsu, kedaluwarsa, state
+// tidak cocok, atau state sudah pernah dipakai.
+func (ctrl *Controller) useOAuthState(c *gin.Context, state string) (string, error) {
+	cookie, _ := c.Cookie(oauthStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, "", -1, "/auth", "", true, true)
+	if cookie == "" || state == "" {
+		return "", errInvalidOAuthState
+	}
+
+	var claims oauthStateClaims
+	_, err := jwt.ParseWithClaims(cookie, &claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(ctrl.Config.JWTSecret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(oauthStateAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || subtle.ConstantTimeCompare([]byte(claims.State), []byte(state)) != 1 {
+		return "", errInvalidOAuthState
+	}
+
+	err = ctrl.Store.OAuthStates.Use(c.Request.Context(), &models.OAuthState{
+		ID:        helper.HashToken(state),
+		UsedAt:    time.Now(),
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	if errors.Is(err, store.ErrDuplicate) {
+		return "", errInvalidOAuthState
+	}
+	if err != nil {
+		return "", err
+	}
+	return claims.Verifier, nil
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/organisasi/kosconnectbackend/dto"
+	"github.com/organisasi/kosconnectbackend/helper"
+	"github.com/organisasi/kosconnectbackend/middlewares"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ForgotPassword mengirim link reset password ke email user. Response-nya
+// selalu sama, baik email terdaftar maupun tidak, dan email dikirim di
+// background agar waktu response juga tidak membedakannya.
+func (ctrl *Controller) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if !bind(c, &req, binding.JSON) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := ctrl.Store.Users.FindByEmail(ctx, req.Email)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to process request", err)
+		return
+	}
+	if err == nil {
+		if err := ctrl.sendPasswordReset(c, user); err != nil {
+			respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to process request", err)
+			return
+		}
+	}
+
+	response.Message(c, http.StatusOK, nil, "If the email is registered, a password reset link has been sent.")
+}
+
+// sendPasswordReset membuat token reset baru untuk user, membatalkan token
+// sebelumnya, lalu mengirim email di background.
+func (ctrl *Controller) sendPasswordReset(c *gin.Context, user *models.User) error {
+	ctx := c.Request.Context()
+	if _, err := ctrl.Store.PasswordResets.UseAll(ctx, user.UserID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	token := helper.RandomToken()
+	reset := models.PasswordReset{
+		ID:        primitive.NewObjectID(),
+		UserID:    user.UserID,
+		TokenHash: helper.HashToken(token),
+		IP:        c.ClientIP(),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ctrl.Config.Auth.PasswordResetTTL),
+	}
+	if err := ctrl.Store.PasswordResets.Create(ctx, &reset); err != nil {
+		return err
+	}
+
+	logger := middlewares.Logger(c)
+	link := helper.PasswordResetLink(ctrl.Config.FrontendURL, token)
+	// Request sudah selesai saat email dikirim: context request tidak boleh membatalkannya, batasnya timeouts.smtp
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ctrl.Config.Timeouts.SMTP)
+	go func() {
+		defer cancel()
+		err := helper.SendPasswordResetEmail(sendCtx, ctrl.Config.SMTP, user.Email, link, user.FullName, ctrl.Config.Auth.PasswordResetTTL)
+		if err != nil {
+			logger.Error("failed to send password reset email", "user_id", user.UserID.Hex(), "error", err)
+		}
+	}()
+	return nil
+}
+
+// ResetForgottenPassword mengganti password dengan token dari email
+// forgot-password. Token hanya bisa dipakai sekali, dan semua session user
+// dicabut agar perangkat yang mungkin dipakai orang lain ikut logout.
+func (ctrl *Controller) ResetForgottenPassword(c *gin.Context) {
+	var req dto.ResetForgottenPasswordRequest
+	if !bind(c, &req, binding.JSON) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	invalid := func() {
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidToken, "Invalid or expired reset token")
+	}
+	reset, err := ctrl.Store.PasswordResets.FindByTokenHash(ctx, helper.HashToken(req.Token))
+	if errors.Is(err, store.ErrNotFound) {
+		invalid()
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to reset password", err)
+		return
+	}
+	if !reset.Usable(time.Now()) {
+		invalid()
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to hash password", err)
+		return
+	}
+
+	// Tandai terpakai sebelum password diganti; request bersamaan dengan token yang sama gagal di sini
+	err = ctrl.Store.PasswordResets.Use(ctx, reset.ID)
+	if errors.Is(err, store.ErrNotFound) {
+		invalid()
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to reset password", err)
+		return
+	}
+
+	err = ctrl.Store.Users.Update(ctx, reset.UserID, bson.M{"password": string(hashedPassword), "updated_at": time.Now()})
+	if errors.Is(err, store.ErrNotFound) {
+		invalid()
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to reset password", err)
+		return
+	}
+
+	if _, err := ctrl.Store.PasswordResets.UseAll(ctx, reset.UserID); err != nil {
+		middlewares.Logger(c).Error("failed to invalidate other password reset tokens", "user_id", reset.UserID.Hex(), "error", err)
+	}
+	if _, err := ctrl.Store.Sessions.RevokeAll(ctx, reset.UserID); err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Password was reset but failed to log out existing sessions", err)
+		return
+	}
+
+	response.Message(c, http.StatusOK, nil, "Password reset successfully. Please log in with your new password.")
+}
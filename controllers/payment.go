@@ -2,40 +2,43 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	// "fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/snap"
 	"github.com/organisasi/kosconnectbackend/config"
-	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/dto"
+	"github.com/organisasi/kosconnectbackend/metrics"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func CreatePayment(c *gin.Context) {
+func (ctrl *Controller) CreatePayment(c *gin.Context) {
 	// Ambil parameter transaction_id dari request
 	transactionID := c.Param("transaction_id")
 	if transactionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Transaction ID is required"})
+		response.Fail(c, http.StatusBadRequest, response.CodeValidationFailed, "Transaction ID is required")
 		return
 	}
 
 	// Validasi ObjectID
 	objectID, err := primitive.ObjectIDFromHex(transactionID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Transaction ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid Transaction ID")
 		return
 	}
 
 	// Ambil data transaksi dari database
-	transactionCollection := config.DB.Collection("transactions")
-	var transaction models.Transaction
-	err = transactionCollection.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&transaction)
+	transaction, err := ctrl.Store.Transactions.FindByID(c.Request.Context(), objectID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+		respondError(c, http.StatusNotFound, response.CodeTransactionNotFound, "Transaction not found", err)
 		return
 	}
 
@@ -53,45 +56,46 @@ func CreatePayment(c *gin.Context) {
 	}
 
 	// Panggil API Midtrans Snap untuk membuat transaksi pembayaran
-	snapResp, snapErr := config.SnapClient.CreateTransaction(snapReq)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctrl.Config.Timeouts.Midtrans)
+	defer cancel()
+	snapResp, snapErr := config.CreateSnapTransaction(ctx, snapReq)
 	if snapErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payment: " + snapErr.Error()})
+		if errors.Is(snapErr, context.DeadlineExceeded) {
+			metrics.MidtransRequests.WithLabelValues(metrics.OutcomeTimeout).Inc()
+		} else {
+			metrics.MidtransRequests.WithLabelValues(metrics.OutcomeFailure).Inc()
+		}
+		respondError(c, http.StatusBadGateway, response.CodePaymentFailed, "Failed to create payment", snapErr)
 		return
 	}
 
 	// Pastikan respons Snap mengandung token dan RedirectURL
 	if snapResp.Token == "" || snapResp.RedirectURL == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid Snap response"})
+		metrics.MidtransRequests.WithLabelValues(metrics.OutcomeInvalidResponse).Inc()
+		response.Fail(c, http.StatusInternalServerError, response.CodePaymentFailed, "Invalid Snap response")
 		return
 	}
+	metrics.MidtransRequests.WithLabelValues(metrics.OutcomeSuccess).Inc()
 
 	// Kirim respons dengan Redirect URL dari Midtrans
-	c.JSON(http.StatusOK, gin.H{
-		"message":     "Payment created successfully",
+	response.Message(c, http.StatusOK, gin.H{
 		"redirectURL": snapResp.RedirectURL,
-	})
+	}, "Payment created successfully")
 }
 
-func PaymentNotification(c *gin.Context) {
-	var notificationPayload map[string]interface{}
-
+func (ctrl *Controller) PaymentNotification(c *gin.Context) {
 	// Bind payload dari request
-	if err := c.ShouldBindJSON(&notificationPayload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification payload"})
+	var notification dto.PaymentNotification
+	if !bind(c, &notification, binding.JSON) {
 		return
 	}
 
 	// Ambil OrderID, Status, dan Payment Method dari payload
-	orderID, ok := notificationPayload["order_id"].(string)
-	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID format"})
-		return
-	}
-	transactionStatus := notificationPayload["transaction_status"].(string)
-	paymentMethod := notificationPayload["payment_type"].(string) // Menambahkan payment_type
+	orderID := notification.OrderID
+	transactionStatus := notification.TransactionStatus
+	paymentMethod := notification.PaymentType // Menambahkan payment_type
 
 	// Update status pembayaran di database berdasarkan status
-	transactionCollection := config.DB.Collection("transactions")
 	updateFields := bson.M{
 		"payment_status": transactionStatus,
 		"payment_method": paymentMethod,  // Menambahkan payment_method
@@ -109,15 +113,12 @@ func PaymentNotification(c *gin.Context) {
 		updateFields["payment_status_detail"] = "Pembayaran ditolak"
 	}
 
-	_, err := transactionCollection.UpdateOne(
-		context.TODO(),
-		bson.M{"transaction_code": orderID},
-		bson.M{"$set": updateFields},
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transaction status"})
+	// Order yang tidak dikenal tetap dibalas 200 agar Midtrans tidak mengulang notifikasi
+	err := ctrl.Store.Transactions.UpdateByCode(c.Request.Context(), orderID, updateFields)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update transaction status", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Payment status updated successfully"})
+	response.Message(c, http.StatusOK, nil, "Payment status updated successfully")
 }
@@ -1,25 +1,26 @@
 package controllers
 
 import (
-	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"path"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"github.com/organisasi/kosconnectbackend/config"
-	"github.com/organisasi/kosconnectbackend/helper"
+	"github.com/organisasi/kosconnectbackend/dto"
+	"github.com/organisasi/kosconnectbackend/middlewares"
+	"github.com/organisasi/kosconnectbackend/response"
 	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/store"
+	"github.com/organisasi/kosconnectbackend/validation"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
@@ -30,21 +31,22 @@ func formatrupiah(price float64) string {
 	return formatter.Sprintf("Rp %.2f", price)
 }
 
-func CreateRoom(c *gin.Context) {
+// priceRequired dikirim jika semua harga kamar kosong atau 0
+var priceRequired = validation.FieldError{Field: "price_monthly", Rule: "required", Message: "at least one of price_monthly, price_quarterly, price_semi_annual or price_yearly must be set"}
+
+func (ctrl *Controller) CreateRoom(c *gin.Context) {
 	// Ambil boardingHouseID dari URL
 	boardingHouseIDStr := c.Param("boardingHouseID")
 	boardingHouseID, err := primitive.ObjectIDFromHex(boardingHouseIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid BoardingHouseID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid BoardingHouseID")
 		return
 	}
 
 	// Ambil ownerID dari BoardingHouse
-	var boardingHouse models.BoardingHouse
-	collectionBoardingHouse := config.DB.Collection("boardinghouses")
-	err = collectionBoardingHouse.FindOne(context.TODO(), bson.M{"_id": boardingHouseID}).Decode(&boardingHouse)
+	boardingHouse, err := ctrl.Store.BoardingHouses.FindByID(c.Request.Context(), boardingHouseID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Boarding house not found"})
+		respondError(c, http.StatusNotFound, response.CodeBoardingHouseNotFound, "Boarding house not found", err)
 		return
 	}
 	ownerID := boardingHouse.OwnerID // Ambil ownerID dari data BoardingHouse
@@ -52,42 +54,34 @@ func CreateRoom(c *gin.Context) {
 	// Parse form-data
 	err = c.Request.ParseMultipartForm(10 << 20)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse form-data"})
+		response.Fail(c, http.StatusBadRequest, response.CodeValidationFailed, "Failed to parse form-data")
 		return
 	}
 
-	// Ambil field dari form-data
-	roomType := c.PostForm("room_type")
-	size := c.PostForm("size")
-
-	// Parsing harga dengan default 0 jika kosong
-	priceMonthly, _ := strconv.Atoi(c.DefaultPostForm("price_monthly", "0"))
-	priceQuarterly, _ := strconv.Atoi(c.DefaultPostForm("price_quarterly", "0"))
-	priceSemiAnnual, _ := strconv.Atoi(c.DefaultPostForm("price_semi_annual", "0"))
-	priceYearly, _ := strconv.Atoi(c.DefaultPostForm("price_yearly", "0"))
+	// Ambil dan validasi field dari form-data
+	var req dto.RoomForm
+	if !bind(c, &req, binding.FormMultipart) {
+		return
+	}
+	price := req.Price()
+	if price == (models.RoomPrice{}) {
+		failValidation(c, priceRequired)
+		return
+	}
 
 	// Parse Room Facilities
-	var roomFacilities []primitive.ObjectID
-	roomFacilitiesJSON := c.PostForm("room_facilities")
-	if err := json.Unmarshal([]byte(roomFacilitiesJSON), &roomFacilities); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room facilities format"})
+	roomFacilities, ok := parseObjectIDs(c, "room_facilities", req.RoomFacilities)
+	if !ok {
 		return
 	}
 
 	// Validasi Room Facilities
-	collectionFacilities := config.DB.Collection("facilities")
 	validRoomFacilities := []primitive.ObjectID{}
 
 	for _, facilityID := range roomFacilities {
-		err := collectionFacilities.FindOne(context.TODO(), bson.M{
-			"_id":  facilityID,
-			"type": "room",
-		}).Err()
-
+		_, err := ctrl.Store.Facilities.FindByIDAndType(c.Request.Context(), facilityID, "room")
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Room facility with ID %s is not valid or not of type 'room'", facilityID.Hex()),
-			})
+			respondError(c, http.StatusBadRequest, response.CodeValidationFailed, fmt.Sprintf("Room facility with ID %s is not valid or not of type 'room'", facilityID.Hex()), err)
 			return
 		}
 
@@ -95,26 +89,17 @@ func CreateRoom(c *gin.Context) {
 	}
 
 	// Parse Custom Facilities
-	var customFacilities []primitive.ObjectID
-	customFacilitiesJSON := c.PostForm("custom_facilities")
-	if err := json.Unmarshal([]byte(customFacilitiesJSON), &customFacilities); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid custom facilities format"})
+	customFacilities, ok := parseObjectIDs(c, "custom_facilities", req.CustomFacilities)
+	if !ok {
 		return
 	}
 
-	collectionCustomFacilities := config.DB.Collection("customFacility")
 	validCustomFacilities := []primitive.ObjectID{}
 
 	for _, facilityID := range customFacilities {
-		err := collectionCustomFacilities.FindOne(context.TODO(), bson.M{
-			"_id":      facilityID,
-			"owner_id": ownerID,
-		}).Err()
-
+		_, err := ctrl.Store.CustomFacilities.FindByIDAndOwner(c.Request.Context(), facilityID, ownerID)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Custom facility with ID %s is not valid or not owned by this user", facilityID.Hex()),
-			})
+			respondError(c, http.StatusBadRequest, response.CodeValidationFailed, fmt.Sprintf("Custom facility with ID %s is not valid or not owned by this user", facilityID.Hex()), err)
 			return
 		}
 
@@ -129,34 +114,23 @@ func CreateRoom(c *gin.Context) {
 		for _, fileHeader := range files {
 			file, err := fileHeader.Open()
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+				response.Fail(c, http.StatusInternalServerError, response.CodeUploadFailed, "Failed to read uploaded file")
 				return
 			}
 			defer file.Close()
 
 			content, err := io.ReadAll(file)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file content"})
+				response.Fail(c, http.StatusInternalServerError, response.CodeInternal, "Failed to read file content")
 				return
 			}
 
 			ext := path.Ext(fileHeader.Filename)
 			uniqueFilename := fmt.Sprintf("RoomImages/%s%s", uuid.New().String(), ext)
 
-			githubConfig := helper.GitHubConfig{
-				AccessToken: config.GetGitHubToken(),
-				AuthorName:  "Balqis Rosa Sekamayang",
-				AuthorEmail: "balqisrosasekamayang@gmail.com",
-				Org:         "kosconnect",
-				Repo:        "img",
-				FilePath:    uniqueFilename,
-				FileContent: content,
-				Replace:     true,
-			}
-
-			resp, err := helper.UploadFile(context.Background(), githubConfig)
+			resp, err := ctrl.uploadImage(c.Request.Context(), uniqueFilename, content)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to upload file to GitHub: %v", err)})
+				respondError(c, http.StatusInternalServerError, response.CodeUploadFailed, "Failed to upload file to GitHub", err)
 				return
 			}
 
@@ -167,8 +141,8 @@ func CreateRoom(c *gin.Context) {
 		}
 	}
 
-	// Ambil nilai dari form (atau set default jika kosong)
-	numberAvailable, _ := strconv.Atoi(c.DefaultPostForm("number_available", "0"))
+	// Ambil nilai dari form (0 jika kosong)
+	numberAvailable := req.Available()
 
 	status := "Tidak Tersedia"
 	if numberAvailable >= 1 {
@@ -179,89 +153,66 @@ func CreateRoom(c *gin.Context) {
 	room := models.Room{
 		RoomID:          primitive.NewObjectID(),
 		BoardingHouseID: boardingHouseID,
-		RoomType:        roomType,
-		Size:            size,
-		Price: models.RoomPrice{
-			Monthly:    priceMonthly,
-			Quarterly:  priceQuarterly,
-			SemiAnnual: priceSemiAnnual,
-			Yearly:     priceYearly,
-		},
+		RoomType:        req.RoomType,
+		Size:            req.Size,
+		Price:           price,
 		RoomFacilities:   validRoomFacilities,
 		CustomFacilities: validCustomFacilities,
 		NumberAvailable:  numberAvailable,
+		Capacity:         numberAvailable, // Kamar baru belum punya transaksi
 		Status:           status,
 		Images:           roomImageURL,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
 	}
 
-	collection := config.DB.Collection("rooms")
-	_, err = collection.InsertOne(context.Background(), room)
+	err = ctrl.Store.Rooms.Create(c.Request.Context(), &room)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save room to database"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to save room to database", err)
 		return
 	}
 
 	// Format response prices to rupiah
 	formattedPrices := map[string]string{
-		"monthly":     formatrupiah(float64(priceMonthly)),
-		"quarterly":   formatrupiah(float64(priceQuarterly)),
-		"semi_annual": formatrupiah(float64(priceSemiAnnual)),
-		"yearly":      formatrupiah(float64(priceYearly)),
-	}
-
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Room created successfully",
-		"data": gin.H{
-			"room":             room,
-			"prices_formatted": formattedPrices,
-		},
-	})
+		"monthly":     formatrupiah(float64(price.Monthly)),
+		"quarterly":   formatrupiah(float64(price.Quarterly)),
+		"semi_annual": formatrupiah(float64(price.SemiAnnual)),
+		"yearly":      formatrupiah(float64(price.Yearly)),
+	}
+
+	response.Message(c, http.StatusCreated, gin.H{
+		"room":             room,
+		"prices_formatted": formattedPrices,
+	}, "Room created successfully")
 }
 
 
 // GetAllRoom retrieves all rooms for public view
-func GetAllRooms(c *gin.Context) {
-	// Mendapatkan koleksi MongoDB
-	collection := config.DB.Collection("rooms")
-
-	// Query semua kamar
-	cursor, err := collection.Find(context.TODO(), bson.M{})
-	if err != nil {
-		log.Printf("Error fetching rooms from the database: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rooms from the database"})
+func (ctrl *Controller) GetAllRooms(c *gin.Context) {
+	q, ok := ctrl.parseQuery(c, dto.RoomListQuery)
+	if !ok {
 		return
 	}
-	defer cursor.Close(context.TODO())
 
-	// Array untuk menyimpan data kamar
-	var rooms []models.Room
-
-	// Decode hasil query ke dalam array rooms
-	if err := cursor.All(context.TODO(), &rooms); err != nil {
-		log.Printf("Error decoding rooms: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode rooms"})
+	// Query satu halaman kamar
+	page, err := ctrl.Store.Rooms.List(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch rooms from the database", err)
 		return
 	}
 
-	// Log the rooms for debugging
-	log.Printf("Rooms: %+v", rooms)
-
-	// Return semua kamar
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Rooms fetched successfully",
-		"data":    rooms,
-	})
+	respondPage(c, q, page, page.Items)
 }
 
 // GetRoomByBoardingHouseID retrieves rooms by boarding house ID
-func GetRoomByBoardingHouseID(c *gin.Context) {
+func (ctrl *Controller) GetRoomByBoardingHouseID(c *gin.Context) {
 	// Ambil klaim JWT dari context
 	claims := c.MustGet("user").(jwt.MapClaims)
 
 	// Ambil role dari klaim JWT
 	role, ok := claims["role"].(string)
 	if !ok || (role != "owner" && role != "admin") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only owners or admins can view rooms"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only owners or admins can view rooms")
 		return
 	}
 
@@ -269,439 +220,147 @@ func GetRoomByBoardingHouseID(c *gin.Context) {
 	boardingHouseID := c.Param("id")
 	boardingHouseObjectID, err := primitive.ObjectIDFromHex(boardingHouseID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid boarding house ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid boarding house ID")
 		return
 	}
 
 	// Ambil semua kamar berdasarkan boarding_house_id
-	roomCollection := config.DB.Collection("rooms")
-	var rooms []models.Room
-	cursor, err := roomCollection.Find(context.Background(), bson.M{"boarding_house_id": boardingHouseObjectID})
+	rooms, err := ctrl.Store.Rooms.FindByBoardingHouse(c.Request.Context(), boardingHouseObjectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rooms"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch rooms", err)
 		return
 	}
-	defer cursor.Close(context.Background())
-
-	for cursor.Next(context.Background()) {
-		var room models.Room
-		if err := cursor.Decode(&room); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding room"})
-			return
-		}
-		rooms = append(rooms, room)
-	}
 
 	// Kirim data rooms ke frontend
-	c.JSON(http.StatusOK, gin.H{"data": rooms})
+	response.OK(c, rooms)
 }
 
 // GetRoomByID retrieves a specific room by ID
-func GetRoomByID(c *gin.Context) {
+func (ctrl *Controller) GetRoomByID(c *gin.Context) {
 	id := c.Param("id")
 	roomID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid room ID")
 		return
 	}
 
-	collection := config.DB.Collection("rooms")
-	var room models.Room
-
-	err = collection.FindOne(context.Background(), bson.M{"_id": roomID}).Decode(&room)
+	room, err := ctrl.Store.Rooms.FindByID(c.Request.Context(), roomID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		respondError(c, http.StatusNotFound, response.CodeRoomNotFound, "Room not found", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": room})
+	response.OK(c, room)
 }
 
-func GetRoomDetailsByID(c *gin.Context) {
+func (ctrl *Controller) GetRoomDetailsByID(c *gin.Context) {
 	roomID := c.Param("id")
 	objectID, err := primitive.ObjectIDFromHex(roomID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
-		return
-	}
-
-	roomCollection := config.DB.Collection("rooms")
-
-	// Pipeline untuk agregasi
-	pipeline := mongo.Pipeline{
-		{
-			{Key: "$match", Value: bson.D{{Key: "_id", Value: objectID}}}, // Match the room by ID
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "boardinghouses"},
-				{Key: "localField", Value: "boarding_house_id"},
-				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: "boarding_house"},
-			}}, // Join with boardinghouses collection
-		},
-		{
-			{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$boarding_house"}}}, // Unwind the boarding house array
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "users"},
-				{Key: "localField", Value: "boarding_house.owner_id"},
-				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: "owner"},
-			}}, // Join with users collection to get owner details
-		},
-		{
-			{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$owner"}}}, // Unwind the owner array
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "facilities"},
-				{Key: "localField", Value: "room_facilities"},
-				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: "room_facilities_details"},
-			}}, // Join with facilities collection
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "customFacility"},
-				{Key: "localField", Value: "custom_facilities"},
-				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: "custom_facility_details"},
-			}}, // Join with customFacility collection
-		},
-		{
-			{Key: "$project", Value: bson.D{
-				{Key: "room_id", Value: "$_id"},
-				{Key: "boarding_house_name", Value: "$boarding_house.name"},      // Include boarding house name
-				{Key: "owner_name", Value: "$owner.fullname"},                    // Include owner name
-				{Key: "room_facilities", Value: "$room_facilities_details.name"}, // Include room facility names
-				{Key: "custom_facility_details", Value: "$custom_facility_details"},
-			}},
-		},
-	}
-
-	// Eksekusi pipeline
-	cursor, err := roomCollection.Aggregate(context.TODO(), pipeline)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
-		log.Printf("Error during aggregation: %v", err)
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid room ID")
 		return
 	}
 
-	// Decode hasil query
-	var roomDetails []bson.M
-	if err := cursor.All(context.TODO(), &roomDetails); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode data"})
-		log.Printf("Error decoding cursor: %v", err)
+	// Ambil detail kamar beserta kos, owner dan fasilitasnya
+	roomDetails, err := ctrl.Store.Rooms.Details(c.Request.Context(), objectID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch data", err)
 		return
 	}
 
-	// Log hasil custom_facility_details untuk debug
-	if len(roomDetails) > 0 {
-		log.Printf("Custom Facility Details: %v", roomDetails[0]["custom_facility_details"])
-	} else {
-		log.Printf("No room details found for room ID: %s", roomID)
+	// Pipeline mengembalikan paling banyak satu dokumen
+	if len(roomDetails) == 0 {
+		middlewares.Logger(c).Debug("no room details found", "room_id", roomID)
+		response.Fail(c, http.StatusNotFound, response.CodeRoomNotFound, "Room not found")
+		return
 	}
+	middlewares.Logger(c).Debug("room details fetched", "room_id", roomID, "custom_facility_details", roomDetails[0]["custom_facility_details"])
 
-	// Kirimkan response JSON
-	c.JSON(http.StatusOK, roomDetails)
+	response.OK(c, roomDetails[0])
 }
 
-func GetRoomDetailPages(c *gin.Context) {
+func (ctrl *Controller) GetRoomDetailPages(c *gin.Context) {
 	roomID := c.Param("id")
 	objectID, err := primitive.ObjectIDFromHex(roomID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
-		return
-	}
-
-	roomCollection := config.DB.Collection("rooms")
-
-	// Pipeline untuk menggabungkan data dan gambar, termasuk owner, kategori, fasilitas, dan custom fasilitas
-	pipeline := mongo.Pipeline{
-		{
-			{Key: "$match", Value: bson.D{
-				{Key: "_id", Value: objectID}, // Filter berdasarkan Room ID
-			}},
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "boardinghouses"},          // Gabungkan dengan koleksi BoardingHouse
-				{Key: "localField", Value: "boarding_house_id"}, // Field referensi dari koleksi Room
-				{Key: "foreignField", Value: "_id"},             // Field referensi di koleksi BoardingHouse
-				{Key: "as", Value: "boarding_house"},            // Hasil join disimpan dalam field boarding_house
-			}},
-		},
-		{
-			{Key: "$unwind", Value: bson.D{
-				{Key: "path", Value: "$boarding_house"}, // Unwind untuk mengubah array menjadi objek
-			}},
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "users"},                         // Gabungkan dengan koleksi Users untuk mendapatkan Owner
-				{Key: "localField", Value: "boarding_house.owner_id"}, // Field referensi dari koleksi BoardingHouse
-				{Key: "foreignField", Value: "_id"},                   // Field referensi di koleksi Users (Owner)
-				{Key: "as", Value: "owner"},                           // Hasil join disimpan dalam field owner
-			}},
-		},
-		{
-			{Key: "$unwind", Value: bson.D{
-				{Key: "path", Value: "$owner"}, // Unwind untuk mengubah array menjadi objek
-			}},
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "categories"},                       // Gabungkan dengan koleksi Categories untuk mendapatkan kategori
-				{Key: "localField", Value: "boarding_house.category_id"}, // Field referensi dari boarding_house
-				{Key: "foreignField", Value: "_id"},                      // Field referensi di koleksi Categories
-				{Key: "as", Value: "category"},                           // Hasil join disimpan dalam field category
-			}},
-		},
-		{
-			{Key: "$unwind", Value: bson.D{
-				{Key: "path", Value: "$category"}, // Unwind untuk mengubah array menjadi objek
-			}},
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "facilities"}, // Gabungkan dengan koleksi Facilities
-				{Key: "localField", Value: "boarding_house.facilities_id"},
-				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: "facilities"}, // Hasil join disimpan dalam field room_facilities
-			}},
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "facilities"}, // Gabungkan dengan koleksi Facilities
-				{Key: "localField", Value: "room_facilities"},
-				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: "room_facilities"}, // Hasil join disimpan dalam field room_facilities
-			}},
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "customFacility"}, // Gabungkan dengan koleksi Custom Facilities
-				{Key: "localField", Value: "custom_facilities"},
-				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: "custom_facilities"}, // Hasil join disimpan dalam field custom_facilities
-			}},
-		},
-		{
-			{Key: "$addFields", Value: bson.D{
-				{Key: "all_images", Value: bson.D{
-					{Key: "$concatArrays", Value: bson.A{"$images", "$boarding_house.images"}}, // Gabungkan gambar
-				}},
-				{Key: "owner_id", Value: "$boarding_house.owner_id"},
-				{Key: "owner_fullname", Value: "$owner.fullname"},          // Tambahkan fullname dari owner
-				{Key: "category_name", Value: "$category.name"},            // Tambahkan nama kategori
-				{Key: "rules", Value: "$boarding_house.rules"},             // Tambahkan nama kategori
-				{Key: "description", Value: "$boarding_house.description"}, // Tambahkan nama kategori
-				{Key: "address", Value: "$boarding_house.address"},         // Tambahkan nama kategori
-				{Key: "room_name", Value: bson.D{
-					{Key: "$concat", Value: bson.A{"$boarding_house.name", " Tipe ", "$room_type"}}, // Gabungkan nama kos dan tipe kamar
-				}},
-			}},
-		},
-		{
-			{Key: "$project", Value: bson.D{
-				{Key: "room_id", Value: "$_id"},
-				{Key: "boarding_house_id", Value: "$boarding_house._id"},
-				{Key: "owner_id", Value: 1},
-				{Key: "room_name", Value: 1},
-				{Key: "all_images", Value: 1},
-				{Key: "owner_fullname", Value: 1},
-				{Key: "category_name", Value: 1},
-				{Key: "facilities", Value: "$facilities"},
-				{Key: "room_facilities", Value: "$room_facilities"},
-				{Key: "custom_facilities", Value: "$custom_facilities"},
-				{Key: "price", Value: "$price"},
-				{Key: "description", Value: 1},
-				{Key: "address", Value: 1},
-				{Key: "size", Value: 1},
-				{Key: "rules", Value: 1},
-				{Key: "number_available", Value: 1},
-			}},
-		},
-	}
-
-	// Eksekusi pipeline
-	cursor, err := roomCollection.Aggregate(context.TODO(), pipeline)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid room ID")
 		return
 	}
 
-	// Decode hasil query
-	var roomDetails []bson.M
-	if err := cursor.All(context.TODO(), &roomDetails); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode data"})
+	// Gabungkan data kamar, kos, owner, kategori dan fasilitas
+	roomDetails, err := ctrl.Store.Rooms.DetailPage(c.Request.Context(), objectID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch data", err)
 		return
 	}
 
-	// Kirimkan response JSON
-	c.JSON(http.StatusOK, roomDetails)
+	// Pipeline mengembalikan paling banyak satu dokumen
+	if len(roomDetails) == 0 {
+		response.Fail(c, http.StatusNotFound, response.CodeRoomNotFound, "Room not found")
+		return
+	}
+	response.OK(c, roomDetails[0])
 }
 
-func GetRoomsForLandingPage(c *gin.Context) {
-	roomCollection := config.DB.Collection("rooms")
-
-	// Define aggregation pipeline
-	pipeline := mongo.Pipeline{
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "boardinghouses"},          // Join dengan koleksi BoardingHouse
-				{Key: "localField", Value: "boarding_house_id"}, // Field referensi dari koleksi Room
-				{Key: "foreignField", Value: "_id"},             // Field referensi di BoardingHouse
-				{Key: "as", Value: "boarding_house"},            // Hasil join disimpan di boarding_house
-			}},
-		},
-		{
-			{Key: "$unwind", Value: bson.D{
-				{Key: "path", Value: "$boarding_house"},          // Unwind array ke objek
-				{Key: "preserveNullAndEmptyArrays", Value: true}, // Pastikan tetap ada meskipun boarding house kosong
-			}},
-		},
-		{
-			{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "categories"},                       // Join dengan koleksi Categories
-				{Key: "localField", Value: "boarding_house.category_id"}, // Referensi kategori
-				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: "category"},
-			}},
-		},
-		{
-			{Key: "$unwind", Value: bson.D{
-				{Key: "path", Value: "$category"},                // Unwind array ke objek
-				{Key: "preserveNullAndEmptyArrays", Value: true}, // Pastikan tetap ada meskipun kategori kosong
-			}},
-		},
-		{
-			{Key: "$project", Value: bson.D{
-				{Key: "room_id", Value: "$_id"}, // Tambahkan room_id
-				{Key: "room_name", Value: bson.D{
-					{Key: "$concat", Value: bson.A{"$boarding_house.name", " Tipe ", "$room_type"}},
-				}}, // Nama kamar gabungan
-				{Key: "address", Value: "$boarding_house.address"}, // Alamat kos
-				{Key: "price", Value: bson.D{
-					{Key: "$cond", Value: bson.D{
-						{Key: "if", Value: bson.D{{Key: "$gt", Value: bson.A{"$price.quarterly", nil}}}},
-						{Key: "then", Value: bson.D{
-							{Key: "quarterly", Value: "$price.quarterly"},
-						}},
-						{Key: "else", Value: bson.D{
-							{Key: "$cond", Value: bson.D{
-								{Key: "if", Value: bson.D{{Key: "$gt", Value: bson.A{"$price.monthly", nil}}}},
-								{Key: "then", Value: bson.D{
-									{Key: "monthly", Value: "$price.monthly"},
-								}},
-								{Key: "else", Value: bson.D{
-									{Key: "$cond", Value: bson.D{
-										{Key: "if", Value: bson.D{{Key: "$gt", Value: bson.A{"$price.semi_annual", nil}}}},
-										{Key: "then", Value: bson.D{
-											{Key: "semi_annual", Value: "$price.semi_annual"},
-										}},
-										{Key: "else", Value: bson.D{
-											{Key: "yearly", Value: "$price.yearly"},
-										}},
-									}},
-								}},
-							}},
-						}},
-					}},
-				}},
-				{Key: "category_name", Value: "$category.name"}, // Nama kategori
-				{Key: "category_id", Value: "$category._id"},    // ID kategori
-				{Key: "images", Value: bson.D{
-					{Key: "$slice", Value: bson.A{"$images", 1}}, // Gambar pertama
-				}},
-				{Key: "status", Value: bson.D{ // Hitung Status
-					{Key: "$cond", Value: bson.A{
-						bson.D{{Key: "$gt", Value: bson.A{"$number_available", 0}}},
-						bson.D{{Key: "$concat", Value: bson.A{
-							bson.D{{Key: "$toString", Value: "$number_available"}},
-							" Kamar Tersedia",
-						}}},
-						"Tidak Tersedia",
-					}},
-				}},
-				{Key: "owner_id", Value: "$boarding_house.owner_id"},
-			}},
-		},
+func (ctrl *Controller) GetRoomsForLandingPage(c *gin.Context) {
+	q, ok := ctrl.parseQuery(c, dto.RoomLandingPageQuery)
+	if !ok {
+		return
 	}
 
 	// Menjalankan agregasi
-	cursor, err := roomCollection.Aggregate(context.TODO(), pipeline)
+	page, err := ctrl.Store.Rooms.LandingPage(c.Request.Context(), q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data"})
-		return
-	}
-
-	// Menyimpan hasil agregasi
-	var results []bson.M
-	if err := cursor.All(context.TODO(), &results); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode data"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch data", err)
 		return
 	}
 
 	// Mengirim data hasil agregasi ke frontend
-	c.JSON(http.StatusOK, results)
+	respondPage(c, q, page, page.Items)
 }
 
 // UPDATE
-func UpdateRoom(c *gin.Context) {
+func (ctrl *Controller) UpdateRoom(c *gin.Context) {
 	roomID, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid room ID")
 		return
 	}
 
 	// Parse form-data
 	err = c.Request.ParseMultipartForm(10 << 20)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse form-data"})
+		response.Fail(c, http.StatusBadRequest, response.CodeValidationFailed, "Failed to parse form-data")
 		return
 	}
 
-	// Extract fields
-	roomType := c.PostForm("room_type")
-	size := c.PostForm("size")
+	// Extract dan validasi fields
+	var req dto.RoomForm
+	if !bind(c, &req, binding.FormMultipart) {
+		return
+	}
 
 	// Parsing harga dengan validasi minimal satu harus diisi
-	priceMonthly, _ := strconv.Atoi(c.DefaultPostForm("price_monthly", "0"))
-	priceQuarterly, _ := strconv.Atoi(c.DefaultPostForm("price_quarterly", "0"))
-	priceSemiAnnual, _ := strconv.Atoi(c.DefaultPostForm("price_semi_annual", "0"))
-	priceYearly, _ := strconv.Atoi(c.DefaultPostForm("price_yearly", "0"))
-
-	if priceMonthly == 0 && priceQuarterly == 0 && priceSemiAnnual == 0 && priceYearly == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Minimal satu harga harus diisi"})
+	price := req.Price()
+	if price == (models.RoomPrice{}) {
+		failValidation(c, priceRequired)
 		return
 	}
 
-	numberAvailable, _ := strconv.Atoi(c.DefaultPostForm("number_available", "0"))
+	numberAvailable := req.Available()
 
 	// Validasi Room Facilities
-	var roomFacilities []primitive.ObjectID
-	roomFacilitiesJSON := c.PostForm("room_facilities")
-	if err := json.Unmarshal([]byte(roomFacilitiesJSON), &roomFacilities); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room facilities format"})
+	roomFacilities, ok := parseObjectIDs(c, "room_facilities", req.RoomFacilities)
+	if !ok {
 		return
 	}
 
-	collectionFacilities := config.DB.Collection("facilities")
 	validRoomFacilities := []primitive.ObjectID{}
 
 	for _, facilityID := range roomFacilities {
-		err := collectionFacilities.FindOne(context.TODO(), bson.M{
-			"_id":  facilityID,
-			"type": "room",
-		}).Err()
-
+		_, err := ctrl.Store.Facilities.FindByIDAndType(c.Request.Context(), facilityID, "room")
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Room facility with ID %s is not valid or not of type 'room'", facilityID.Hex()),
-			})
+			respondError(c, http.StatusBadRequest, response.CodeValidationFailed, fmt.Sprintf("Room facility with ID %s is not valid or not of type 'room'", facilityID.Hex()), err)
 			return
 		}
 
@@ -709,25 +368,17 @@ func UpdateRoom(c *gin.Context) {
 	}
 
 	// Validasi Custom Facilities
-	var customFacilities []primitive.ObjectID
-	customFacilitiesJSON := c.PostForm("custom_facilities")
-	if err := json.Unmarshal([]byte(customFacilitiesJSON), &customFacilities); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid custom facilities format"})
+	customFacilities, ok := parseObjectIDs(c, "custom_facilities", req.CustomFacilities)
+	if !ok {
 		return
 	}
 
-	collectionCustomFacilities := config.DB.Collection("customFacility")
 	validCustomFacilities := []primitive.ObjectID{}
 
 	for _, facilityID := range customFacilities {
-		err := collectionCustomFacilities.FindOne(context.TODO(), bson.M{
-			"_id": facilityID,
-		}).Err()
-
+		_, err := ctrl.Store.CustomFacilities.FindByID(c.Request.Context(), facilityID)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Custom facility with ID %s is not valid", facilityID.Hex()),
-			})
+			respondError(c, http.StatusBadRequest, response.CodeValidationFailed, fmt.Sprintf("Custom facility with ID %s is not valid", facilityID.Hex()), err)
 			return
 		}
 
@@ -743,34 +394,23 @@ func UpdateRoom(c *gin.Context) {
 		for _, fileHeader := range files {
 			file, err := fileHeader.Open()
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+				response.Fail(c, http.StatusInternalServerError, response.CodeUploadFailed, "Failed to read uploaded file")
 				return
 			}
 			defer file.Close()
 
 			content, err := io.ReadAll(file)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file content"})
+				response.Fail(c, http.StatusInternalServerError, response.CodeInternal, "Failed to read file content")
 				return
 			}
 
 			ext := path.Ext(fileHeader.Filename)
 			uniqueFilename := fmt.Sprintf("RoomImages/%s%s", uuid.New().String(), ext)
 
-			githubConfig := helper.GitHubConfig{
-				AccessToken: config.GetGitHubToken(),
-				AuthorName:  "Balqis Rosa Sekamayang",
-				AuthorEmail: "balqisrosasekamayang@gmail.com",
-				Org:         "kosconnect",
-				Repo:        "img",
-				FilePath:    uniqueFilename,
-				FileContent: content,
-				Replace:     true,
-			}
-
-			resp, err := helper.UploadFile(context.Background(), githubConfig)
+			resp, err := ctrl.uploadImage(c.Request.Context(), uniqueFilename, content)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to upload file to GitHub: %v", err)})
+				respondError(c, http.StatusInternalServerError, response.CodeUploadFailed, "Failed to upload file to GitHub", err)
 				return
 			}
 
@@ -781,54 +421,73 @@ func UpdateRoom(c *gin.Context) {
 		}
 	}
 
+	current, err := ctrl.Store.Rooms.FindByID(c.Request.Context(), roomID)
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeRoomNotFound, "Room not found")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch room", err)
+		return
+	}
+
 	// Update fields
 	updateFields := bson.M{
-		"room_type":         roomType,
-		"size":              size,
-		"price":             models.RoomPrice{Monthly: priceMonthly, Quarterly: priceQuarterly, SemiAnnual: priceSemiAnnual, Yearly: priceYearly},
+		"room_type":         req.RoomType,
+		"size":              req.Size,
+		"price":             price,
 		"room_facilities":   validRoomFacilities,
 		"custom_facilities": validCustomFacilities,
 		"number_available":  numberAvailable,
 		"status":            map[bool]string{true: "Tersedia", false: "Tidak Tersedia"}[numberAvailable > 0],
+		"updated_at":        time.Now(),
 	}
 
 	if len(roomImageURL) > 0 {
 		updateFields["images"] = roomImageURL
 	}
+	// Kamar yang ditambah atau dikurangi owner ikut mengubah capacity, agar
+	// kosctl recompute-availability tidak mengembalikan number_available lama.
+	// Kamar lama tanpa capacity dibiarkan; kosctl yang menyimpulkannya.
+	if current.Capacity > 0 {
+		updateFields["capacity"] = max(current.Capacity+numberAvailable-current.NumberAvailable, numberAvailable)
+	}
 
-	collection := config.DB.Collection("rooms")
-	_, err = collection.UpdateOne(context.Background(), bson.M{"_id": roomID}, bson.M{"$set": updateFields})
+	err = ctrl.Store.Rooms.Update(c.Request.Context(), roomID, updateFields)
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeRoomNotFound, "Room not found")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update room in database"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update room in database", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Room updated successfully"})
+	response.Message(c, http.StatusOK, nil, "Room updated successfully")
 }
 
 // DeleteRoom deletes an existing room by ID
-func DeleteRoom(c *gin.Context) {
+func (ctrl *Controller) DeleteRoom(c *gin.Context) {
 	// Extract and validate the room ID
 	id := c.Param("id")
 	roomID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid room ID")
 		return
 	}
 
 	// Delete the room document from the database
-	collection := config.DB.Collection("rooms")
-	result, err := collection.DeleteOne(context.Background(), bson.M{"_id": roomID})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete room"})
-		return
-	}
+	err = ctrl.Store.Rooms.Delete(c.Request.Context(), roomID)
 
 	// Check if the room was actually deleted
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeRoomNotFound, "Room not found")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to delete room", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Room deleted successfully"})
+	response.Message(c, http.StatusOK, nil, "Room deleted successfully")
 }
@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/organisasi/kosconnectbackend/dto"
+	"github.com/organisasi/kosconnectbackend/helper"
+	"github.com/organisasi/kosconnectbackend/middlewares"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Nama cookie token login. refreshToken hanya dikirim ke /auth.
+const (
+	accessTokenCookie  = "authToken"
+	refreshTokenCookie = "refreshToken"
+	roleCookie         = "userRole"
+)
+
+// tokenPair adalah hasil login dan refresh.
+type tokenPair struct {
+	Access    string
+	Refresh   string
+	ExpiresIn int // Detik sampai access token kedaluwarsa
+}
+
+// startSession membuat session baru untuk user yang berhasil login lalu
+// mengembalikan access token dan refresh token-nya.
+func (ctrl *Controller) startSession(c *gin.Context, user *models.User) (tokenPair, error) {
+	now := time.Now()
+	session := models.Session{
+		ID:          primitive.NewObjectID(),
+		UserID:      user.UserID,
+		UserAgent:   c.Request.UserAgent(),
+		IP:          c.ClientIP(),
+		CreatedAt:   now,
+		RefreshedAt: now,
+		ExpiresAt:   now.Add(ctrl.Config.Auth.RefreshTokenTTL),
+	}
+	secret := helper.RandomToken()
+	session.RefreshHash = helper.HashToken(secret)
+	if err := ctrl.Store.Sessions.Create(c.Request.Context(), &session); err != nil {
+		return tokenPair{}, err
+	}
+	return ctrl.tokens(user, session.ID, secret)
+}
+
+// tokens membuat access token untuk session dan refresh token berbentuk
+// "<session_id>.<secret>"; hanya hash secret yang disimpan di session.
+func (ctrl *Controller) tokens(user *models.User, sessionID primitive.ObjectID, secret string) (tokenPair, error) {
+	access, err := ctrl.generateToken(user.UserID, user.Role, sessionID)
+	if err != nil {
+		return tokenPair{}, err
+	}
+	return tokenPair{
+		Access:    access,
+		Refresh:   sessionID.Hex() + "." + secret,
+		ExpiresIn: int(ctrl.Config.Auth.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// respondLogin mengirim token di body dan cookie, dipakai Login,
+// GoogleAuth dan Refresh.
+func (ctrl *Controller) respondLogin(c *gin.Context, user *models.User, tokens tokenPair, message string) {
+	refreshMaxAge := int(ctrl.Config.Auth.RefreshTokenTTL.Seconds())
+	// Secure dan HttpOnly, kecuali role yang dibaca JavaScript frontend
+	c.SetCookie(accessTokenCookie, tokens.Access, tokens.ExpiresIn, "/", "", true, true)
+	c.SetCookie(refreshTokenCookie, tokens.Refresh, refreshMaxAge, "/auth", "", true, true)
+	c.SetCookie(roleCookie, user.Role, refreshMaxAge, "/", "", true, false)
+
+	response.Message(c, http.StatusOK, gin.H{
+		"token":         tokens.Access,
+		"refresh_token": tokens.Refresh,
+		"expires_in":    tokens.ExpiresIn,
+		"role":          user.Role,
+	}, message)
+}
+
+// Refresh menukar refresh token dengan access token dan refresh token baru.
+// Refresh token lama langsung tidak berlaku. Jika refresh token lama dipakai
+// lagi (kemungkinan dicuri), session-nya dicabut.
+func (ctrl *Controller) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if c.Request.ContentLength > 0 && !bind(c, &req, binding.JSON) {
+		return
+	}
+	if req.RefreshToken == "" {
+		req.RefreshToken, _ = c.Cookie(refreshTokenCookie)
+	}
+	if req.RefreshToken == "" {
+		response.Fail(c, http.StatusUnauthorized, response.CodeUnauthorized, "Refresh token is required")
+		return
+	}
+
+	ctx := c.Request.Context()
+	invalid := func() {
+		response.Fail(c, http.StatusUnauthorized, response.CodeInvalidToken, "Invalid or expired refresh token")
+	}
+	sid, secret, ok := strings.Cut(req.RefreshToken, ".")
+	sessionID, err := primitive.ObjectIDFromHex(sid)
+	if !ok || err != nil {
+		invalid()
+		return
+	}
+	session, err := ctrl.Store.Sessions.FindByID(ctx, sessionID)
+	if errors.Is(err, store.ErrNotFound) {
+		invalid()
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to refresh session", err)
+		return
+	}
+	if !session.Active(time.Now()) {
+		invalid()
+		return
+	}
+
+	revoke := func(reason string) {
+		middlewares.Logger(c).Warn(reason, "session_id", sid, "user_id", session.UserID.Hex())
+		if err := ctrl.Store.Sessions.Revoke(ctx, sessionID); err != nil && !errors.Is(err, store.ErrNotFound) {
+			middlewares.Logger(c).Error("failed to revoke session", "error", err)
+		}
+		invalid()
+	}
+	oldHash := helper.HashToken(secret)
+	if oldHash != session.RefreshHash {
+		revoke("refresh token reused, session revoked")
+		return
+	}
+
+	// Role diambil ulang dari database, bisa berubah sejak login
+	user, err := ctrl.Store.Users.FindByID(ctx, session.UserID)
+	if errors.Is(err, store.ErrNotFound) {
+		revoke("user of session no longer exists, session revoked")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to refresh session", err)
+		return
+	}
+
+	newSecret := helper.RandomToken()
+	err = ctrl.Store.Sessions.Rotate(ctx, sessionID, oldHash, helper.HashToken(newSecret), time.Now().Add(ctrl.Config.Auth.RefreshTokenTTL))
+	if errors.Is(err, store.ErrNotFound) {
+		// Request lain memakai refresh token yang sama lebih dulu
+		revoke("refresh token used concurrently, session revoked")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to refresh session", err)
+		return
+	}
+
+	tokens, err := ctrl.tokens(user, sessionID, newSecret)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to generate token", err)
+		return
+	}
+	ctrl.respondLogin(c, user, tokens, "Token refreshed")
+}
+
+// Logout mencabut session dari access token yang dipakai, atau semua
+// session user jika body berisi {"all": true}.
+func (ctrl *Controller) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	if c.Request.ContentLength > 0 && !bind(c, &req, binding.JSON) {
+		return
+	}
+
+	claims := c.MustGet("user").(jwt.MapClaims)
+	ctx := c.Request.Context()
+	if req.All {
+		userID, err := primitive.ObjectIDFromHex(claims["user_id"].(string))
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid user ID")
+			return
+		}
+		if _, err := ctrl.Store.Sessions.RevokeAll(ctx, userID); err != nil {
+			respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to log out", err)
+			return
+		}
+	} else {
+		// sid sudah diperiksa JWTAuthMiddleware
+		sessionID, _ := primitive.ObjectIDFromHex(claims["sid"].(string))
+		err := ctrl.Store.Sessions.Revoke(ctx, sessionID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to log out", err)
+			return
+		}
+	}
+
+	c.SetCookie(accessTokenCookie, "", -1, "/", "", true, true)
+	c.SetCookie(refreshTokenCookie, "", -1, "/auth", "", true, true)
+	c.SetCookie(roleCookie, "", -1, "/", "", true, false)
+	response.Message(c, http.StatusOK, nil, "Logged out")
+}
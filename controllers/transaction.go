@@ -2,120 +2,63 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/dto"
 	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func CreateTransaction(c *gin.Context) {
-	// Ambil query string dari request
-	roomID := c.Query("room_id")
-	boardingHouseID := c.Query("boarding_house_id")
-	ownerID := c.Query("owner_id")
-	userID := c.Query("user_id")
-
-	// Validasi input
-	if roomID == "" || boardingHouseID == "" || ownerID == "" || userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required parameters"})
-		return
-	}
-
-	// Convert IDs menjadi ObjectID
-	roomObjectID, err := primitive.ObjectIDFromHex(roomID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+func (ctrl *Controller) CreateTransaction(c *gin.Context) {
+	// Ambil dan validasi query string serta body dari request
+	var query dto.CreateTransactionQuery
+	if !bind(c, &query, binding.Query) {
 		return
 	}
-	boardingHouseObjectID, err := primitive.ObjectIDFromHex(boardingHouseID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid boarding house ID"})
-		return
-	}
-	ownerObjectID, err := primitive.ObjectIDFromHex(ownerID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner ID"})
-		return
-	}
-	userObjectID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	var requestBody dto.CreateTransactionRequest
+	if !bind(c, &requestBody, binding.JSON) {
 		return
 	}
 
+	// Convert IDs menjadi ObjectID (format sudah divalidasi)
+	roomObjectID, _ := primitive.ObjectIDFromHex(query.RoomID)
+	boardingHouseObjectID, _ := primitive.ObjectIDFromHex(query.BoardingHouseID)
+	ownerObjectID, _ := primitive.ObjectIDFromHex(query.OwnerID)
+	userObjectID, _ := primitive.ObjectIDFromHex(query.UserID)
+
 	// Ambil data kamar
-	roomCollection := config.DB.Collection("rooms")
-	var room models.Room
-	err = roomCollection.FindOne(context.TODO(), bson.M{"_id": roomObjectID}).Decode(&room)
+	room, err := ctrl.Store.Rooms.FindByID(c.Request.Context(), roomObjectID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		respondError(c, http.StatusNotFound, response.CodeRoomNotFound, "Room not found", err)
 		return
 	}
 
 	// Ambil data boarding house
-	boardingHouseCollection := config.DB.Collection("boardinghouses")
-	var boardingHouse models.BoardingHouse
-	err = boardingHouseCollection.FindOne(context.TODO(), bson.M{"_id": boardingHouseObjectID}).Decode(&boardingHouse)
+	_, err = ctrl.Store.BoardingHouses.FindByID(c.Request.Context(), boardingHouseObjectID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Boarding house not found"})
-		return
-	}
-
-	// Ambil custom facilities dari body request
-	var requestBody struct {
-		CustomFacilityIDs []string            `json:"custom_facilities"`
-		PaymentTerm       string              `json:"payment_term"`
-		CheckInDate       string              `json:"check_in_date"`
-		PersonalInfo      models.PersonalInfo `json:"personal_info"`
-	}
-	if err := c.ShouldBindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		respondError(c, http.StatusNotFound, response.CodeBoardingHouseNotFound, "Boarding house not found", err)
 		return
 	}
 
-	// Validasi payment term
 	paymentTerm := requestBody.PaymentTerm
-	validTerms := []string{"monthly", "quarterly", "semi_annual", "yearly"}
-	isValidTerm := false
-	for _, term := range validTerms {
-		if paymentTerm == term {
-			isValidTerm = true
-			break
-		}
-	}
-	if !isValidTerm {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment term"})
-		return
-	}
-
-	// Validasi tanggal check-in
-	checkInDate, err := time.Parse("2006-01-02", requestBody.CheckInDate)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid check-in date format"})
-		return
-	}
+	checkInDate := requestBody.CheckIn()
 
 	// Query fasilitas custom berdasarkan ID
 	var customFacilities []models.CustomFacilityInfo
-	customFacilityCollection := config.DB.Collection("customFacility")
 
-	for _, customFacilityID := range requestBody.CustomFacilityIDs {
-		cfObjectID, err := primitive.ObjectIDFromHex(customFacilityID)
+	for _, cfObjectID := range requestBody.CustomFacilityIDs() {
+		customFacility, err := ctrl.Store.CustomFacilities.FindByID(c.Request.Context(), cfObjectID)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid custom facility ID"})
-			return
-		}
-
-		var customFacility models.CustomFacility
-		err = customFacilityCollection.FindOne(context.TODO(), bson.M{"_id": cfObjectID}).Decode(&customFacility)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Custom facility not found"})
+			respondError(c, http.StatusNotFound, response.CodeCustomFacilityNotFound, "Custom facility not found", err)
 			return
 		}
 
@@ -127,14 +70,14 @@ func CreateTransaction(c *gin.Context) {
 	}
 
 	// Ambil data kamar dan validasi ketersediaan
-	err = roomCollection.FindOne(context.TODO(), bson.M{"_id": roomObjectID}).Decode(&room)
+	room, err = ctrl.Store.Rooms.FindByID(c.Request.Context(), roomObjectID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		respondError(c, http.StatusNotFound, response.CodeRoomNotFound, "Room not found", err)
 		return
 	}
 
 	if room.NumberAvailable <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Room is not available"})
+		response.Fail(c, http.StatusConflict, response.CodeRoomNotAvailable, "Room is not available")
 		return
 	}
 
@@ -174,7 +117,7 @@ func CreateTransaction(c *gin.Context) {
 		OwnerID:          ownerObjectID,
 		BoardingHouseID:  boardingHouseObjectID,
 		RoomID:           roomObjectID,
-		PersonalInfo:     requestBody.PersonalInfo,
+		PersonalInfo:     requestBody.PersonalInfo.Model(),
 		CustomFacilities: customFacilities,
 		PaymentTerm:      paymentTerm,
 		CheckInDate:      checkInDate,
@@ -190,26 +133,29 @@ func CreateTransaction(c *gin.Context) {
 	}
 
 	// Simpan transaksi ke database
-	transactionCollection := config.DB.Collection("transactions")
-	_, err = transactionCollection.InsertOne(context.TODO(), transaction)
+	err = ctrl.Store.Transactions.Create(c.Request.Context(), &transaction)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transaction"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to create transaction", err)
 		return
 	}
 
 	// Update jumlah kamar yang tersedia
-	updateResult, err := roomCollection.UpdateOne(
-		context.TODO(),
-		bson.M{"_id": roomObjectID},
-		bson.M{"$inc": bson.M{"number_available": -1}}, // Kurangi jumlah kamar
-	)
-	if err != nil || updateResult.ModifiedCount == 0 {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update room availability"})
+	err = ctrl.Store.Rooms.DecrementAvailable(c.Request.Context(), roomObjectID) // Kurangi jumlah kamar
+	if errors.Is(err, store.ErrNotFound) {
+		// Kamar terakhir sudah diambil booking lain setelah cek di atas,
+		// transaksi yang baru dibuat dibatalkan lagi
+		if err := ctrl.Store.Transactions.Delete(context.WithoutCancel(c.Request.Context()), transaction.TransactionID); err != nil {
+			c.Error(err)
+		}
+		response.Fail(c, http.StatusConflict, response.CodeRoomNotAvailable, "Room is not available")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update room availability", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":        "Transaction created successfully",
+	response.Message(c, http.StatusOK, gin.H{
 		"transaction_id": transaction.TransactionID,
 		"details": gin.H{
 			"room_price":       roomPrice,
@@ -218,103 +164,77 @@ func CreateTransaction(c *gin.Context) {
 			"ppn":              ppn,
 			"total":            total,
 		},
-	})
+	}, "Transaction created successfully")
 }
 
 // dipakai oleh admin dan owner
-func GetAllTransactions(c *gin.Context) {
-	collection := config.DB.Collection("transactions")
-
-	// Ambil semua data transaksi dari database
-	cursor, err := collection.Find(context.TODO(), bson.M{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transactions"})
+func (ctrl *Controller) GetAllTransactions(c *gin.Context) {
+	q, ok := ctrl.parseQuery(c, dto.TransactionListQuery)
+	if !ok {
 		return
 	}
-	defer cursor.Close(context.TODO())
 
-	// Simpan hasilnya dalam slice
-	var transactions []models.Transaction
-	if err = cursor.All(context.TODO(), &transactions); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse transactions"})
+	// Ambil satu halaman data transaksi dari database
+	page, err := ctrl.Store.Transactions.List(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch transactions", err)
 		return
 	}
 
 	// Kembalikan data
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Transactions fetched successfully",
-		"data":    transactions,
-	})
+	respondPage(c, q, page, page.Items)
 }
 
 // untuk dapatkan transaksi berdasarkan id
-func GetTransactionByID(c *gin.Context) {
+func (ctrl *Controller) GetTransactionByID(c *gin.Context) {
 	id := c.Param("id")
 
 	transactionID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid transaction ID")
 		return
 	}
 
-	collection := config.DB.Collection("transactions")
-
-	var transaction models.Transaction
-	err = collection.FindOne(context.TODO(), bson.M{"_id": transactionID}).Decode(&transaction)
+	transaction, err := ctrl.Store.Transactions.FindByID(c.Request.Context(), transactionID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+		respondError(c, http.StatusNotFound, response.CodeTransactionNotFound, "Transaction not found", err)
 		return
 	}
 
 	// Kembalikan data transaksi
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Transaction fetched successfully",
-		"data":    transaction,
-	})
+	response.OK(c, transaction)
 }
 
 // mendapatkan data transaksi berdasarkan user yang login
 // A.K.A BUAT DI HALAMAN USER YA FATH / BALQIS .-fath cantik
-func GetTransactionsByUser(c *gin.Context) {
+func (ctrl *Controller) GetTransactionsByUser(c *gin.Context) {
 	// Ambil ID user dari JWT
 	claims := c.MustGet("user").(jwt.MapClaims)
 	userID, ok := claims["user_id"].(string)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse user ID from token"})
+		response.Fail(c, http.StatusInternalServerError, response.CodeInternal, "Failed to parse user ID from token")
 		return
 	}
 
 	userObjectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	collection := config.DB.Collection("transactions")
-
 	// Ambil semua transaksi milik user
-	cursor, err := collection.Find(context.TODO(), bson.M{"user_id": userObjectID})
+	transactions, err := ctrl.Store.Transactions.FindByUser(c.Request.Context(), userObjectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user transactions"})
-		return
-	}
-	defer cursor.Close(context.TODO())
-
-	var transactions []models.Transaction
-	if err = cursor.All(context.TODO(), &transactions); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse user transactions"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch user transactions", err)
 		return
 	}
 
 	// Kembalikan data transaksi user
-	c.JSON(http.StatusOK, gin.H{
-		"message": "User transactions fetched successfully",
-		"data":    transactions,
-	})
+	response.OK(c, transactions)
 }
 
 // INI YANG DI PAKE DI DASHBOARD OWNER YA :* YA  JADI PERHATIKAN ENDPOINTNYA T_T
-func GetTransactionsByOwner(c *gin.Context) {
+func (ctrl *Controller) GetTransactionsByOwner(c *gin.Context) {
 	// Ambil ID owner dari JWT
 	claims := c.MustGet("user").(jwt.MapClaims)
 	ownerID, ok := claims["user_id"].(string)
@@ -322,177 +242,120 @@ func GetTransactionsByOwner(c *gin.Context) {
 
 	// Validasi role: hanya "owner" yang diizinkan
 	if role != "owner" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only owners can access this resource"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only owners can access this resource")
 		return
 	}
 
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse owner ID from token"})
+		response.Fail(c, http.StatusInternalServerError, response.CodeInternal, "Failed to parse owner ID from token")
 		return
 	}
 
 	ownerObjectID, err := primitive.ObjectIDFromHex(ownerID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid owner ID")
 		return
 	}
 
-	collection := config.DB.Collection("transactions")
-
 	// Ambil semua transaksi milik owner
-	cursor, err := collection.Find(context.TODO(), bson.M{"owner_id": ownerObjectID})
+	transactions, err := ctrl.Store.Transactions.FindByOwner(c.Request.Context(), ownerObjectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch owner transactions"})
-		return
-	}
-	defer cursor.Close(context.TODO())
-
-	var transactions []models.Transaction
-	if err = cursor.All(context.TODO(), &transactions); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse owner transactions"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch owner transactions", err)
 		return
 	}
 
 	// Kembalikan data transaksi owner
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Owner transactions fetched successfully",
-		"data":    transactions,
-	})
+	response.OK(c, transactions)
 }
 
 // DIBAWAH INI CODE UNTUK AMBIL DATA TRANSAKSI PUNYA USER DAN OWNER OLEH ADMIN
 // Ambil transaksi berdasarkan owner ID
-func GetTransactionsOwnerByAdmin(c *gin.Context) {
-	getTransactionsByField(c, "owner_id")
+func (ctrl *Controller) GetTransactionsOwnerByAdmin(c *gin.Context) {
+	getTransactionsByField(c, ctrl.Store.Transactions.FindByOwner)
 }
 
 // Ambil transaksi berdasarkan user ID
-func GetTransactionsUserByAdmin(c *gin.Context) {
-	getTransactionsByField(c, "user_id")
+func (ctrl *Controller) GetTransactionsUserByAdmin(c *gin.Context) {
+	getTransactionsByField(c, ctrl.Store.Transactions.FindByUser)
 }
 
 // Fungsi generik untuk mengambil transaksi berdasarkan field tertentu
-func getTransactionsByField(c *gin.Context, field string) {
+func getTransactionsByField(c *gin.Context, find func(context.Context, primitive.ObjectID) ([]models.Transaction, error)) {
 	id := c.Param("id")
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	collection := config.DB.Collection("transactions")
-
-	cursor, err := collection.Find(context.TODO(), bson.M{field: objectID})
+	transactions, err := find(c.Request.Context(), objectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transactions"})
-		return
-	}
-	defer cursor.Close(context.TODO())
-
-	var transactions []models.Transaction
-	if err = cursor.All(context.TODO(), &transactions); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse transactions"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch transactions", err)
 		return
 	}
 
 	if len(transactions) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No transactions found"})
+		response.Fail(c, http.StatusNotFound, response.CodeTransactionNotFound, "No transactions found")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Transactions fetched successfully",
-		"data":    transactions,
-	})
+	response.OK(c, transactions)
 }
 
 // untuk dapatkan transaksi berdasarkan status
-func GetTransactionsByPaymentStatus(c *gin.Context) {
+func (ctrl *Controller) GetTransactionsByPaymentStatus(c *gin.Context) {
 	status := c.Param("status")
 
-	collection := config.DB.Collection("transactions")
-
-	cursor, err := collection.Find(context.TODO(), bson.M{"payment_status": status})
+	transactions, err := ctrl.Store.Transactions.FindByPaymentStatus(c.Request.Context(), status)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transactions by payment status"})
-		return
-	}
-	defer cursor.Close(context.TODO())
-
-	var transactions []models.Transaction
-	if err = cursor.All(context.TODO(), &transactions); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse transactions by payment status"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch transactions by payment status", err)
 		return
 	}
 
 	// Kembalikan data transaksi berdasarkan status pembayaran
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Transactions by payment status fetched successfully",
-		"data":    transactions,
-	})
+	response.OK(c, transactions)
 }
 
-// UPDATE STATUS DOANG
-func UpdateTransaction(c *gin.Context) {
+// UPDATE STATUS DOANG, hanya admin atau owner kos dari transaksi tersebut
+func (ctrl *Controller) UpdateTransaction(c *gin.Context) {
+	// Ambil data dari token JWT
+	claims := c.MustGet("user").(jwt.MapClaims)
+	role, _ := claims["role"].(string)
+	userID, _ := claims["user_id"].(string)
+
+	// Penyewa tidak boleh mengubah status pembayarannya sendiri
+	if role != "admin" && role != "owner" {
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "You are not authorized to update transactions")
+		return
+	}
+
 	// Ambil transaction ID dari parameter URL
-	transactionID := c.Param("transaction_id")
+	transactionID := c.Param("id")
 
 	// Validasi transaction ID
 	transactionObjectID, err := primitive.ObjectIDFromHex(transactionID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid transaction ID")
 		return
 	}
 
-	// Ambil data dari body request
-	var requestBody struct {
-		PaymentMethod string `json:"payment_method"`
-		PaymentStatus string `json:"payment_status"`
-	}
-	if err := c.ShouldBindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	// Ambil dan validasi data dari body request
+	var requestBody dto.UpdateTransactionRequest
+	if !bind(c, &requestBody, binding.JSON) {
 		return
 	}
 
-	// Validasi payment status
-	validStatuses := []string{"pending", "paid", "failed", "cancelled"}
-	isValidStatus := false
-	for _, status := range validStatuses {
-		if requestBody.PaymentStatus == status {
-			isValidStatus = true
-			break
-		}
-	}
-	if !isValidStatus {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment status"})
+	// Cari transaksi berdasarkan ID
+	transaction, err := ctrl.Store.Transactions.FindByID(c.Request.Context(), transactionObjectID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, response.CodeTransactionNotFound, "Transaction not found", err)
 		return
 	}
 
-	// Validasi payment method jika disediakan
-	if requestBody.PaymentMethod != "" {
-		validMethods := []string{"credit_card", "bank_transfer", "ewallet", "cash"}
-		isValidMethod := false
-		for _, method := range validMethods {
-			if requestBody.PaymentMethod == method {
-				isValidMethod = true
-				break
-			}
-		}
-		if !isValidMethod {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment method"})
-			return
-		}
-	}
-
-	// Ambil koleksi transaksi
-	transactionCollection := config.DB.Collection("transactions")
-
-	// Cari transaksi berdasarkan ID
-	var transaction models.Transaction
-	err = transactionCollection.FindOne(context.TODO(), bson.M{"_id": transactionObjectID}).Decode(&transaction)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+	// Owner hanya boleh mengubah transaksi kos miliknya
+	if role == "owner" && transaction.OwnerID.Hex() != userID {
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "You are not authorized to update this transaction")
 		return
 	}
 
@@ -505,30 +368,25 @@ func UpdateTransaction(c *gin.Context) {
 		updateFields["payment_method"] = requestBody.PaymentMethod
 	}
 
-	_, err = transactionCollection.UpdateOne(
-		context.TODO(),
-		bson.M{"_id": transactionObjectID},
-		bson.M{"$set": updateFields},
-	)
+	err = ctrl.Store.Transactions.Update(c.Request.Context(), transactionObjectID, updateFields)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transaction"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update transaction", err)
 		return
 	}
 
 	// Kirim response sukses
-	c.JSON(http.StatusOK, gin.H{
-		"message":        "Transaction updated successfully",
+	response.Message(c, http.StatusOK, gin.H{
 		"transaction_id": transactionID,
-	})
+	}, "Transaction updated successfully")
 }
 
 // DELETE TRANSACTION (ONLY ADMIN)
-func DeleteTransaction(c *gin.Context) {
+func (ctrl *Controller) DeleteTransaction(c *gin.Context) {
 	// Ambil ID transaksi dari parameter URL
 	id := c.Param("id")
 	transactionID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid transaction ID")
 		return
 	}
 
@@ -538,28 +396,23 @@ func DeleteTransaction(c *gin.Context) {
 
 	// Hanya admin yang diizinkan
 	if role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to delete transactions"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "You are not authorized to delete transactions")
 		return
 	}
 
-	collection := config.DB.Collection("transactions")
-
 	// Cari dan hapus transaksi
-	filter := bson.M{"_id": transactionID}
-	result, err := collection.DeleteOne(context.TODO(), filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete transaction"})
-		return
-	}
+	err = ctrl.Store.Transactions.Delete(c.Request.Context(), transactionID)
 
 	// Periksa apakah transaksi ditemukan dan dihapus
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeTransactionNotFound, "Transaction not found")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to delete transaction", err)
 		return
 	}
 
 	// Berikan respons sukses
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Transaction deleted successfully",
-	})
+	response.Message(c, http.StatusOK, nil, "Transaction deleted successfully")
 }
@@ -1,16 +1,20 @@
 package controllers
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"fmt"
+	"time"
 
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/dto"
+	"github.com/organisasi/kosconnectbackend/middlewares"
 	"github.com/organisasi/kosconnectbackend/models"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
@@ -31,210 +35,191 @@ func getUserIDFromToken(c *gin.Context) (primitive.ObjectID, error) {
 }
 
 // Create user (admin only)
-func CreateUser(c *gin.Context) {
+func (ctrl *Controller) CreateUser(c *gin.Context) {
 	// Only allow admin to create a user
 	claims, _ := c.Get("user")
 	role := claims.(jwt.MapClaims)["role"].(string)
 	if role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only admin can create users"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only admin can create users")
 		return
 	}
 
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	var req dto.CreateUserRequest
+	if !bind(c, &req, binding.JSON) {
 		return
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		response.Fail(c, http.StatusInternalServerError, response.CodeInternal, "Failed to hash password")
 		return
 	}
-	user.Password = string(hashedPassword)
-	user.UserID = primitive.NewObjectID()
+	user := models.User{
+		UserID:         primitive.NewObjectID(),
+		FullName:       req.FullName,
+		Email:          req.Email,
+		PhoneNumber:    req.PhoneNumber,
+		Role:           req.Role,
+		Password:       string(hashedPassword),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+		IsRoleAssigned: true,
+	}
 
 	// Insert to MongoDB
-	collection := config.DB.Collection("users")
-	_, err = collection.InsertOne(context.TODO(), user)
+	err = ctrl.Store.Users.Create(c.Request.Context(), &user)
+	if errors.Is(err, store.ErrDuplicate) {
+		// Ditolak unique index users.email
+		response.Fail(c, http.StatusConflict, response.CodeEmailAlreadyExists, "Email already in use")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to register user", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User created successfully"})
+	response.Message(c, http.StatusOK, nil, "User created successfully")
 }
 
 // Get all users (admin only)
-func GetAllUsers(c *gin.Context) {
+func (ctrl *Controller) GetAllUsers(c *gin.Context) {
 	// Only allow admin to view all users
 	claims, _ := c.Get("user")
 	role := claims.(jwt.MapClaims)["role"].(string)
 	if role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only admin can view all users"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only admin can view all users")
 		return
 	}
 
-	// Fetch all users from MongoDB
-	collection := config.DB.Collection("users")
-	cursor, err := collection.Find(context.TODO(), bson.M{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+	q, ok := ctrl.parseQuery(c, dto.UserListQuery)
+	if !ok {
 		return
 	}
-	defer cursor.Close(context.TODO())
 
-	var users []models.User
-	if err := cursor.All(context.TODO(), &users); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode users"})
+	// Fetch one page of users from MongoDB
+	page, err := ctrl.Store.Users.List(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch users", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"users": users})
+	respondPage(c, q, page, dto.NewAdminUsers(page.Items))
 }
 
 // Get user account (for the currently logged-in user)
-func GetMyAccount(c *gin.Context) {
+func (ctrl *Controller) GetMyAccount(c *gin.Context) {
 	userID, err := getUserIDFromToken(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		response.Fail(c, http.StatusUnauthorized, response.CodeUnauthorized, "User not authenticated")
 		return
 	}
 
 	// Fetch user from MongoDB using the user ID from token
-	collection := config.DB.Collection("users")
-	var user models.User
-	err = collection.FindOne(context.TODO(), bson.M{"_id": userID}).Decode(&user)
+	user, err := ctrl.Store.Users.FindByID(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondError(c, http.StatusNotFound, response.CodeUserNotFound, "User not found", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"user": user})
+	response.OK(c, dto.NewSelfUser(*user))
 }
 
 // GetUserByID retrieves a user's details by their ID
-func GetUserByID(c *gin.Context) {
+func (ctrl *Controller) GetUserByID(c *gin.Context) {
     // Get user ID from URL parameter
     userIDParam := c.Param("id")
     userID, err := primitive.ObjectIDFromHex(userIDParam)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+        response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid user ID")
         return
     }
 
     // Fetch the user from MongoDB
-    collection := config.DB.Collection("users")
-    var user models.User
-    err = collection.FindOne(context.TODO(), bson.M{"_id": userID}).Decode(&user)
+    user, err := ctrl.Store.Users.FindByID(c.Request.Context(), userID)
     if err != nil {
-        c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+        respondError(c, http.StatusNotFound, response.CodeUserNotFound, "User not found", err)
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"user": user})
+    // Data yang dikirim tergantung siapa yang melihat: admin, pemilik akun, atau user lain
+    claims := c.MustGet("user").(jwt.MapClaims)
+    switch {
+    case claims["role"] == "admin":
+        response.OK(c, dto.NewAdminUser(*user))
+    case claims["user_id"] == userID.Hex():
+        response.OK(c, dto.NewSelfUser(*user))
+    default:
+        response.OK(c, dto.NewPublicUser(*user))
+    }
 }
 
 //Get All Owners (Admin can use this to choose an owner)
-func GetAllOwners(c *gin.Context) {
-    collection := config.DB.Collection("users")
-    var owners []models.User
+func (ctrl *Controller) GetAllOwners(c *gin.Context) {
+    q, ok := ctrl.parseQuery(c, dto.OwnerListQuery)
+    if !ok {
+        return
+    }
 
-    cursor, err := collection.Find(context.TODO(), bson.M{"role": "owner"})
+    page, err := ctrl.Store.Users.ListByRole(c.Request.Context(), "owner", q)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch owners"})
+        respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch owners", err)
         return
     }
-    defer cursor.Close(context.TODO())
-
-    for cursor.Next(context.TODO()) {
-        var owner models.User
-        if err := cursor.Decode(&owner); err != nil {
-            c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding owner"})
-            return
-        }
-        owners = append(owners, owner)
-    }
 
-    // Return only the email and _id (ownerID) to frontend
-    c.JSON(http.StatusOK, owners)
+    // Admin melihat data lengkap untuk memilih owner, selain admin hanya data publik
+    claims := c.MustGet("user").(jwt.MapClaims)
+    if claims["role"] == "admin" {
+        respondPage(c, q, page, dto.NewAdminUsers(page.Items))
+        return
+    }
+    respondPage(c, q, page, dto.NewPublicUsers(page.Items))
 }
 
 // Get Owner by ID (Admin can use this to view owner details)
-func GetOwnerByID(c *gin.Context) {
+func (ctrl *Controller) GetOwnerByID(c *gin.Context) {
     ownerID := c.Param("id")
     objectID, err := primitive.ObjectIDFromHex(ownerID)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner ID"})
+        response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid owner ID")
         return
     }
 
-    collection := config.DB.Collection("users")
-    var owner models.User
-
     // Fetch the owner data by ID and filter to include only name and _id
-    err = collection.FindOne(context.TODO(), bson.M{"_id": objectID, "role": "owner"}).Decode(&owner)
-    if err != nil {
-        if err == mongo.ErrNoDocuments {
-            c.JSON(http.StatusNotFound, gin.H{"error": "Owner not found"})
-        } else {
-            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch owner"})
-        }
+    owner, err := ctrl.Store.Users.FindByID(c.Request.Context(), objectID)
+    if err != nil && !errors.Is(err, store.ErrNotFound) {
+        respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to fetch owner", err)
+        return
+    }
+    if err != nil || owner.Role != "owner" {
+        response.Fail(c, http.StatusNotFound, response.CodeUserNotFound, "Owner not found")
         return
     }
 
     // Return only the _id and fullname of the owner
-    c.JSON(http.StatusOK, gin.H{
+    response.OK(c, gin.H{
         "owner_id":   owner.UserID,
         "owner_name": owner.FullName,
     })
 }
 
 // Update user (for the logged-in user or admin)
-func UpdateMe(c *gin.Context) {
+func (ctrl *Controller) UpdateMe(c *gin.Context) {
 	userID, err := getUserIDFromToken(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	// Check if the logged-in user is updating their own account or an admin is updating any user
-	if userID.Hex() != c.Param("id") {
-		claims, _ := c.Get("user")
-		role := claims.(jwt.MapClaims)["role"].(string)
-		if role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You can only update your own account"})
-			return
-		}
-	}
-
-	var updatedUser models.User
-	if err := c.ShouldBindJSON(&updatedUser); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
-		return
-	}
-
-	// Update user in MongoDB
-	collection := config.DB.Collection("users")
-	_, err = collection.UpdateOne(
-		context.TODO(),
-		bson.M{"_id": userID},
-		bson.M{"$set": updatedUser},
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		response.Fail(c, http.StatusUnauthorized, response.CodeUnauthorized, "User not authenticated")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
+	// Route /me tidak punya :id, yang diubah selalu akun dari token
+	ctrl.updateUser(c, userID)
 }
 
-func UpdateUser(c *gin.Context) {
+func (ctrl *Controller) UpdateUser(c *gin.Context) {
 	// Mendapatkan user ID dari token
 	loggedInUserID, err := getUserIDFromToken(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		response.Fail(c, http.StatusUnauthorized, response.CodeUnauthorized, "User not authenticated")
 		return
 	}
 
@@ -246,53 +231,70 @@ func UpdateUser(c *gin.Context) {
 	targetUserID := c.Param("id")
 	targetUserObjectID, err := primitive.ObjectIDFromHex(targetUserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid user ID format")
 		return
 	}
 
 	// Logika kontrol akses
 	if role != "admin" && loggedInUserID.Hex() != targetUserID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to update this user"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "You are not authorized to update this user")
 		return
 	}
 
-	// Parsing data input yang akan diupdate
-	var updatedUserData models.User
-	if err := c.ShouldBindJSON(&updatedUserData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	ctrl.updateUser(c, targetUserObjectID)
+}
+
+// updateUser menerapkan UpdateUserRequest ke user id, dipakai UpdateMe dan
+// UpdateUser. Field sensitif (password, role) tidak ada di DTO sehingga tidak
+// bisa diubah di sini.
+func (ctrl *Controller) updateUser(c *gin.Context, id primitive.ObjectID) {
+	var req dto.UpdateUserRequest
+	if !bind(c, &req, binding.JSON) {
 		return
 	}
+	set := req.Fields()
 
-	// Mencegah admin tidak sengaja mengubah field sensitif tertentu (misalnya password atau role)
-	updateFields := bson.M{
-		"fullname":    updatedUserData.FullName,
-		"email":       updatedUserData.Email,
-		"phonenumber": updatedUserData.PhoneNumber,
-		"picture":     updatedUserData.Picture,
+	if req.Email != "" {
+		user, err := ctrl.Store.Users.FindByID(c.Request.Context(), id)
+		if errors.Is(err, store.ErrNotFound) {
+			response.Fail(c, http.StatusNotFound, response.CodeUserNotFound, "User not found")
+			return
+		}
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update user", err)
+			return
+		}
+		// Email baru harus diverifikasi ulang, status verifikasi email lama tidak berlaku
+		if user.Email != req.Email {
+			set["verified_email"] = false
+		}
 	}
 
-	// Update user di MongoDB
-	collection := config.DB.Collection("users")
-	_, err = collection.UpdateOne(
-		context.TODO(),
-		bson.M{"_id": targetUserObjectID},
-		bson.M{"$set": updateFields},
-	)
+	err := ctrl.Store.Users.Update(c.Request.Context(), id, set)
+	if errors.Is(err, store.ErrNotFound) {
+		response.Fail(c, http.StatusNotFound, response.CodeUserNotFound, "User not found")
+		return
+	}
+	if errors.Is(err, store.ErrDuplicate) {
+		// Email sudah dipakai akun lain, ditolak unique index seperti saat Register
+		response.Fail(c, http.StatusConflict, response.CodeEmailAlreadyExists, "Email already in use")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update user", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
+	response.Message(c, http.StatusOK, nil, "User updated successfully")
 }
 
 // ResetPassword allows admin to reset a user's password
-func ResetPassword(c *gin.Context) {
+func (ctrl *Controller) ResetPassword(c *gin.Context) {
     // Only allow admin to reset passwords
     claims, _ := c.Get("user")
     role := claims.(jwt.MapClaims)["role"].(string)
     if role != "admin" {
-        c.JSON(http.StatusForbidden, gin.H{"error": "Only admin can reset passwords"})
+        response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only admin can reset passwords")
         return
     }
 
@@ -300,100 +302,86 @@ func ResetPassword(c *gin.Context) {
     userIDParam := c.Param("id")
     userID, err := primitive.ObjectIDFromHex(userIDParam)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+        response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid user ID")
         return
     }
 
-    var body struct {
-        NewPassword string `json:"new_password"`
-    }
-    if err := c.ShouldBindJSON(&body); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+    var body dto.ResetPasswordRequest
+    if !bind(c, &body, binding.JSON) {
         return
     }
 
     // Hash the new password
     hashedPassword, err := bcrypt.GenerateFromPassword([]byte(body.NewPassword), bcrypt.DefaultCost)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+        response.Fail(c, http.StatusInternalServerError, response.CodeInternal, "Failed to hash password")
         return
     }
 
     // Update password in MongoDB
-    collection := config.DB.Collection("users")
-    _, err = collection.UpdateOne(
-        context.TODO(),
-        bson.M{"_id": userID},
-        bson.M{"$set": bson.M{"password": string(hashedPassword)}},
-    )
+    err = ctrl.Store.Users.Update(c.Request.Context(), userID, bson.M{"password": string(hashedPassword)})
+    if errors.Is(err, store.ErrNotFound) {
+        response.Fail(c, http.StatusNotFound, response.CodeUserNotFound, "User not found")
+        return
+    }
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+        respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to reset password", err)
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+    response.Message(c, http.StatusOK, nil, "Password reset successfully")
 }
 
 // ChangePassword allows a logged-in user to change their password
-func ChangePassword(c *gin.Context) {
+func (ctrl *Controller) ChangePassword(c *gin.Context) {
     userID, err := getUserIDFromToken(c)
     if err != nil {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+        response.Fail(c, http.StatusUnauthorized, response.CodeUnauthorized, "User not authenticated")
         return
     }
 
-    var body struct {
-        OldPassword string `json:"old_password"`
-        NewPassword string `json:"new_password"`
-    }
-    if err := c.ShouldBindJSON(&body); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+    var body dto.ChangePasswordRequest
+    if !bind(c, &body, binding.JSON) {
         return
     }
 
     // Fetch user from MongoDB
-    collection := config.DB.Collection("users")
-    var user models.User
-    err = collection.FindOne(context.TODO(), bson.M{"_id": userID}).Decode(&user)
+    user, err := ctrl.Store.Users.FindByID(c.Request.Context(), userID)
     if err != nil {
-        c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+        respondError(c, http.StatusNotFound, response.CodeUserNotFound, "User not found", err)
         return
     }
 
     // Verify old password
     if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(body.OldPassword)); err != nil {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "Old password is incorrect"})
+        response.Fail(c, http.StatusUnauthorized, response.CodeInvalidCredentials, "Old password is incorrect")
         return
     }
 
     // Hash the new password
     hashedPassword, err := bcrypt.GenerateFromPassword([]byte(body.NewPassword), bcrypt.DefaultCost)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+        response.Fail(c, http.StatusInternalServerError, response.CodeInternal, "Failed to hash password")
         return
     }
 
     // Update password in MongoDB
-    _, err = collection.UpdateOne(
-        context.TODO(),
-        bson.M{"_id": userID},
-        bson.M{"$set": bson.M{"password": string(hashedPassword)}},
-    )
+    err = ctrl.Store.Users.Update(c.Request.Context(), userID, bson.M{"password": string(hashedPassword)})
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
+        respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update password", err)
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+    response.Message(c, http.StatusOK, nil, "Password changed successfully")
 }
 
 // UpdateUserRole allows admin to update the role of a user
-func UpdateUserRole(c *gin.Context) {
+func (ctrl *Controller) UpdateUserRole(c *gin.Context) {
     // Only allow admin to update roles
     claims, _ := c.Get("user")
     role := claims.(jwt.MapClaims)["role"].(string)
     if role != "admin" {
-        c.JSON(http.StatusForbidden, gin.H{"error": "Only admin can update roles"})
+        response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only admin can update roles")
         return
     }
 
@@ -401,46 +389,35 @@ func UpdateUserRole(c *gin.Context) {
     userIDParam := c.Param("id")
     userID, err := primitive.ObjectIDFromHex(userIDParam)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+        response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid user ID")
         return
     }
 
-    var body struct {
-        Role string `json:"role"`
-    }
-    if err := c.ShouldBindJSON(&body); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+    var body dto.UpdateRoleRequest
+    if !bind(c, &body, binding.JSON) {
         return
     }
 
-    // Validate role
-    validRoles := map[string]bool{"user": true, "owner": true, "admin": true}
-    if !validRoles[body.Role] {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+    // Update role in MongoDB
+    err = ctrl.Store.Users.Update(c.Request.Context(), userID, bson.M{"role": body.Role})
+    if errors.Is(err, store.ErrNotFound) {
+        response.Fail(c, http.StatusNotFound, response.CodeUserNotFound, "User not found")
         return
     }
-
-    // Update role in MongoDB
-    collection := config.DB.Collection("users")
-    _, err = collection.UpdateOne(
-        context.TODO(),
-        bson.M{"_id": userID},
-        bson.M{"$set": bson.M{"role": body.Role}},
-    )
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+        respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to update role", err)
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"message": "Role updated successfully"})
+    response.Message(c, http.StatusOK, nil, "Role updated successfully")
 }
 
 // DeleteUser deletes a user (self or by admin)
-func DeleteUser(c *gin.Context) {
+func (ctrl *Controller) DeleteUser(c *gin.Context) {
 	// Get the logged-in user's ID from the token
 	loggedInUserID, err := getUserIDFromToken(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		response.Fail(c, http.StatusUnauthorized, response.CodeUnauthorized, "User not authenticated")
 		return
 	}
 
@@ -448,7 +425,7 @@ func DeleteUser(c *gin.Context) {
 	targetUserID := c.Param("id")
 	targetUserObjectID, err := primitive.ObjectIDFromHex(targetUserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		response.Fail(c, http.StatusBadRequest, response.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
@@ -458,27 +435,28 @@ func DeleteUser(c *gin.Context) {
 
 	// Check permissions
 	if role != "admin" && loggedInUserID != targetUserObjectID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can delete other users"})
+		response.Fail(c, http.StatusForbidden, response.CodeForbidden, "Only admins can delete other users")
 		return
 	}
 
-	// Access the users collection
-	collection := config.DB.Collection("users")
-
 	// Find the user to ensure they exist
-	var user models.User
-	err = collection.FindOne(context.TODO(), bson.M{"_id": targetUserObjectID}).Decode(&user)
+	_, err = ctrl.Store.Users.FindByID(c.Request.Context(), targetUserObjectID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondError(c, http.StatusNotFound, response.CodeUserNotFound, "User not found", err)
 		return
 	}
 
 	// Perform the deletion
-	_, err = collection.DeleteOne(context.TODO(), bson.M{"_id": targetUserObjectID})
+	err = ctrl.Store.Users.Delete(c.Request.Context(), targetUserObjectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		respondError(c, http.StatusInternalServerError, response.CodeInternal, "Failed to delete user", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+	// Token yang masih berlaku tidak boleh dipakai lagi
+	if _, err := ctrl.Store.Sessions.RevokeAll(c.Request.Context(), targetUserObjectID); err != nil {
+		middlewares.Logger(c).Error("failed to revoke sessions of deleted user", "error", err)
+	}
+
+	response.Message(c, http.StatusOK, nil, "User deleted successfully")
 }
\ No newline at end of file
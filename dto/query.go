@@ -0,0 +1,85 @@
+package dto
+
+import "github.com/organisasi/kosconnectbackend/query"
+
+// Sort dan filter yang diterima endpoint list, dipakai handler dan
+// dokumentasi OpenAPI. Semua endpoint juga menerima page, limit dan cursor.
+
+var timestamps = map[string]string{"created_at": "created_at", "updated_at": "updated_at"}
+
+func withTimestamps(fields map[string]string) map[string]string {
+	for name, field := range timestamps {
+		fields[name] = field
+	}
+	return fields
+}
+
+// RoomListQuery untuk GET /api/rooms/.
+var RoomListQuery = query.Spec{
+	Sort:        withTimestamps(map[string]string{"room_type": "room_type", "number_available": "number_available"}),
+	DefaultSort: "-created_at",
+	Filters: map[string]query.Filter{
+		"boarding_house_id": {Field: "boarding_house_id", Kind: query.ObjectID},
+		"status":            {Field: "status"},
+	},
+}
+
+// RoomLandingPageQuery untuk GET /api/rooms/home. Hasil agregasi tidak
+// membawa created_at, jadi urutan terbaru memakai _id.
+var RoomLandingPageQuery = query.Spec{
+	Sort:        map[string]string{"created_at": "_id", "room_name": "room_name", "category_name": "category_name"},
+	DefaultSort: "-created_at",
+	Filters: map[string]query.Filter{
+		"category_id": {Field: "category_id", Kind: query.ObjectID},
+		"owner_id":    {Field: "owner_id", Kind: query.ObjectID},
+	},
+}
+
+// BoardingHouseListQuery untuk GET /api/boardingHouses/.
+var BoardingHouseListQuery = query.Spec{
+	Sort:        withTimestamps(map[string]string{"name": "name"}),
+	DefaultSort: "-created_at",
+	Filters: map[string]query.Filter{
+		"owner_id":    {Field: "owner_id", Kind: query.ObjectID},
+		"category_id": {Field: "category_id", Kind: query.ObjectID},
+	},
+}
+
+// TransactionListQuery untuk GET /api/transaction/.
+var TransactionListQuery = query.Spec{
+	Sort:        withTimestamps(map[string]string{"check_in_date": "check_in_date", "total": "total"}),
+	DefaultSort: "-created_at",
+	Filters: map[string]query.Filter{
+		"payment_status":    {Field: "payment_status"},
+		"payment_term":      {Field: "payment_term"},
+		"payment_method":    {Field: "payment_method"},
+		"user_id":           {Field: "user_id", Kind: query.ObjectID},
+		"owner_id":          {Field: "owner_id", Kind: query.ObjectID},
+		"boarding_house_id": {Field: "boarding_house_id", Kind: query.ObjectID},
+		"room_id":           {Field: "room_id", Kind: query.ObjectID},
+	},
+}
+
+// UserListQuery untuk GET /api/users/.
+var UserListQuery = query.Spec{
+	Sort:        withTimestamps(map[string]string{"fullname": "fullname", "email": "email"}),
+	DefaultSort: "-created_at",
+	Filters: map[string]query.Filter{
+		"role": {Field: "role"},
+	},
+}
+
+// OwnerListQuery untuk GET /api/users/owner; role selalu owner.
+var OwnerListQuery = query.Spec{
+	Sort:        withTimestamps(map[string]string{"fullname": "fullname"}),
+	DefaultSort: "fullname",
+}
+
+// CustomFacilityListQuery untuk GET /api/customFacilities/.
+var CustomFacilityListQuery = query.Spec{
+	Sort:        withTimestamps(map[string]string{"name": "name", "price": "price"}),
+	DefaultSort: "-created_at",
+	Filters: map[string]query.Filter{
+		"owner_id": {Field: "owner_id", Kind: query.ObjectID},
+	},
+}
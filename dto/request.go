@@ -0,0 +1,271 @@
+// Package dto berisi bentuk request dan response API, terpisah dari model
+// database di package models. Client tidak bisa lagi mengisi field yang
+// seharusnya diatur server (role, verified_email, _id, dll).
+//
+// Aturan validasi ditulis sebagai tag `binding`, lihat package validation
+// untuk tag kustom (objectid, phone_id, payment_term, future_date).
+package dto
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/validation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ===== Auth =====
+
+// RegisterRequest untuk POST /auth/register. Role dipilih belakangan lewat /auth/assign-role.
+type RegisterRequest struct {
+	FullName    string `json:"fullname" binding:"required,min=2,max=100"`
+	Email       string `json:"email" binding:"required,email"`
+	PhoneNumber string `json:"phonenumber" binding:"omitempty,phone_id"`
+	Password    string `json:"password" binding:"required,min=8,max=72"` // bcrypt hanya memakai 72 byte pertama
+}
+
+// LoginRequest untuk POST /auth/login.
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// AssignRoleRequest untuk PUT /auth/assign-role. Route ini publik, jadi
+// seperti GoogleAuthRequest admin tidak bisa dipilih sendiri.
+type AssignRoleRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=user owner"`
+}
+
+// GoogleAuthRequest untuk POST /auth/googleauth. IDToken adalah credential
+// dari Google Identity Services. Role hanya dipakai jika akun belum punya
+// role; admin tidak bisa dipilih sendiri.
+type GoogleAuthRequest struct {
+	IDToken string `json:"id_token" binding:"required"`
+	Role    string `json:"role" binding:"omitempty,oneof=user owner"`
+}
+
+// RefreshRequest untuk POST /auth/refresh. Jika kosong, refresh token
+// dibaca dari cookie refreshToken.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LogoutRequest untuk POST /auth/logout. All mencabut semua session user
+// (logout dari semua perangkat).
+type LogoutRequest struct {
+	All bool `json:"all"`
+}
+
+// ResendVerificationRequest untuk POST /auth/resend-verification.
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ForgotPasswordRequest untuk POST /auth/forgot-password.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetForgottenPasswordRequest untuk POST /auth/reset-password. Token
+// berasal dari link di email forgot-password.
+type ResetForgottenPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=72"`
+}
+
+// ===== Users =====
+
+// CreateUserRequest untuk POST /api/users/ (admin).
+type CreateUserRequest struct {
+	FullName    string `json:"fullname" binding:"required,min=2,max=100"`
+	Email       string `json:"email" binding:"required,email"`
+	PhoneNumber string `json:"phonenumber" binding:"omitempty,phone_id"`
+	Password    string `json:"password" binding:"required,min=8,max=72"`
+	Role        string `json:"role" binding:"required,oneof=user owner admin"`
+}
+
+// UpdateUserRequest untuk PUT /api/users/me dan PUT /api/users/:id.
+// Field kosong tidak diubah.
+type UpdateUserRequest struct {
+	FullName    string `json:"fullname" binding:"omitempty,min=2,max=100"`
+	Email       string `json:"email" binding:"omitempty,email"`
+	PhoneNumber string `json:"phonenumber" binding:"omitempty,phone_id"`
+	Picture     string `json:"picture" binding:"omitempty,url"`
+}
+
+// Fields mengembalikan field yang diisi untuk $set.
+func (r UpdateUserRequest) Fields() bson.M {
+	set := bson.M{"updated_at": time.Now()}
+	if r.FullName != "" {
+		set["fullname"] = r.FullName
+	}
+	if r.Email != "" {
+		set["email"] = r.Email
+	}
+	if r.PhoneNumber != "" {
+		set["phonenumber"] = r.PhoneNumber
+	}
+	if r.Picture != "" {
+		set["picture"] = r.Picture
+	}
+	return set
+}
+
+// ChangePasswordRequest untuk PUT /api/users/change-password.
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=72,nefield=OldPassword"`
+}
+
+// ResetPasswordRequest untuk PUT /api/users/:id/reset-password (admin).
+type ResetPasswordRequest struct {
+	NewPassword string `json:"new_password" binding:"required,min=8,max=72"`
+}
+
+// UpdateRoleRequest untuk PUT /api/users/:id/role (admin).
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user owner admin"`
+}
+
+// ===== Category & facility =====
+
+// CategoryRequest untuk POST dan PUT /api/categories.
+type CategoryRequest struct {
+	Name string `json:"name" binding:"required,min=2,max=50"`
+}
+
+// FacilityRequest untuk POST dan PUT /api/facility.
+type FacilityRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	Type string `json:"type" binding:"required,oneof=room boarding_house"`
+}
+
+// CustomFacilityRequest untuk POST dan PUT /api/customFacilities.
+// OwnerID hanya dipakai admin saat create; owner selalu memakai ID dari token.
+type CustomFacilityRequest struct {
+	Name    string  `json:"name" binding:"required,max=100"`
+	Price   float64 `json:"price" binding:"gte=0"`
+	OwnerID string  `json:"owner_id" binding:"omitempty,objectid"`
+}
+
+// ===== Boarding house & room (multipart form) =====
+
+// BoardingHouseForm untuk POST /api/boardingHouses. Facilities berupa
+// JSON array ID fasilitas bertipe boarding_house, misal ["6756..."].
+type BoardingHouseForm struct {
+	Name        string `form:"name" binding:"required,max=150"`
+	Address     string `form:"address" binding:"required,max=500"`
+	Description string `form:"description" binding:"required"`
+	Rules       string `form:"rules"`
+	CategoryID  string `form:"category_id" binding:"required,objectid"`
+	OwnerID     string `form:"owner_id" binding:"omitempty,objectid"` // Wajib jika admin
+	Facilities  string `form:"facilities" binding:"required,json"`
+}
+
+// UpdateBoardingHouseForm untuk PUT /api/boardingHouses/:id. Field kosong tidak diubah.
+type UpdateBoardingHouseForm struct {
+	Name        string `form:"name" binding:"omitempty,max=150"`
+	Address     string `form:"address" binding:"omitempty,max=500"`
+	Description string `form:"description"`
+	Rules       string `form:"rules"`
+	CategoryID  string `form:"category_id" binding:"omitempty,objectid"`
+	Facilities  string `form:"facilities" binding:"omitempty,json"`
+}
+
+// RoomForm untuk POST /api/rooms/:boardingHouseID dan PUT /api/rooms/:id.
+// Harga berupa angka Rupiah tanpa pemisah; minimal satu harga harus diisi.
+// RoomFacilities dan CustomFacilities berupa JSON array ID.
+type RoomForm struct {
+	RoomType         string `form:"room_type" binding:"required,max=100"`
+	Size             string `form:"size" binding:"omitempty,max=50"`
+	PriceMonthly     string `form:"price_monthly" binding:"omitempty,number,max=12"`
+	PriceQuarterly   string `form:"price_quarterly" binding:"omitempty,number,max=12"`
+	PriceSemiAnnual  string `form:"price_semi_annual" binding:"omitempty,number,max=12"`
+	PriceYearly      string `form:"price_yearly" binding:"omitempty,number,max=12"`
+	NumberAvailable  string `form:"number_available" binding:"omitempty,number,max=6"`
+	RoomFacilities   string `form:"room_facilities" binding:"required,json"`
+	CustomFacilities string `form:"custom_facilities" binding:"required,json"`
+}
+
+// Price mengembalikan harga kamar. Aman dipanggil setelah validasi (hanya digit).
+func (f RoomForm) Price() models.RoomPrice {
+	return models.RoomPrice{
+		Monthly:    atoi(f.PriceMonthly),
+		Quarterly:  atoi(f.PriceQuarterly),
+		SemiAnnual: atoi(f.PriceSemiAnnual),
+		Yearly:     atoi(f.PriceYearly),
+	}
+}
+
+// Available mengembalikan jumlah kamar tersedia.
+func (f RoomForm) Available() int {
+	return atoi(f.NumberAvailable)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// ===== Transaction & payment =====
+
+// CreateTransactionQuery adalah query string POST /api/transaction.
+type CreateTransactionQuery struct {
+	RoomID          string `form:"room_id" binding:"required,objectid"`
+	BoardingHouseID string `form:"boarding_house_id" binding:"required,objectid"`
+	OwnerID         string `form:"owner_id" binding:"required,objectid"`
+	UserID          string `form:"user_id" binding:"required,objectid"`
+}
+
+// CreateTransactionRequest adalah body POST /api/transaction.
+type CreateTransactionRequest struct {
+	CustomFacilities []string            `json:"custom_facilities" binding:"omitempty,dive,objectid"`
+	PaymentTerm      string              `json:"payment_term" binding:"required,payment_term"`
+	CheckInDate      string              `json:"check_in_date" binding:"required,future_date"` // YYYY-MM-DD
+	PersonalInfo     PersonalInfoRequest `json:"personal_info" binding:"required"`
+}
+
+// CheckIn mengembalikan tanggal check-in. Aman dipanggil setelah validasi.
+func (r CreateTransactionRequest) CheckIn() time.Time {
+	date, _ := time.ParseInLocation(validation.DateLayout, r.CheckInDate, time.Local)
+	return date
+}
+
+// CustomFacilityIDs mengembalikan ID custom facility. Aman dipanggil setelah validasi.
+func (r CreateTransactionRequest) CustomFacilityIDs() []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(r.CustomFacilities))
+	for _, hex := range r.CustomFacilities {
+		id, _ := primitive.ObjectIDFromHex(hex)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// PersonalInfoRequest adalah data penyewa yang diteruskan ke Midtrans.
+type PersonalInfoRequest struct {
+	FullName    string `json:"full_name" binding:"required,max=100"`
+	Email       string `json:"email" binding:"required,email"`
+	PhoneNumber string `json:"phone_number" binding:"required,phone_id"`
+}
+
+// Model mengubah request menjadi models.PersonalInfo.
+func (p PersonalInfoRequest) Model() models.PersonalInfo {
+	return models.PersonalInfo{FullName: p.FullName, Email: p.Email, PhoneNumber: p.PhoneNumber}
+}
+
+// UpdateTransactionRequest untuk PUT /api/transaction/:id/payment-status.
+type UpdateTransactionRequest struct {
+	PaymentStatus string `json:"payment_status" binding:"required,oneof=pending paid failed cancelled"`
+	PaymentMethod string `json:"payment_method" binding:"omitempty,oneof=credit_card bank_transfer ewallet cash"`
+}
+
+// PaymentNotification adalah field notifikasi Midtrans yang dipakai.
+// Field lain di payload diabaikan.
+type PaymentNotification struct {
+	OrderID           string `json:"order_id" binding:"required"`
+	TransactionStatus string `json:"transaction_status" binding:"required"`
+	PaymentType       string `json:"payment_type"`
+}
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Response user dibedakan per audiens. Tidak ada satupun yang memuat
+// password atau verification_token, jadi handler tidak boleh mengirim
+// models.User langsung; selalu lewat mapper di bawah.
+
+// PublicUser adalah data user yang boleh dilihat user lain, misal owner kos.
+type PublicUser struct {
+	UserID   primitive.ObjectID `json:"user_id"`
+	FullName string             `json:"fullname"`
+	Role     string             `json:"role,omitempty"`
+	Picture  string             `json:"picture,omitempty"`
+}
+
+// SelfUser adalah data akun untuk pemiliknya sendiri (GET /api/users/me).
+type SelfUser struct {
+	UserID         primitive.ObjectID `json:"user_id"`
+	FullName       string             `json:"fullname"`
+	Email          string             `json:"email"`
+	PhoneNumber    string             `json:"phonenumber,omitempty"`
+	Role           string             `json:"role,omitempty"`
+	Picture        string             `json:"picture,omitempty"`
+	VerifiedEmail  bool               `json:"verified_email"`
+	IsRoleAssigned bool               `json:"is_role_assigned"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// AdminUser adalah data user untuk admin. Selain SelfUser, admin melihat
+// apakah akun punya password (bukan hanya Google) dan masih menunggu verifikasi,
+// tanpa melihat nilai hash atau token-nya.
+type AdminUser struct {
+	SelfUser
+	HasPassword         bool `json:"has_password"`
+	PendingVerification bool `json:"pending_verification"`
+}
+
+// NewPublicUser memetakan models.User ke PublicUser.
+func NewPublicUser(u models.User) PublicUser {
+	return PublicUser{UserID: u.UserID, FullName: u.FullName, Role: u.Role, Picture: u.Picture}
+}
+
+// NewSelfUser memetakan models.User ke SelfUser.
+func NewSelfUser(u models.User) SelfUser {
+	return SelfUser{
+		UserID:         u.UserID,
+		FullName:       u.FullName,
+		Email:          u.Email,
+		PhoneNumber:    u.PhoneNumber,
+		Role:           u.Role,
+		Picture:        u.Picture,
+		VerifiedEmail:  u.VerifiedEmail,
+		IsRoleAssigned: u.IsRoleAssigned,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
+	}
+}
+
+// NewAdminUser memetakan models.User ke AdminUser.
+func NewAdminUser(u models.User) AdminUser {
+	return AdminUser{
+		SelfUser:            NewSelfUser(u),
+		HasPassword:         u.Password != "",
+		PendingVerification: !u.VerifiedEmail && u.VerificationToken != "",
+	}
+}
+
+// NewPublicUsers memetakan daftar user ke PublicUser.
+func NewPublicUsers(users []models.User) []PublicUser {
+	out := make([]PublicUser, 0, len(users))
+	for _, u := range users {
+		out = append(out, NewPublicUser(u))
+	}
+	return out
+}
+
+// NewAdminUsers memetakan daftar user ke AdminUser.
+func NewAdminUsers(users []models.User) []AdminUser {
+	out := make([]AdminUser, 0, len(users))
+	for _, u := range users {
+		out = append(out, NewAdminUser(u))
+	}
+	return out
+}
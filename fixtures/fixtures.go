@@ -0,0 +1,171 @@
+// Package fixtures memuat dataset contoh (kategori, fasilitas, user, kos,
+// kamar, custom facility dan transaksi) dari file YAML atau JSON ke store.
+// Dipakai oleh `kosctl seed` untuk database development dan oleh test
+// integrasi dengan store.NewMemory().
+//
+// Dokumen di file saling merujuk lewat key, bukan ObjectID. ID dibentuk
+// dari key secara deterministik (lihat ID), jadi test bisa langsung
+// memakai fixtures.ID("room-melati-a") tanpa membaca database.
+package fixtures
+
+import (
+	"crypto/sha256"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gopkg.in/yaml.v3"
+)
+
+// Version adalah versi format dataset yang didukung loader. Naikkan jika
+// struktur file berubah dan perbarui file di data/.
+const Version = 1
+
+//go:embed data
+var files embed.FS
+
+// DefaultFile adalah dataset bawaan, di-embed ke binary.
+const DefaultFile = "data/dev.yaml"
+
+// Dataset adalah isi satu file fixture.
+type Dataset struct {
+	Version          int              `yaml:"version"`
+	Categories       []Category       `yaml:"categories"`
+	Facilities       []Facility       `yaml:"facilities"`
+	Users            []User           `yaml:"users"`
+	CustomFacilities []CustomFacility `yaml:"custom_facilities"`
+	BoardingHouses   []BoardingHouse  `yaml:"boarding_houses"`
+	Rooms            []Room           `yaml:"rooms"`
+	Transactions     []Transaction    `yaml:"transactions"`
+}
+
+type Category struct {
+	Key  string `yaml:"key"`
+	Name string `yaml:"name"`
+}
+
+type Facility struct {
+	Key  string `yaml:"key"`
+	Name string `yaml:"name"`
+	Type string `yaml:"type"` // "room" atau "boarding_house"
+}
+
+// User menyimpan password dalam bentuk plain; loader yang membuat hash-nya.
+type User struct {
+	Key               string `yaml:"key"`
+	FullName          string `yaml:"fullname"`
+	Email             string `yaml:"email"`
+	PhoneNumber       string `yaml:"phonenumber"`
+	Role              string `yaml:"role"`
+	Password          string `yaml:"password"` // Kosong untuk user Google
+	Picture           string `yaml:"picture"`
+	VerifiedEmail     bool   `yaml:"verified_email"`
+	VerificationToken string `yaml:"verification_token"`
+}
+
+type CustomFacility struct {
+	Key   string  `yaml:"key"`
+	Owner string  `yaml:"owner"`
+	Name  string  `yaml:"name"`
+	Price float64 `yaml:"price"`
+}
+
+type BoardingHouse struct {
+	Key         string   `yaml:"key"`
+	Owner       string   `yaml:"owner"`
+	Category    string   `yaml:"category"`
+	Name        string   `yaml:"name"`
+	Address     string   `yaml:"address"`
+	Description string   `yaml:"description"`
+	Rules       string   `yaml:"rules"`
+	Facilities  []string `yaml:"facilities"`
+	Images      []string `yaml:"images"`
+}
+
+// Room tidak menyimpan number_available; loader menghitungnya dari
+// capacity dikurangi transaksi aktif, sama seperti kosctl recompute-availability.
+type Room struct {
+	Key              string   `yaml:"key"`
+	BoardingHouse    string   `yaml:"boarding_house"`
+	RoomType         string   `yaml:"room_type"`
+	Size             string   `yaml:"size"`
+	Price            Price    `yaml:"price"`
+	Facilities       []string `yaml:"facilities"`
+	CustomFacilities []string `yaml:"custom_facilities"`
+	Capacity         int      `yaml:"capacity"`
+	Images           []string `yaml:"images"`
+}
+
+type Price struct {
+	Monthly    int `yaml:"monthly"`
+	Quarterly  int `yaml:"quarterly"`
+	SemiAnnual int `yaml:"semi_annual"`
+	Yearly     int `yaml:"yearly"`
+}
+
+// Transaction hanya berisi pilihan penyewa; harga, PPN, total dan kode
+// transaksi dihitung loader seperti CreateTransaction. Tanggal ditulis
+// relatif terhadap waktu load agar dataset tidak kedaluwarsa.
+type Transaction struct {
+	Key              string   `yaml:"key"`
+	User             string   `yaml:"user"`
+	Room             string   `yaml:"room"`
+	CustomFacilities []string `yaml:"custom_facilities"`
+	PaymentTerm      string   `yaml:"payment_term"`
+	PaymentStatus    string   `yaml:"payment_status"`
+	PaymentMethod    string   `yaml:"payment_method"`
+	CheckInDays      int      `yaml:"check_in_days"`    // Hari setelah waktu load, boleh negatif
+	CreatedDaysAgo   int      `yaml:"created_days_ago"` // Hari sebelum waktu load
+}
+
+// ID mengembalikan ObjectID untuk key fixture. Nilainya selalu sama untuk
+// key yang sama, sehingga seed bisa diulang tanpa membuat data ganda.
+func ID(key string) primitive.ObjectID {
+	sum := sha256.Sum256([]byte("kosconnect-fixture:" + key))
+	var id primitive.ObjectID
+	copy(id[:], sum[:len(id)])
+	return id
+}
+
+// Default membaca dataset bawaan.
+func Default() (*Dataset, error) {
+	data, err := files.ReadFile(DefaultFile)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(DefaultFile, data)
+}
+
+// ReadFile membaca dataset dari file .yaml, .yml atau .json.
+func ReadFile(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fixtures: read %s: %w", path, err)
+	}
+	return Parse(path, data)
+}
+
+// Parse mengurai dataset. Seperti config, JSON dibaca dengan parser YAML
+// karena JSON adalah subset YAML. name hanya dipakai untuk pesan error dan
+// menentukan jenis file.
+func Parse(name string, data []byte) (*Dataset, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("fixtures: unsupported file type %s (use .yaml, .yml or .json)", name)
+	}
+	var ds Dataset
+	if err := yaml.Unmarshal(data, &ds); err != nil {
+		return nil, fmt.Errorf("fixtures: parse %s: %w", name, err)
+	}
+	if ds.Version != Version {
+		return nil, fmt.Errorf("fixtures: %s has version %d, this build supports version %d", name, ds.Version, Version)
+	}
+	if err := ds.Validate(); err != nil {
+		return nil, fmt.Errorf("fixtures: %s: %w", name, err)
+	}
+	return &ds, nil
+}
@@ -0,0 +1,96 @@
+package fixtures
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/organisasi/kosconnectbackend/store"
+)
+
+func TestLoadDefault(t *testing.T) {
+	ctx := context.Background()
+	stores := store.NewMemory()
+
+	res, err := LoadDefault(ctx, stores)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, _ := Default()
+	for collection, want := range map[string]int{
+		store.CollectionCategories:       len(ds.Categories),
+		store.CollectionFacilities:       len(ds.Facilities),
+		store.CollectionUsers:            len(ds.Users),
+		store.CollectionCustomFacilities: len(ds.CustomFacilities),
+		store.CollectionBoardingHouses:   len(ds.BoardingHouses),
+		store.CollectionRooms:            len(ds.Rooms),
+		store.CollectionTransactions:     len(ds.Transactions),
+	} {
+		if res.Inserted[collection] != want {
+			t.Errorf("inserted %d %s, want %d", res.Inserted[collection], collection, want)
+		}
+	}
+
+	// Load ulang tidak membuat data ganda
+	res, err = LoadDefault(ctx, stores)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Inserted) != 0 {
+		t.Errorf("second load inserted %v, want nothing", res.Inserted)
+	}
+
+	// room-kamboja-a punya capacity 1 dan satu transaksi paid
+	room, err := stores.Rooms.FindByID(ctx, ID("room-kamboja-a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if room.NumberAvailable != 0 || room.Status != "Tidak Tersedia" {
+		t.Errorf("room-kamboja-a: number_available %d status %q, want 0 Tidak Tersedia", room.NumberAvailable, room.Status)
+	}
+
+	trx, err := stores.Transactions.FindByID(ctx, ID("trx-pending"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1.500.000 + laundry 75.000, ditambah PPN 11%
+	if trx.Subtotal != 1575000 || trx.Total != 1748250 {
+		t.Errorf("trx-pending: subtotal %v total %v, want 1575000 1748250", trx.Subtotal, trx.Total)
+	}
+}
+
+func TestDefaultCoversEveryPaymentStatus(t *testing.T) {
+	ds, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for _, trx := range ds.Transactions {
+		seen[trx.PaymentStatus] = true
+	}
+	for _, status := range []string{"pending", "settlement", "capture", "paid", "expire", "deny", "cancel", "failed", "cancelled"} {
+		if !seen[status] {
+			t.Errorf("no transaction with payment_status %q", status)
+		}
+	}
+}
+
+func TestParseRejectsBrokenReferences(t *testing.T) {
+	_, err := Parse("broken.json", []byte(`{
+		"version": 1,
+		"users": [{"key": "u", "role": "user"}],
+		"boarding_houses": [{"key": "bh", "owner": "u", "category": "missing"}]
+	}`))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{`owner "u" must have role owner`, `category "missing" does not exist`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	if _, err := Parse("old.yaml", []byte("version: 0")); err == nil || !strings.Contains(err.Error(), "version 0") {
+		t.Errorf("expected a version error, got %v", err)
+	}
+}
@@ -0,0 +1,371 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/store"
+	"github.com/organisasi/kosconnectbackend/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Result menghitung dokumen per koleksi yang dimasukkan dan yang dilewati
+// karena sudah ada.
+type Result struct {
+	Inserted map[string]int
+	Skipped  map[string]int
+}
+
+// Validate memastikan setiap key unik dan setiap rujukan mengarah ke
+// dokumen dengan jenis yang benar.
+func (ds *Dataset) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	keys := map[string]string{}
+	define := func(kind, key string) {
+		if key == "" {
+			fail("%s without key", kind)
+			return
+		}
+		if other, ok := keys[key]; ok {
+			fail("key %q is used by both a %s and a %s", key, other, kind)
+			return
+		}
+		keys[key] = kind
+	}
+	ref := func(kind, key, field, target string) {
+		if keys[target] != field {
+			fail("%s %q: %s %q does not exist", kind, key, field, target)
+		}
+	}
+
+	for _, c := range ds.Categories {
+		define("category", c.Key)
+	}
+	facilityType := map[string]string{}
+	for _, f := range ds.Facilities {
+		define("facility", f.Key)
+		if f.Type != "room" && f.Type != "boarding_house" {
+			fail("facility %q: type must be room or boarding_house", f.Key)
+		}
+		facilityType[f.Key] = f.Type
+	}
+	role := map[string]string{}
+	for _, u := range ds.Users {
+		define("user", u.Key)
+		if u.Role != "user" && u.Role != "owner" && u.Role != "admin" {
+			fail("user %q: role must be user, owner or admin", u.Key)
+		}
+		if u.Password != "" && len(u.Password) < 8 {
+			fail("user %q: password must be at least 8 characters", u.Key)
+		}
+		role[u.Key] = u.Role
+	}
+	facilityOwner := map[string]string{}
+	for _, cf := range ds.CustomFacilities {
+		define("custom facility", cf.Key)
+		ref("custom facility", cf.Key, "user", cf.Owner)
+		facilityOwner[cf.Key] = cf.Owner
+	}
+	houseOwner := map[string]string{}
+	for _, bh := range ds.BoardingHouses {
+		define("boarding house", bh.Key)
+		ref("boarding house", bh.Key, "user", bh.Owner)
+		if role[bh.Owner] != "owner" {
+			fail("boarding house %q: owner %q must have role owner", bh.Key, bh.Owner)
+		}
+		ref("boarding house", bh.Key, "category", bh.Category)
+		for _, f := range bh.Facilities {
+			if facilityType[f] != "boarding_house" {
+				fail("boarding house %q: %q is not a boarding_house facility", bh.Key, f)
+			}
+		}
+		houseOwner[bh.Key] = bh.Owner
+	}
+	roomPrice := map[string]Price{}
+	roomOwner := map[string]string{}
+	for _, r := range ds.Rooms {
+		define("room", r.Key)
+		ref("room", r.Key, "boarding house", r.BoardingHouse)
+		for _, f := range r.Facilities {
+			if facilityType[f] != "room" {
+				fail("room %q: %q is not a room facility", r.Key, f)
+			}
+		}
+		for _, cf := range r.CustomFacilities {
+			if facilityOwner[cf] != houseOwner[r.BoardingHouse] {
+				fail("room %q: custom facility %q does not belong to the boarding house owner", r.Key, cf)
+			}
+		}
+		if r.Price == (Price{}) {
+			fail("room %q: at least one price is required", r.Key)
+		}
+		roomPrice[r.Key] = r.Price
+		roomOwner[r.Key] = houseOwner[r.BoardingHouse]
+	}
+	for _, t := range ds.Transactions {
+		define("transaction", t.Key)
+		ref("transaction", t.Key, "user", t.User)
+		ref("transaction", t.Key, "room", t.Room)
+		if !validation.ValidPaymentTerm(t.PaymentTerm) {
+			fail("transaction %q: payment_term must be one of %s", t.Key, strings.Join(validation.PaymentTerms, ", "))
+		} else if priceFor(roomPrice[t.Room], t.PaymentTerm) == 0 {
+			fail("transaction %q: room %q has no %s price", t.Key, t.Room, t.PaymentTerm)
+		}
+		if t.PaymentStatus == "" {
+			fail("transaction %q: payment_status is required", t.Key)
+		}
+		for _, cf := range t.CustomFacilities {
+			if facilityOwner[cf] != roomOwner[t.Room] {
+				fail("transaction %q: custom facility %q does not belong to the room owner", t.Key, cf)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Load memasukkan dataset ke stores. Dokumen yang sudah ada (ID atau
+// unique index sama) dilewati, jadi Load aman dijalankan berulang.
+func Load(ctx context.Context, stores *store.Stores, ds *Dataset) (*Result, error) {
+	if err := ds.Validate(); err != nil {
+		return nil, fmt.Errorf("fixtures: %w", err)
+	}
+	now := time.Now()
+	res := &Result{Inserted: map[string]int{}, Skipped: map[string]int{}}
+	save := func(collection, key string, err error) error {
+		switch {
+		case errors.Is(err, store.ErrDuplicate):
+			res.Skipped[collection]++
+			return nil
+		case err != nil:
+			return fmt.Errorf("fixtures: insert %s %q: %w", collection, key, err)
+		}
+		res.Inserted[collection]++
+		return nil
+	}
+
+	for _, c := range ds.Categories {
+		doc := models.Category{CategoryID: ID(c.Key), Name: c.Name, CreatedAt: now, UpdatedAt: now}
+		if err := save(store.CollectionCategories, c.Key, stores.Categories.Create(ctx, &doc)); err != nil {
+			return res, err
+		}
+	}
+	for _, f := range ds.Facilities {
+		doc := models.Facility{FacilityID: ID(f.Key), Name: f.Name, Type: f.Type, CreatedAt: now, UpdatedAt: now}
+		if err := save(store.CollectionFacilities, f.Key, stores.Facilities.Create(ctx, &doc)); err != nil {
+			return res, err
+		}
+	}
+
+	users := map[string]User{}
+	for _, u := range ds.Users {
+		users[u.Key] = u
+		doc := models.User{
+			UserID:            ID(u.Key),
+			FullName:          u.FullName,
+			Email:             u.Email,
+			PhoneNumber:       u.PhoneNumber,
+			Role:              u.Role,
+			Picture:           u.Picture,
+			VerifiedEmail:     u.VerifiedEmail,
+			VerificationToken: u.VerificationToken,
+			IsRoleAssigned:    true,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		}
+		if u.Password != "" {
+			// MinCost cukup untuk data development dan membuat test tetap cepat
+			hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.MinCost)
+			if err != nil {
+				return res, err
+			}
+			doc.Password = string(hash)
+		}
+		if err := save(store.CollectionUsers, u.Key, stores.Users.Create(ctx, &doc)); err != nil {
+			return res, err
+		}
+	}
+
+	customFacilities := map[string]CustomFacility{}
+	for _, cf := range ds.CustomFacilities {
+		customFacilities[cf.Key] = cf
+		doc := models.CustomFacility{
+			CustomFacilityID: ID(cf.Key),
+			Name:             cf.Name,
+			Price:            cf.Price,
+			OwnerID:          ID(cf.Owner),
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		if err := save(store.CollectionCustomFacilities, cf.Key, stores.CustomFacilities.Create(ctx, &doc)); err != nil {
+			return res, err
+		}
+	}
+
+	houses := map[string]BoardingHouse{}
+	for _, bh := range ds.BoardingHouses {
+		houses[bh.Key] = bh
+		doc := models.BoardingHouse{
+			BoardingHouseID: ID(bh.Key),
+			OwnerID:         ID(bh.Owner),
+			CategoryID:      ID(bh.Category),
+			Name:            bh.Name,
+			Slug:            slug(bh.Name, bh.Key),
+			Address:         bh.Address,
+			Description:     bh.Description,
+			Rules:           bh.Rules,
+			Facilities:      ids(bh.Facilities),
+			Images:          bh.Images,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		if err := save(store.CollectionBoardingHouses, bh.Key, stores.BoardingHouses.Create(ctx, &doc)); err != nil {
+			return res, err
+		}
+	}
+
+	rooms := map[string]Room{}
+	for _, r := range ds.Rooms {
+		rooms[r.Key] = r
+	}
+	transactions := make([]models.Transaction, 0, len(ds.Transactions))
+	for _, t := range ds.Transactions {
+		transactions = append(transactions, transaction(t, users[t.User], rooms[t.Room], houses[rooms[t.Room].BoardingHouse], customFacilities, now))
+	}
+
+	for _, r := range ds.Rooms {
+		var roomTransactions []models.Transaction
+		for _, t := range transactions {
+			if t.RoomID == ID(r.Key) {
+				roomTransactions = append(roomTransactions, t)
+			}
+		}
+		available := r.Capacity - store.CountActive(roomTransactions)
+		if available < 0 {
+			available = 0
+		}
+		doc := models.Room{
+			RoomID:          ID(r.Key),
+			BoardingHouseID: ID(r.BoardingHouse),
+			RoomType:        r.RoomType,
+			Size:            r.Size,
+			Price: models.RoomPrice{
+				Monthly:    r.Price.Monthly,
+				Quarterly:  r.Price.Quarterly,
+				SemiAnnual: r.Price.SemiAnnual,
+				Yearly:     r.Price.Yearly,
+			},
+			RoomFacilities:   ids(r.Facilities),
+			CustomFacilities: ids(r.CustomFacilities),
+			Status:           map[bool]string{true: "Tersedia", false: "Tidak Tersedia"}[available > 0],
+			NumberAvailable:  available,
+			Capacity:         r.Capacity,
+			Images:           r.Images,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		if err := save(store.CollectionRooms, r.Key, stores.Rooms.Create(ctx, &doc)); err != nil {
+			return res, err
+		}
+	}
+
+	for i := range transactions {
+		if err := save(store.CollectionTransactions, ds.Transactions[i].Key, stores.Transactions.Create(ctx, &transactions[i])); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// LoadDefault memasukkan dataset bawaan ke stores.
+func LoadDefault(ctx context.Context, stores *store.Stores) (*Result, error) {
+	ds, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return Load(ctx, stores, ds)
+}
+
+// transaction menghitung harga seperti CreateTransaction: harga kamar
+// sesuai payment term ditambah custom facility, lalu PPN 11%.
+func transaction(t Transaction, user User, room Room, house BoardingHouse, customFacilities map[string]CustomFacility, now time.Time) models.Transaction {
+	var facilities []models.CustomFacilityInfo
+	var facilitiesPrice float64
+	for _, key := range t.CustomFacilities {
+		cf := customFacilities[key]
+		facilities = append(facilities, models.CustomFacilityInfo{CustomFacilityID: ID(key), Name: cf.Name, Price: cf.Price})
+		facilitiesPrice += cf.Price
+	}
+	roomPrice := float64(priceFor(room.Price, t.PaymentTerm))
+	subtotal := roomPrice + facilitiesPrice
+	ppn := subtotal * 0.11
+	created := now.AddDate(0, 0, -t.CreatedDaysAgo)
+	y, m, d := now.AddDate(0, 0, t.CheckInDays).Date()
+
+	id := ID(t.Key)
+	return models.Transaction{
+		TransactionID:   id,
+		TransactionCode: fmt.Sprintf("KCT%s%s", created.Format("060102"), id.Hex()[18:]),
+		UserID:          ID(t.User),
+		OwnerID:         ID(house.Owner),
+		BoardingHouseID: ID(room.BoardingHouse),
+		RoomID:          ID(t.Room),
+		PersonalInfo: models.PersonalInfo{
+			FullName:    user.FullName,
+			Email:       user.Email,
+			PhoneNumber: user.PhoneNumber,
+		},
+		CustomFacilities: facilities,
+		PaymentTerm:      t.PaymentTerm,
+		CheckInDate:      time.Date(y, m, d, 0, 0, 0, 0, time.Local),
+		Price:            roomPrice,
+		FacilitiesPrice:  facilitiesPrice,
+		Subtotal:         subtotal,
+		PPN:              ppn,
+		Total:            subtotal + ppn,
+		PaymentStatus:    t.PaymentStatus,
+		PaymentMethod:    t.PaymentMethod,
+		CreatedAt:        created,
+		UpdatedAt:        created,
+	}
+}
+
+func priceFor(p Price, term string) int {
+	switch term {
+	case "monthly":
+		return p.Monthly
+	case "quarterly":
+		return p.Quarterly
+	case "semi_annual":
+		return p.SemiAnnual
+	case "yearly":
+		return p.Yearly
+	}
+	return 0
+}
+
+func ids(keys []string) []primitive.ObjectID {
+	out := make([]primitive.ObjectID, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, ID(key))
+	}
+	return out
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// slug mengikuti bentuk generateSlug di controllers, tetapi akhiran diambil
+// dari ID agar sama setiap kali seed dijalankan.
+func slug(name, key string) string {
+	s := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+	return nonSlug.ReplaceAllString(s, "") + "-" + ID(key).Hex()[16:]
+}
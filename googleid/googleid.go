@@ -0,0 +1,210 @@
+// Package googleid memverifikasi Google ID token (JWT RS256 dari Google
+// Identity Services) terhadap JSON Web Key Set milik Google.
+package googleid
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// CertsURL adalah JWKS Google untuk ID token.
+const CertsURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+// Nilai iss yang dipakai Google di ID token.
+var issuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+var (
+	// ErrInvalidToken dikembalikan untuk token yang palsu, kedaluwarsa,
+	// untuk client lain, atau emailnya belum diverifikasi Google.
+	ErrInvalidToken = errors.New("googleid: invalid id token")
+	// ErrUnknownKey dikembalikan KeySource jika kid tidak ada di key set.
+	ErrUnknownKey = errors.New("googleid: unknown key id")
+)
+
+// Claims adalah isi ID token yang sudah diverifikasi.
+type Claims struct {
+	Email         string `json:"email"`
+	EmailVerified flag   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+	jwt.RegisteredClaims
+}
+
+// flag menerima true dan "true"; token Google lama mengirim email_verified
+// sebagai string.
+type flag bool
+
+func (f *flag) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `true`, `"true"`:
+		*f = true
+	case `false`, `"false"`, `null`:
+		*f = false
+	default:
+		return fmt.Errorf("googleid: invalid boolean %s", data)
+	}
+	return nil
+}
+
+// KeySource mengembalikan public key untuk kid di header token.
+type KeySource interface {
+	Key(ctx context.Context, kid string) (*rsa.PublicKey, error)
+}
+
+// StaticKeys adalah KeySource tetap, misal untuk test dengan key lokal.
+type StaticKeys map[string]*rsa.PublicKey
+
+func (k StaticKeys) Key(_ context.Context, kid string) (*rsa.PublicKey, error) {
+	if key, ok := k[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// RemoteKeys mengambil JWKS dari URL dan menyimpannya selama ttl. Google
+// merotasi key secara berkala, jadi kid yang belum dikenal memicu fetch
+// ulang, paling sering sekali per minRefresh.
+type RemoteKeys struct {
+	url        string
+	client     *http.Client
+	ttl        time.Duration
+	minRefresh time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+// NewRemoteKeys membuat RemoteKeys untuk url, biasanya CertsURL.
+func NewRemoteKeys(url string) *RemoteKeys {
+	return &RemoteKeys{
+		url:        url,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		ttl:        time.Hour,
+		minRefresh: time.Minute,
+		now:        time.Now,
+	}
+}
+
+func (r *RemoteKeys) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	age := r.now().Sub(r.fetched)
+	key, ok := r.keys[kid]
+	if ok && age < r.ttl {
+		return key, nil
+	}
+	if r.keys == nil || age >= r.ttl || age >= r.minRefresh {
+		keys, err := r.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.keys, r.fetched = keys, r.now()
+		key, ok = keys[kid]
+	}
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// fetch mengunduh dan membaca JWKS. Key selain RSA diabaikan.
+func (r *RemoteKeys) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("googleid: fetch keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("googleid: fetch keys: %s", resp.Status)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("googleid: decode keys: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			return nil, fmt.Errorf("googleid: key %s is not valid base64url", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+// Verifier memeriksa tanda tangan, aud, iss, exp dan email_verified ID
+// token untuk satu OAuth client ID.
+type Verifier struct {
+	clientID string
+	keys     KeySource
+	now      func() time.Time
+}
+
+// NewVerifier membuat Verifier untuk token yang diterbitkan bagi clientID.
+func NewVerifier(clientID string, keys KeySource) *Verifier {
+	return &Verifier{clientID: clientID, keys: keys, now: time.Now}
+}
+
+// Verify mengembalikan claims dari token yang valid. Error yang
+// membungkus ErrInvalidToken berarti token ditolak; error lain berarti
+// verifikasi tidak bisa dilakukan, misal JWKS gagal diambil.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	if v.clientID == "" {
+		return nil, errors.New("googleid: client id is not configured")
+	}
+
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithAudience(v.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(v.now),
+	)
+	if err != nil {
+		// Gagal mengambil key bukan kesalahan token
+		if errors.Is(err, jwt.ErrTokenUnverifiable) && !errors.Is(err, ErrUnknownKey) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if !slices.Contains(issuers, claims.Issuer) {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	}
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, fmt.Errorf("%w: email is not verified by Google", ErrInvalidToken)
+	}
+	return &claims, nil
+}
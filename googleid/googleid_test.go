@@ -0,0 +1,154 @@
+package googleid
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func sign(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewVerifier("client-id", StaticKeys{"k1": &key.PublicKey})
+	ctx := context.Background()
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":            "https://accounts.google.com",
+			"aud":            "client-id",
+			"sub":            "1234567890",
+			"email":          "nadia@example.com",
+			"email_verified": true,
+			"name":           "Nadia",
+			"iat":            time.Now().Unix(),
+			"exp":            time.Now().Add(time.Hour).Unix(),
+		}
+	}
+	with := func(key string, value any) jwt.MapClaims {
+		claims := valid()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	claims, err := v.Verify(ctx, sign(t, key, "k1", valid()))
+	if err != nil {
+		t.Fatalf("valid token: %v", err)
+	}
+	if claims.Email != "nadia@example.com" || claims.Name != "Nadia" {
+		t.Errorf("claims = %+v", claims)
+	}
+	if _, err := v.Verify(ctx, sign(t, key, "k1", with("iss", "accounts.google.com"))); err != nil {
+		t.Errorf("issuer without https: %v", err)
+	}
+	if _, err := v.Verify(ctx, sign(t, key, "k1", with("email_verified", "true"))); err != nil {
+		t.Errorf("email_verified as string: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"other audience":     sign(t, key, "k1", with("aud", "someone-else")),
+		"other issuer":       sign(t, key, "k1", with("iss", "https://evil.example.com")),
+		"expired":            sign(t, key, "k1", with("exp", time.Now().Add(-time.Minute).Unix())),
+		"no expiry":          sign(t, key, "k1", with("exp", nil)),
+		"email not verified": sign(t, key, "k1", with("email_verified", false)),
+		"no email":           sign(t, key, "k1", with("email", nil)),
+		"wrong key":          sign(t, other, "k1", valid()),
+		"unknown kid":        sign(t, key, "k2", valid()),
+		"malformed":          "not.a.token",
+	} {
+		if _, err := v.Verify(ctx, token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidToken", name, err)
+		}
+	}
+
+	// HS256 dengan public key sebagai secret tidak boleh diterima
+	hs, err := jwt.NewWithClaims(jwt.SigningMethodHS256, valid()).SignedString(key.PublicKey.N.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Verify(ctx, hs); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("HS256 token: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestRemoteKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kids := []string{"k1"}
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		var set struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		for _, kid := range kids {
+			set.Keys = append(set.Keys, map[string]string{
+				"kid": kid,
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	keys := NewRemoteKeys(srv.URL)
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	got, err := keys.Key(ctx, "k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(&key.PublicKey) {
+		t.Error("key from JWKS does not match")
+	}
+	keys.Key(ctx, "k1")
+	if fetches != 1 {
+		t.Errorf("fetches = %d, want 1 while cached", fetches)
+	}
+
+	// Key baru dari rotasi: kid yang tidak dikenal memicu fetch ulang, dibatasi minRefresh
+	kids = append(kids, "k2")
+	if _, err := keys.Key(ctx, "k2"); !errors.Is(err, ErrUnknownKey) || fetches != 1 {
+		t.Errorf("unknown kid right after fetch: err = %v, fetches = %d", err, fetches)
+	}
+	now = now.Add(time.Minute)
+	if _, err := keys.Key(ctx, "k2"); err != nil || fetches != 2 {
+		t.Errorf("unknown kid after minRefresh: err = %v, fetches = %d", err, fetches)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/google/go-github/v68/github"
+	"github.com/organisasi/kosconnectbackend/metrics"
 	"golang.org/x/oauth2"
 )
 
@@ -30,7 +33,23 @@ func CalculateHash(data []byte) string {
 }
 
 // UploadFile uploads or updates a file in a GitHub repository.
+// The duration and outcome are recorded in kosconnect_github_upload_duration_seconds.
 func UploadFile(ctx context.Context, config GitHubConfig) (*github.RepositoryContentResponse, error) {
+	start := time.Now()
+	resp, err := uploadFile(ctx, config)
+
+	outcome := metrics.OutcomeSuccess
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		outcome = metrics.OutcomeTimeout
+	case err != nil:
+		outcome = metrics.OutcomeFailure
+	}
+	metrics.GitHubUploadDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+func uploadFile(ctx context.Context, config GitHubConfig) (*github.RepositoryContentResponse, error) {
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: config.AccessToken})
 	tc := oauth2.NewClient(ctx, ts)
 	client := github.NewClient(tc)
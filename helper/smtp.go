@@ -1,16 +1,53 @@
 package helper
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html"
+	"net"
 	"net/smtp"
-	"os"
 	"time"
+
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/metrics"
 	// "gopkg.in/gomail.v2"
 	// "fmt"
 )
 
-func SendVerificationEmail(email, verificationLink, fullName string) error {
+func SendVerificationEmail(ctx context.Context, cfg config.SMTPConfig, email, verificationLink, fullName string) error {
+	body := emailHTML(fullName,
+		`<p>Terima kasih telah mendaftar di KosConnect!</p>
+            <p>Untuk menyelesaikan pendaftaran Anda, silakan verifikasi alamat email Anda dengan mengklik tombol di bawah ini:</p>`,
+		verificationLink, "Verifikasi Email",
+		`<p>Jika Anda tidak meminta ini, silakan abaikan email ini.</p>`)
+
+	err := sendHTML(ctx, cfg, email, "Verifikasi Email Anda di KosConnect", body)
+	countEmail("verification", err)
+	return err
+}
+
+// SendPasswordResetEmail mengirim link dari /auth/forgot-password. validFor
+// ditampilkan di email sebagai masa berlaku link.
+func SendPasswordResetEmail(ctx context.Context, cfg config.SMTPConfig, email, resetLink, fullName string, validFor time.Duration) error {
+	body := emailHTML(fullName,
+		`<p>Kami menerima permintaan untuk mengatur ulang password akun KosConnect Anda.</p>
+            <p>Klik tombol di bawah ini untuk membuat password baru. Link ini berlaku selama `+fmt.Sprintf("%.0f", validFor.Minutes())+` menit dan hanya bisa dipakai sekali:</p>`,
+		resetLink, "Atur Ulang Password",
+		`<p>Jika Anda tidak meminta reset password, abaikan email ini. Password Anda tidak akan berubah.</p>`)
+
+	err := sendHTML(ctx, cfg, email, "Atur Ulang Password KosConnect", body)
+	countEmail("password_reset", err)
+	return err
+}
+
+// emailHTML membuat body email dengan header logo KosConnect, satu tombol
+// ke link, dan footer. intro dan outro adalah HTML yang ditulis di kode,
+// bukan input user.
+func emailHTML(fullName, intro, link, button, outro string) string {
 	// Template body email
-	body := `
+	return `
     <!DOCTYPE html>
     <html>
     <head>
@@ -64,13 +101,12 @@ func SendVerificationEmail(email, verificationLink, fullName string) error {
             <span class="title">KosConnect</span>
         </div>
         <div class="content">
-            <h2>Halo, ` + fullName + `</h2>
-            <p>Terima kasih telah mendaftar di KosConnect!</p>
-            <p>Untuk menyelesaikan pendaftaran Anda, silakan verifikasi alamat email Anda dengan mengklik tombol di bawah ini:</p>
-            <a href="` + verificationLink + `" class="button">
-                Verifikasi Email
+            <h2>Halo, ` + html.EscapeString(fullName) + `</h2>
+            ` + intro + `
+            <a href="` + link + `" class="button">
+                ` + button + `
             </a>
-            <p>Jika Anda tidak meminta ini, silakan abaikan email ini.</p>
+            ` + outro + `
             <p>Terima kasih,<br>Tim KosConnect</p>
         </div>
         <div class="footer">
@@ -79,17 +115,87 @@ func SendVerificationEmail(email, verificationLink, fullName string) error {
     </body>
     </html>
     `
-	// Mengakses variabel environment langsung dari Vercel
-	appPassword := os.Getenv("APP_PASSWORD")
-	// Konfigurasi SMTP
-	auth := smtp.PlainAuth("", "kosconnect2@gmail.com", appPassword, "smtp.gmail.com")
+}
+
+// sendHTML mengirim body HTML ke satu alamat email.
+func sendHTML(ctx context.Context, cfg config.SMTPConfig, email, subject, body string) error {
 	to := []string{email}
 
 	// Header email
-	subject := "Subject: Verifikasi Email Anda di KosConnect\r\n"
+	header := "Subject: " + subject + "\r\n"
 	contentType := "MIME-Version: 1.0\r\nContent-Type: text/html; charset=UTF-8\r\n"
-	msg := []byte(subject + contentType + "\r\n" + body)
+	msg := []byte(header + contentType + "\r\n" + body)
 
 	// Kirim email
-	return smtp.SendMail("smtp.gmail.com:587", auth, "kosconnect2@gmail.com", to, msg)
+	return sendMail(ctx, cfg, to, msg)
+}
+
+// countEmail mencatat hasil pengiriman email di kosconnect_emails_sent_total
+func countEmail(kind string, err error) {
+	outcome := metrics.OutcomeSuccess
+	if err != nil {
+		outcome = metrics.OutcomeFailure
+	}
+	metrics.EmailsSent.WithLabelValues(kind, outcome).Inc()
+}
+
+// sendMail sama seperti smtp.SendMail tetapi berhenti ketika ctx selesai.
+// Error karena deadline habis membungkus context.DeadlineExceeded.
+func sendMail(ctx context.Context, cfg config.SMTPConfig, to []string, msg []byte) error {
+	err := sendMailConn(ctx, cfg, to, msg)
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("smtp: %w: %v", ctx.Err(), err)
+	}
+	return err
+}
+
+func sendMailConn(ctx context.Context, cfg config.SMTPConfig, to []string, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", cfg.Addr())
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); !ok {
+		return errors.New("smtp: server doesn't support AUTH")
+	}
+	// Konfigurasi SMTP
+	if err := client.Auth(smtp.PlainAuth("", cfg.Sender, cfg.Password, cfg.Host)); err != nil {
+		return err
+	}
+
+	if err := client.Mail(cfg.Sender); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := client.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
 }
\ No newline at end of file
@@ -0,0 +1,35 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// RandomToken membuat token acak 32 byte yang aman dipakai di URL,
+// misal untuk verifikasi email.
+func RandomToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.URLEncoding.EncodeToString(b)
+}
+
+// HashToken mengembalikan SHA-256 (hex) dari token. Token acak disimpan di
+// database dalam bentuk ini agar tidak bisa dipakai jika database bocor.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerificationLink membuat link verifikasi email yang dikirim ke user.
+func VerificationLink(baseURL, token string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/auth/verify?token=" + token
+}
+
+// PasswordResetLink membuat link halaman reset password di frontend yang
+// dikirim lewat email. Frontend mengirim token ke POST /auth/reset-password.
+func PasswordResetLink(frontendURL, token string) string {
+	return strings.TrimSuffix(frontendURL, "/") + "/reset-password?token=" + token
+}
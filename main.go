@@ -1,66 +1,55 @@
-package handler //ganti ke main kalau mau di run local
+package handler
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 
 	"github.com/gin-gonic/gin"
 	// "github.com/joho/godotenv" //digunakan hanya jika akan di run secara local
 	"github.com/organisasi/kosconnectbackend/config"
-	"github.com/organisasi/kosconnectbackend/middlewares"
-	"github.com/organisasi/kosconnectbackend/routes"
+	"github.com/organisasi/kosconnectbackend/migrations"
+	"github.com/organisasi/kosconnectbackend/server"
+	"github.com/organisasi/kosconnectbackend/store"
 )
 
+// router dibangun sekali saat cold start lalu dipakai ulang di setiap request
+var router *gin.Engine
+
 func init() {
 	// Load environment variables digunakan hanya jika akan di run secara local
 	// if err := godotenv.Load(); err != nil {
 	// 	log.Println("No .env file found")
 	// }
-	// Inisialisasi konfigurasi Midtrans
-	config.InitMidtransConfig()
-
-	// Connect to MongoDB
-	config.ConnectDB()
-}
-
-// Handler for deployment - Menerima request dan menangani routing dengan CORS
-func Handler(w http.ResponseWriter, r *http.Request) {
-	// Set up Gin router di dalam handler
-	router := gin.Default()
-
-	// Menyajikan file statis dari folder "public"
-	router.Static("/images", "./public/images")
-
-	// Apply CORS Middleware
-	router.Use(middlewares.CORSMiddleware())
+	// Load dan validasi konfigurasi, gagal di awal jika ada nilai wajib yang kosong
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(server.NewLogger(os.Stdout, cfg.LogLevel))
 
-	// Register routes setelah router diinisialisasi
-	routes.AuthRoutes(router)
-	routes.UserRoutes(router)
-	routes.CustomFacility(router)
-	routes.CategoryRoutes(router)
-	routes.BoardingHouse(router)
-	routes.Facility(router)
-	routes.RoomRoutes(router)
-	// Tambahkan di file main.go
-	routes.TransactionRoutes(router)
+	// Inisialisasi konfigurasi Midtrans
+	config.InitMidtransConfig(cfg.Midtrans)
 
-	// Handle HTTP request
-	router.ServeHTTP(w, r)
-}
+	// Connect to MongoDB. Di Vercel client dibiarkan terbuka selama instance hidup
+	if _, err := config.ConnectDB(cfg.Mongo); err != nil {
+		log.Fatal(err)
+	}
 
-func main() {
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Migrasi yang sudah tercatat dilewati, jadi cold start berikutnya hanya membaca koleksi migrations
+	if cfg.Mongo.MigrateOnStartup {
+		if _, err := migrations.Run(context.Background(), config.DB); err != nil {
+			log.Fatal(err)
+		}
 	}
-	log.Printf("Server is running on port %s\n", port)
 
-	// Gunakan handler untuk menangani request HTTP
-	http.HandleFunc("/", Handler) // Memetakan path "/" ke Handler
+	router = server.NewServer(cfg, store.NewMongo(config.DB, cfg.Timeouts.Database))
+}
 
-	// Mulai server
-	log.Fatal(http.ListenAndServe(":"+port, nil)) // Tidak perlu http.HandlerFunc(Handler) di sini
+// Handler for deployment (Vercel). Untuk menjalankan secara local gunakan
+// `go run ./cmd/server`.
+func Handler(w http.ResponseWriter, r *http.Request) {
+	router.ServeHTTP(w, r)
 }
@@ -0,0 +1,85 @@
+// Package metrics mendefinisikan metric Prometheus KosConnect. Semua metric
+// didaftarkan ke registry default dan diekspos oleh Handler di /metrics.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "kosconnect"
+
+// Outcome dipakai sebagai label hasil operasi keluar
+const (
+	OutcomeSuccess         = "success"
+	OutcomeFailure         = "failure"
+	OutcomeTimeout         = "timeout"
+	OutcomeInvalidResponse = "invalid_response"
+)
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Jumlah request HTTP per method, route dan status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency request HTTP per method, route dan status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	MongoOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongo_operation_duration_seconds",
+		Help:      "Durasi operasi MongoDB per koleksi dan operasi.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"collection", "operation"})
+
+	MidtransRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "midtrans_requests_total",
+		Help:      "Hasil panggilan Midtrans Snap dari CreatePayment.",
+	}, []string{"outcome"})
+
+	GitHubUploadDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "github_upload_duration_seconds",
+		Help:      "Durasi upload gambar ke GitHub per hasil.",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"outcome"})
+
+	EmailsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "emails_sent_total",
+		Help:      "Email yang dikirim per jenis dan hasil.",
+	}, []string{"kind", "outcome"})
+
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Pembacaan cache agregasi per view dan hasil (hit atau miss).",
+	}, []string{"view", "result"})
+
+	CachePurges = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_purges_total",
+		Help:      "Berapa kali cache agregasi dikosongkan karena data berubah.",
+	})
+)
+
+// ObserveMongo mencatat durasi satu operasi MongoDB sejak start
+func ObserveMongo(collection, operation string, start time.Time) {
+	MongoOperationDuration.WithLabelValues(collection, operation).Observe(time.Since(start).Seconds())
+}
+
+// Handler mengekspos semua metric dalam format Prometheus
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
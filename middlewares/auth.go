@@ -8,26 +8,30 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Secret key (gunakan dari env)
-var jwtSecret = []byte("your_secret_key")
+// JWTAuthMiddleware memverifikasi token Bearer dengan secret dari config,
+// lalu memastikan session di klaim sid belum logout, dicabut atau
+// kedaluwarsa. Token tanpa sid (dibuat sebelum ada session) ditolak.
+func JWTAuthMiddleware(cfg *config.Config, sessions store.SessionStore) gin.HandlerFunc {
+	jwtSecret := []byte(cfg.JWTSecret)
 
-func JWTAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Ambil token dari header Authorization
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			c.Abort()
+			response.Abort(c, http.StatusUnauthorized, response.CodeUnauthorized, "Authorization header is required")
 			return
 		}
 
 		// Token format: "Bearer <token>"
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
-			c.Abort()
+			response.Abort(c, http.StatusUnauthorized, response.CodeUnauthorized, "Invalid token format")
 			return
 		}
 
@@ -36,11 +40,29 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 		// Parse dan verifikasi token
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 			return jwtSecret, nil
-		})
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			response.Abort(c, http.StatusUnauthorized, response.CodeInvalidToken, "Invalid or expired token")
+			return
+		}
+
+		// Cek session di database agar token yang sudah logout tidak bisa dipakai lagi
+		claims := token.Claims.(jwt.MapClaims)
+		sid, _ := claims["sid"].(string)
+		sessionID, err := primitive.ObjectIDFromHex(sid)
+		if err != nil {
+			response.Abort(c, http.StatusUnauthorized, response.CodeInvalidToken, "Invalid or expired token")
+			return
+		}
+		session, err := sessions.FindByID(c.Request.Context(), sessionID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			Logger(c).Error("failed to load session", "error", err)
+			response.Abort(c, http.StatusInternalServerError, response.CodeInternal, "Failed to verify session")
+			return
+		}
+		if session == nil || !session.Active(time.Now()) || session.UserID.Hex() != claims["user_id"] {
+			response.Abort(c, http.StatusUnauthorized, response.CodeSessionRevoked, "Session has been revoked, please log in again")
 			return
 		}
 
@@ -51,14 +73,14 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 }
 
 // Fungsi untuk memvalidasi token JWT dan mengembalikan klaim jika valid
-func ValidateToken(tokenString string) (jwt.MapClaims, error) {
+func ValidateToken(cfg *config.Config, tokenString string) (jwt.MapClaims, error) {
 	// Parse token dan verifikasi
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Pastikan token menggunakan algoritma yang benar
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
-		return jwtSecret, nil
+		return []byte(cfg.JWTSecret), nil
 	})
 
 	if err != nil {
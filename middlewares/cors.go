@@ -7,17 +7,10 @@ import (
 )
 
 // CORSMiddleware sets up the Cross-Origin Resource Sharing (CORS) headers
-func CORSMiddleware() gin.HandlerFunc {
+// Daftar origin yang diizinkan diambil dari config (allowed_origins)
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
-		allowedOrigins := []string{
-			"https://kosconnect.github.io",
-			"http://localhost:8080",               // Testing dengan localhost
-			"https://accounts.google.com",        // Google OAuth origin
-			"https://kosconnect-server.vercel.app", // Backend utama
-			"http://127.0.0.1:5504", //go live fe
-			"http://127.0.0.1:5500", //alternative go live fe
-		}
 
 		allowed := false
 
@@ -34,9 +27,11 @@ func CORSMiddleware() gin.HandlerFunc {
 
 		// Tambahkan header CORS lainnya
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-None-Match, "+IdempotencyKeyHeader+", "+RequestIDHeader)
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Authorization, Retry-After, ETag, "+IdempotentReplayedHeader+", "+RequestIDHeader)
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		// Allow-Origin berbeda per origin, jadi cache publik harus memisahkannya
+		c.Writer.Header().Add("Vary", "Origin")
 
 		// Tangani metode OPTIONS
 		if c.Request.Method == http.MethodOptions {
@@ -0,0 +1,90 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VersionFunc mengembalikan versi data di balik sebuah endpoint, misal
+// store.Stores.Version untuk koleksi yang dibaca handler-nya.
+type VersionFunc func(ctx context.Context) (string, error)
+
+// ETag menambahkan weak ETag dan Cache-Control pada response GET publik
+// agar browser dan edge cache (Vercel) bisa merevalidasi dengan
+// If-None-Match. ETag dihitung dari version dan URL request sebelum
+// handler berjalan, jadi request yang ETag-nya masih cocok dibalas 304
+// tanpa menjalankan agregasi sama sekali.
+//
+// Versi dibaca sebelum handler: jika data berubah di antaranya, response
+// baru berlabel ETag lama dan request berikutnya mendapat data lengkap
+// lagi. Urutan sebaliknya bisa membuat client menyimpan data lama dengan
+// ETag baru. Jika version gagal, request diproses tanpa ETag.
+//
+// cacheControl kosong berarti "no-cache": boleh disimpan tetapi selalu
+// direvalidasi. Response selain 2xx dikirim dengan Cache-Control: no-store.
+func ETag(cacheControl string, version VersionFunc) gin.HandlerFunc {
+	if cacheControl == "" {
+		cacheControl = "no-cache"
+	}
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		v, err := version(c.Request.Context())
+		if err != nil {
+			Logger(c).Warn("failed to compute etag", "error", err)
+			c.Next()
+			return
+		}
+		etag := `W/"` + hash(v, c.Request.URL.RequestURI())[:32] + `"`
+
+		header := c.Writer.Header()
+		header.Set("ETag", etag)
+		header.Set("Cache-Control", cacheControl)
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			c.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+
+		c.Writer = &cacheHeaderWriter{ResponseWriter: c.Writer}
+		c.Next()
+	}
+}
+
+// etagMatches membandingkan If-None-Match dengan etag secara weak
+// (RFC 9110 13.1.2): prefix W/ diabaikan di kedua sisi.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheHeaderWriter membuang ETag dan Cache-Control yang sudah diset
+// jika handler membalas dengan error, agar 404 atau 500 tidak ikut
+// disimpan cache publik.
+type cacheHeaderWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *cacheHeaderWriter) WriteHeader(status int) {
+	if status < 200 || status >= 300 {
+		w.Header().Del("ETag")
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
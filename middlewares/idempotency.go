@@ -0,0 +1,162 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
+)
+
+// IdempotencyKeyHeader adalah header dari client, biasanya UUID baru per checkout.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader diset "true" pada response yang diputar ulang.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// Idempotency menyimpan response pertama untuk setiap Idempotency-Key dan
+// memutarnya ulang untuk request dengan key yang sama selama ttl, tanpa
+// menjalankan handler lagi. Key berlaku per path dan per user_id (pasang
+// setelah JWTAuthMiddleware jika route butuh login).
+//
+//   - Request tanpa header diproses seperti biasa.
+//   - Key sama dengan method, path, query atau body berbeda: 409 IDEMPOTENCY_KEY_REUSED.
+//   - Key sama saat request pertama belum selesai: 409 IDEMPOTENCY_IN_PROGRESS.
+//   - Response 5xx tidak disimpan agar client bisa mencoba lagi dengan key yang sama.
+func Idempotency(keys store.IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			response.Abort(c, http.StatusBadRequest, response.CodeValidationFailed, IdempotencyKeyHeader+" must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.Abort(c, http.StatusBadRequest, response.CodeValidationFailed, "Failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		now := time.Now()
+		record := &models.IdempotencyKey{
+			ID:          hash(c.Request.URL.Path, userIDFromClaims(c), key),
+			RequestHash: hash(c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery, string(body)),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		existing, err := reserve(ctx, keys, record, now)
+		if err != nil {
+			Logger(c).Error("idempotency store failed", "error", err)
+			response.Abort(c, http.StatusInternalServerError, response.CodeInternal, "Internal server error")
+			return
+		}
+		if existing != nil {
+			replay(c, existing, record.RequestHash)
+			return
+		}
+
+		// Jika handler panic atau gagal, hapus reservasi agar key bisa dipakai lagi
+		completed := false
+		defer func() {
+			if !completed {
+				if err := keys.Delete(context.WithoutCancel(ctx), record.ID); err != nil && !errors.Is(err, store.ErrNotFound) {
+					Logger(c).Warn("failed to release idempotency key", "error", err)
+				}
+			}
+		}()
+
+		writer := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		err = keys.Complete(context.WithoutCancel(ctx), record.ID, status, c.Writer.Header().Get("Content-Type"), writer.body.Bytes())
+		if err != nil {
+			Logger(c).Warn("failed to store idempotent response", "error", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// reserve menyimpan record baru. Jika key sudah dipakai dan belum
+// kedaluwarsa, record lama dikembalikan.
+func reserve(ctx context.Context, keys store.IdempotencyStore, record *models.IdempotencyKey, now time.Time) (*models.IdempotencyKey, error) {
+	err := keys.Reserve(ctx, record)
+	if !errors.Is(err, store.ErrDuplicate) {
+		return nil, err
+	}
+	existing, err := keys.Find(ctx, record.ID)
+	if errors.Is(err, store.ErrNotFound) {
+		// Baru saja dihapus (selesai dengan 5xx atau kedaluwarsa), coba sekali lagi
+		return nil, keys.Reserve(ctx, record)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if now.Before(existing.ExpiresAt) {
+		return existing, nil
+	}
+	// TTL index MongoDB (migrasi 4) berjalan berkala, jadi record kedaluwarsa bisa masih ada
+	if err := keys.Delete(ctx, record.ID); err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, err
+	}
+	return nil, keys.Reserve(ctx, record)
+}
+
+func replay(c *gin.Context, existing *models.IdempotencyKey, requestHash string) {
+	switch {
+	case existing.RequestHash != requestHash:
+		response.Abort(c, http.StatusConflict, response.CodeIdempotencyKeyReused, IdempotencyKeyHeader+" was already used for a different request")
+	case !existing.Completed:
+		c.Header("Retry-After", "1")
+		response.Abort(c, http.StatusConflict, response.CodeIdempotencyInProgress, "A request with this "+IdempotencyKeyHeader+" is still being processed")
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(existing.Status, existing.ContentType, existing.Body)
+		c.Abort()
+	}
+}
+
+func hash(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bodyRecorder meneruskan response ke client sambil menyimpan salinannya.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
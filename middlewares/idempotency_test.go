@@ -0,0 +1,108 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/store"
+)
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var (
+		mu      sync.Mutex
+		calls   = map[string]int{}
+		status  = http.StatusCreated
+		entered = make(chan struct{})
+		release chan struct{}
+	)
+	handler := func(c *gin.Context) {
+		mu.Lock()
+		calls[c.FullPath()]++
+		n, code, wait := calls[c.FullPath()], status, release
+		mu.Unlock()
+		if wait != nil {
+			entered <- struct{}{}
+			<-wait
+		}
+		c.JSON(code, gin.H{"call": n})
+	}
+	router := gin.New()
+	router.Use(Idempotency(store.NewMemory().Idempotency, time.Hour))
+	router.POST("/a", handler)
+	router.POST("/b", handler)
+
+	send := func(path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	expect := func(rec *httptest.ResponseRecorder, code int, body string) {
+		t.Helper()
+		if rec.Code != code || !strings.Contains(rec.Body.String(), body) {
+			t.Errorf("got %d %s, want %d containing %s", rec.Code, rec.Body, code, body)
+		}
+	}
+
+	// Replay: response pertama diputar ulang tanpa menjalankan handler lagi
+	expect(send("/a", "k1", `{"x":1}`), http.StatusCreated, `{"call":1}`)
+	replayed := send("/a", "k1", `{"x":1}`)
+	expect(replayed, http.StatusCreated, `{"call":1}`)
+	if replayed.Header().Get(IdempotentReplayedHeader) != "true" || replayed.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("replay headers = %v", replayed.Header())
+	}
+	if calls["/a"] != 1 {
+		t.Errorf("handler ran %d times for one key", calls["/a"])
+	}
+
+	// Key sama dengan body berbeda ditolak; di route lain key berdiri sendiri
+	expect(send("/a", "k1", `{"x":2}`), http.StatusConflict, string(response.CodeIdempotencyKeyReused))
+	expect(send("/b", "k1", `{"x":1}`), http.StatusCreated, `{"call":1}`)
+
+	// Tanpa header setiap request diproses
+	expect(send("/a", "", `{"x":1}`), http.StatusCreated, `{"call":2}`)
+
+	// Request kedua selagi yang pertama masih berjalan
+	mu.Lock()
+	release = make(chan struct{})
+	mu.Unlock()
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- send("/a", "k2", `{}`) }()
+	<-entered
+	mu.Lock()
+	wait := release
+	release = nil
+	mu.Unlock()
+	inProgress := send("/a", "k2", `{}`)
+	expect(inProgress, http.StatusConflict, string(response.CodeIdempotencyInProgress))
+	if inProgress.Header().Get("Retry-After") != "1" {
+		t.Errorf("in-progress Retry-After = %q", inProgress.Header().Get("Retry-After"))
+	}
+	close(wait)
+	expect(<-first, http.StatusCreated, `{"call":3}`)
+	expect(send("/a", "k2", `{}`), http.StatusCreated, `{"call":3}`)
+
+	// 5xx tidak disimpan: key yang sama bisa dicoba lagi
+	status = http.StatusBadGateway
+	expect(send("/a", "k3", `{}`), http.StatusBadGateway, `{"call":4}`)
+	status = http.StatusCreated
+	expect(send("/a", "k3", `{}`), http.StatusCreated, `{"call":5}`)
+	expect(send("/a", "k3", `{}`), http.StatusCreated, `{"call":5}`)
+
+	// Key terlalu panjang ditolak sebelum handler
+	expect(send("/a", strings.Repeat("k", 256), `{}`), http.StatusBadRequest, string(response.CodeValidationFailed))
+	if calls["/a"] != 5 {
+		t.Errorf("handler ran %d times, want 5", calls["/a"])
+	}
+}
@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader dipakai untuk menerima dan mengembalikan ID request
+const RequestIDHeader = "X-Request-ID"
+
+const loggerKey = "logger"
+
+// RequestLogger memberi setiap request sebuah X-Request-ID (memakai ulang
+// header dari client jika ada), menyimpan logger dengan request_id di
+// context gin, lalu menulis satu baris log setelah request selesai.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		reqLogger := logger.With("request_id", requestID)
+		c.Set(loggerKey, reqLogger)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", c.FullPath(), // Template route, kosong jika tidak ada route yang cocok
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip", c.ClientIP(),
+		}
+		if userID := userIDFromClaims(c); userID != "" {
+			attrs = append(attrs, "user_id", userID)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		reqLogger.Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// Logger mengembalikan logger milik request, atau slog.Default jika
+// RequestLogger tidak dipasang.
+func Logger(c *gin.Context) *slog.Logger {
+	if logger, ok := c.Get(loggerKey); ok {
+		if l, ok := logger.(*slog.Logger); ok {
+			return l
+		}
+	}
+	return slog.Default()
+}
+
+// userIDFromClaims membaca user_id dari klaim yang diset JWTAuthMiddleware
+func userIDFromClaims(c *gin.Context) string {
+	value, ok := c.Get("user")
+	if !ok {
+		return ""
+	}
+	claims, ok := value.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	userID, _ := claims["user_id"].(string)
+	return userID
+}
@@ -0,0 +1,114 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	router := gin.New()
+	router.Use(RequestLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	router.GET("/items/:id", func(c *gin.Context) {
+		Logger(c).Info("inside handler")
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fail", func(c *gin.Context) {
+		c.Set("user", jwt.MapClaims{"user_id": "user-1"})
+		c.Error(errors.New("boom"))
+		c.Status(http.StatusInternalServerError)
+	})
+
+	// send menjalankan request lalu mengembalikan response dan baris log JSON-nya
+	send := func(path, requestID string) (*httptest.ResponseRecorder, []map[string]any) {
+		t.Helper()
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		if requestID != "" {
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var lines []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var entry map[string]any
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("log line %q: %v", line, err)
+			}
+			lines = append(lines, entry)
+		}
+		return rec, lines
+	}
+
+	// Tanpa header: ID baru dibuat dan dipakai di response, log handler dan log request
+	rec, lines := send("/items/42", "")
+	id := rec.Header().Get(RequestIDHeader)
+	if len(id) != 36 {
+		t.Fatalf("generated %s = %q, want a UUID", RequestIDHeader, id)
+	}
+	if len(lines) != 2 || lines[0]["msg"] != "inside handler" || lines[1]["msg"] != "request" {
+		t.Fatalf("log lines = %v, want the handler line then the request line", lines)
+	}
+	for _, line := range lines {
+		if line["request_id"] != id {
+			t.Errorf("%s: request_id = %v, want %s", line["msg"], line["request_id"], id)
+		}
+	}
+	request := lines[1]
+	for key, want := range map[string]any{
+		"level":     "INFO",
+		"method":    "GET",
+		"route":     "/items/:id",
+		"path":      "/items/42",
+		"status":    float64(200),
+		"client_ip": "192.0.2.1",
+	} {
+		if request[key] != want {
+			t.Errorf("%s = %v, want %v", key, request[key], want)
+		}
+	}
+	if _, ok := request["latency_ms"].(float64); !ok {
+		t.Errorf("latency_ms = %v, want a number", request["latency_ms"])
+	}
+	if _, ok := request["user_id"]; ok {
+		t.Errorf("anonymous request logged user_id %v", request["user_id"])
+	}
+
+	// ID dari client dipakai ulang, kecuali terlalu panjang
+	if rec, lines := send("/items/1", "trace-123"); rec.Header().Get(RequestIDHeader) != "trace-123" || lines[1]["request_id"] != "trace-123" {
+		t.Errorf("incoming request ID not reused: header %q, log %v", rec.Header().Get(RequestIDHeader), lines[1]["request_id"])
+	}
+	long := strings.Repeat("x", 129)
+	if rec, _ := send("/items/1", long); rec.Header().Get(RequestIDHeader) == long {
+		t.Error("request ID longer than 128 characters was reused")
+	}
+
+	// Level mengikuti status; user_id dan error handler ikut dicatat
+	_, lines = send("/fail", "")
+	if fail := lines[0]; fail["level"] != "ERROR" || fail["user_id"] != "user-1" || !strings.Contains(fmt.Sprint(fail["errors"]), "boom") {
+		t.Errorf("5xx log line = %v", fail)
+	}
+	_, lines = send("/missing", "")
+	if missing := lines[0]; missing["level"] != "WARN" || missing["route"] != "" || missing["status"] != float64(404) {
+		t.Errorf("404 log line = %v", missing)
+	}
+}
+
+func TestLoggerWithoutMiddleware(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	if Logger(c) != slog.Default() {
+		t.Error("Logger without RequestLogger is not slog.Default()")
+	}
+}
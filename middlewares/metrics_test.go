@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/organisasi/kosconnectbackend/metrics"
+)
+
+// scrape membaca nilai metric dari /metrics, per nama metric beserta label
+// persis seperti di output Prometheus.
+func scrape(t *testing.T) map[string]float64 {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	values := map[string]float64{}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndexByte(line, ' ')
+		if value, err := strconv.ParseFloat(line[i+1:], 64); err == nil {
+			values[line[:i]] = value
+		}
+	}
+	return values
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/metrics-test/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/metrics-test/:id", func(c *gin.Context) { c.Status(http.StatusConflict) })
+
+	// Registry Prometheus global, jadi yang dibandingkan selisihnya
+	before := scrape(t)
+	for _, req := range []struct{ method, path string }{
+		{http.MethodGet, "/metrics-test/1"},
+		{http.MethodGet, "/metrics-test/2"},
+		{http.MethodPost, "/metrics-test/1"},
+		{http.MethodGet, "/metrics-test-unknown/abc"},
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.path, nil))
+	}
+	after := scrape(t)
+
+	// Label route memakai template, bukan path, agar jumlah series terbatas
+	for labels, want := range map[string]float64{
+		`{method="GET",route="/metrics-test/:id",status="200"}`:  2,
+		`{method="POST",route="/metrics-test/:id",status="409"}`: 1,
+		`{method="GET",route="unmatched",status="404"}`:          1,
+	} {
+		for _, name := range []string{"kosconnect_http_requests_total", "kosconnect_http_request_duration_seconds_count"} {
+			if got := after[name+labels] - before[name+labels]; got != want {
+				t.Errorf("%s%s increased by %v, want %v", name, labels, got, want)
+			}
+		}
+		bucket := `kosconnect_http_request_duration_seconds_bucket` + strings.TrimSuffix(labels, "}") + `,le="+Inf"}`
+		if got := after[bucket] - before[bucket]; got != want {
+			t.Errorf("%s increased by %v, want %v", bucket, got, want)
+		}
+	}
+	for key := range after {
+		if strings.Contains(key, "/metrics-test/1") || strings.Contains(key, "abc") {
+			t.Errorf("metric labelled with a raw path: %s", key)
+		}
+	}
+}
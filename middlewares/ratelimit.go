@@ -0,0 +1,130 @@
+package middlewares
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/response"
+)
+
+// RateLimitStore menyimpan isi token bucket per key. Implementasi bawaan
+// MemoryRateLimitStore hanya berlaku untuk satu instance; untuk beberapa
+// instance (misal Vercel) pasang store bersama seperti Redis.
+type RateLimitStore interface {
+	// Take mengambil satu token dari bucket key. Jika bucket kosong, ok false
+	// dan retryAfter berisi waktu sampai token berikutnya tersedia.
+	Take(ctx context.Context, key string, limit config.RateLimit, now time.Time) (ok bool, retryAfter time.Duration, err error)
+}
+
+// RateLimiter membuat middleware rate limit yang berbagi satu store.
+type RateLimiter struct {
+	enabled bool
+	store   RateLimitStore
+	now     func() time.Time
+}
+
+// NewRateLimiter membuat RateLimiter. Jika cfg.Enabled false, middleware
+// yang dibuat tidak membatasi apa pun.
+func NewRateLimiter(cfg config.RateLimitConfig, store RateLimitStore) *RateLimiter {
+	return &RateLimiter{enabled: cfg.Enabled, store: store, now: time.Now}
+}
+
+// Limit membatasi request di grup route dengan token bucket. Key bucket
+// adalah nama grup ditambah user_id dari JWT (pasang setelah
+// JWTAuthMiddleware) atau IP client. Request yang ditolak dibalas 429
+// RATE_LIMITED dengan header Retry-After dalam detik.
+func (rl *RateLimiter) Limit(group string, limit config.RateLimit) gin.HandlerFunc {
+	if !rl.enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		key := group + ":ip:" + c.ClientIP()
+		if userID := userIDFromClaims(c); userID != "" {
+			key = group + ":user:" + userID
+		}
+
+		ok, retryAfter, err := rl.store.Take(c.Request.Context(), key, limit, rl.now())
+		if err != nil {
+			// Store bermasalah tidak boleh membuat login atau booking ikut gagal
+			Logger(c).Warn("rate limit store failed, allowing request", "group", group, "error", err)
+			c.Next()
+			return
+		}
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			response.Abort(c, http.StatusTooManyRequests, response.CodeRateLimited, "Too many requests, please try again later")
+			return
+		}
+		c.Next()
+	}
+}
+
+// MemoryRateLimitStore menyimpan bucket di memori proses.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // Waktu bucket terisi penuh lagi; setelahnya bucket boleh dihapus
+}
+
+// NewMemoryRateLimitStore membuat store in-memory.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: map[string]*bucket{}}
+}
+
+// sweepInterval adalah jarak minimal antar pembersihan bucket yang sudah penuh.
+const sweepInterval = time.Minute
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit config.RateLimit, now time.Time) (bool, time.Duration, error) {
+	burst := float64(limit.Burst)
+	if limit.Burst == 0 {
+		burst = float64(limit.Requests)
+	}
+	perToken := limit.Per / time.Duration(limit.Requests) // Waktu pengisian satu token
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		s.buckets[key] = b
+	}
+	// Isi ulang sesuai waktu yang berlalu sejak request terakhir
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+float64(elapsed)/float64(perToken))
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(perToken)), nil
+	}
+	b.tokens--
+	b.full = now.Add(time.Duration((burst - b.tokens) * float64(perToken)))
+	return true, 0, nil
+}
+
+// sweep menghapus bucket yang sudah terisi penuh; bucket baru akan sama
+// isinya. Pemanggil harus memegang lock.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
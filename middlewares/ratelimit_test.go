@@ -0,0 +1,184 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/organisasi/kosconnectbackend/config"
+)
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRateLimitStore()
+	limit := config.RateLimit{Requests: 6, Per: time.Minute, Burst: 3} // Satu token per 10 detik
+	now := time.Now()
+	take := func(key string) (bool, time.Duration) {
+		t.Helper()
+		ok, retryAfter, err := store.Take(ctx, key, limit, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok, retryAfter
+	}
+
+	// Burst: tiga request langsung lolos, yang keempat ditolak
+	for i := 0; i < 3; i++ {
+		if ok, _ := take("a"); !ok {
+			t.Fatalf("request %d within burst was rejected", i+1)
+		}
+	}
+	if ok, retryAfter := take("a"); ok || retryAfter != 10*time.Second {
+		t.Fatalf("request after burst: ok %v, retryAfter %s; want rejected for 10s", ok, retryAfter)
+	}
+
+	// Key lain punya bucket sendiri
+	if ok, _ := take("b"); !ok {
+		t.Error("other key shares the bucket")
+	}
+
+	// Refill: satu token setelah 10 detik, bukan dua
+	now = now.Add(4 * time.Second)
+	if ok, retryAfter := take("a"); ok || retryAfter != 6*time.Second {
+		t.Errorf("after 4s: ok %v, retryAfter %s; want rejected for 6s", ok, retryAfter)
+	}
+	now = now.Add(6 * time.Second)
+	if ok, _ := take("a"); !ok {
+		t.Error("no token after refill")
+	}
+	if ok, _ := take("a"); ok {
+		t.Error("refill gave more than one token")
+	}
+
+	// Refill tidak melebihi burst meskipun lama tidak dipakai
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if ok, _ := take("a"); !ok {
+			t.Fatalf("request %d after idle was rejected", i+1)
+		}
+	}
+	if ok, _ := take("a"); ok {
+		t.Error("bucket refilled beyond burst")
+	}
+
+	// Burst 0 berarti sama dengan Requests
+	limit = config.RateLimit{Requests: 2, Per: time.Minute}
+	for i := 0; i < 2; i++ {
+		if ok, _ := take("c"); !ok {
+			t.Fatalf("request %d within default burst was rejected", i+1)
+		}
+	}
+	if ok, _ := take("c"); ok {
+		t.Error("default burst is larger than requests")
+	}
+}
+
+// fakeRateLimitStore mencatat key yang diminta dan menolak jika bucket key
+// sudah habis.
+type fakeRateLimitStore struct {
+	taken map[string]int
+	max   int
+}
+
+func (s *fakeRateLimitStore) Take(_ context.Context, key string, _ config.RateLimit, _ time.Time) (bool, time.Duration, error) {
+	s.taken[key]++
+	return s.taken[key] <= s.max, time.Second, nil
+}
+
+func TestRateLimitKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &fakeRateLimitStore{taken: map[string]int{}, max: 1}
+	limiter := NewRateLimiter(config.RateLimitConfig{Enabled: true}, store)
+
+	router := gin.New()
+	router.SetTrustedProxies(nil)
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Set("user", jwt.MapClaims{"user_id": userID})
+		}
+	})
+	router.POST("/", limiter.Limit("auth", config.RateLimit{}), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	send := func(remoteAddr, userID string, want int) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.RemoteAddr = remoteAddr
+		if userID != "" {
+			req.Header.Set("X-Test-User", userID)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("%s user %q = %d, want %d", remoteAddr, userID, rec.Code, want)
+		}
+		return rec
+	}
+
+	send("203.0.113.1:1000", "", http.StatusNoContent)
+	if rec := send("203.0.113.1:2000", "", http.StatusTooManyRequests); rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("Retry-After = %q, want 1", rec.Header().Get("Retry-After"))
+	}
+	send("203.0.113.2:1000", "", http.StatusNoContent)
+
+	// Request dengan JWT dibatasi per user, tidak ikut bucket IP-nya
+	send("203.0.113.1:1000", "u1", http.StatusNoContent)
+	send("203.0.113.3:1000", "u1", http.StatusTooManyRequests)
+	send("203.0.113.1:1000", "u2", http.StatusNoContent)
+
+	for _, key := range []string{"auth:ip:203.0.113.1", "auth:ip:203.0.113.2", "auth:user:u1", "auth:user:u2"} {
+		if store.taken[key] == 0 {
+			t.Errorf("bucket %q never used; buckets = %v", key, store.taken)
+		}
+	}
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, tc := range []struct {
+		name    string
+		trusted []string
+		want    string
+	}{
+		{"no trusted proxy", nil, "auth:ip:203.0.113.1"},
+		{"request not from the proxy", []string{"10.0.0.1"}, "auth:ip:203.0.113.1"},
+	} {
+		store := &fakeRateLimitStore{taken: map[string]int{}, max: 2}
+		limiter := NewRateLimiter(config.RateLimitConfig{Enabled: true}, store)
+		router := gin.New()
+		if err := router.SetTrustedProxies(tc.trusted); err != nil {
+			t.Fatal(err)
+		}
+		router.POST("/", limiter.Limit("auth", config.RateLimit{}), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+		codes := []int{}
+		for _, spoofed := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.RemoteAddr = "203.0.113.1:1000"
+			req.Header.Set("X-Forwarded-For", spoofed)
+			req.Header.Set("X-Real-IP", spoofed)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			codes = append(codes, rec.Code)
+		}
+		if codes[2] != http.StatusTooManyRequests || store.taken[tc.want] != 3 {
+			t.Errorf("%s: codes %v, buckets %v; want all requests in %s", tc.name, codes, store.taken, tc.want)
+		}
+	}
+
+	// Dari proxy yang dipercaya, IP client diambil dari X-Forwarded-For
+	store := &fakeRateLimitStore{taken: map[string]int{}, max: 2}
+	router := gin.New()
+	router.SetTrustedProxies([]string{"10.0.0.1"})
+	router.POST("/", NewRateLimiter(config.RateLimitConfig{Enabled: true}, store).Limit("auth", config.RateLimit{}), func(c *gin.Context) {})
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if store.taken["auth:ip:198.51.100.1"] != 1 {
+		t.Errorf("trusted proxy: buckets %v, want auth:ip:198.51.100.1", store.taken)
+	}
+}
@@ -0,0 +1,220 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All adalah daftar migrasi aplikasi, urut berdasarkan versi.
+var All = []Migration{
+	{
+		Version:     1,
+		Description: "unique indexes on users.email, transactions.transaction_code and boardinghouses.slug",
+		Up:          uniqueIndexes,
+	},
+	{
+		Version:     2,
+		Description: "lookup indexes for users, rooms, boarding houses, transactions and custom facilities",
+		Up:          lookupIndexes,
+	},
+	{
+		Version:     3,
+		Description: "backfill missing created_at and updated_at",
+		Up:          backfillTimestamps,
+	},
+	{
+		Version:     4,
+		Description: "TTL index on idempotency_keys.expires_at",
+		Up:          idempotencyTTL,
+	},
+	{
+		Version:     5,
+		Description: "updated_at indexes for ETags on public endpoints",
+		Up:          updatedAtIndexes,
+	},
+	{
+		Version:     6,
+		Description: "sessions indexes: TTL on expires_at and user_id for logout from all devices",
+		Up:          sessionIndexes,
+	},
+	{
+		Version:     7,
+		Description: "password_resets indexes: TTL on expires_at and unique token_hash",
+		Up:          passwordResetIndexes,
+	},
+	{
+		Version:     8,
+		Description: "TTL index on oauth_states.expires_at",
+		Up:          oauthStateTTL,
+	},
+}
+
+// hasString membatasi index ke dokumen yang field-nya terisi. Model memakai
+// omitempty, jadi dokumen lama bisa tidak punya field tersebut sama sekali.
+func hasString(field string) *options.IndexOptions {
+	return options.Index().SetPartialFilterExpression(bson.M{field: bson.M{"$type": "string"}})
+}
+
+func uniqueIndexes(ctx context.Context, db *mongo.Database) error {
+	if err := createIndexes(ctx, db, store.CollectionUsers, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetName("email_unique").SetUnique(true),
+	}); err != nil {
+		return err
+	}
+	if err := createIndexes(ctx, db, store.CollectionTransactions, mongo.IndexModel{
+		Keys:    bson.D{{Key: "transaction_code", Value: 1}},
+		Options: hasString("transaction_code").SetName("transaction_code_unique").SetUnique(true),
+	}); err != nil {
+		return err
+	}
+	return createIndexes(ctx, db, store.CollectionBoardingHouses, mongo.IndexModel{
+		Keys:    bson.D{{Key: "slug", Value: 1}},
+		Options: hasString("slug").SetName("slug_unique").SetUnique(true),
+	})
+}
+
+func lookupIndexes(ctx context.Context, db *mongo.Database) error {
+	for _, idx := range []struct {
+		collection string
+		models     []mongo.IndexModel
+	}{
+		{store.CollectionUsers, []mongo.IndexModel{
+			// Dipakai VerifyEmail
+			{Keys: bson.D{{Key: "verification_token", Value: 1}}, Options: hasString("verification_token").SetName("verification_token")},
+			{Keys: bson.D{{Key: "role", Value: 1}}, Options: options.Index().SetName("role")},
+		}},
+		{store.CollectionBoardingHouses, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "owner_id", Value: 1}}, Options: options.Index().SetName("owner_id")},
+		}},
+		{store.CollectionRooms, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "boarding_house_id", Value: 1}}, Options: options.Index().SetName("boarding_house_id")},
+		}},
+		{store.CollectionTransactions, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("user_id_created_at")},
+			{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("owner_id_created_at")},
+			{Keys: bson.D{{Key: "payment_status", Value: 1}}, Options: options.Index().SetName("payment_status")},
+		}},
+		{store.CollectionCustomFacilities, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "owner_id", Value: 1}}, Options: options.Index().SetName("owner_id")},
+		}},
+	} {
+		if err := createIndexes(ctx, db, idx.collection, idx.models...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillTimestamps mengisi created_at yang tidak ada atau null dengan
+// waktu dari _id (ObjectID menyimpan waktu pembuatan), lalu updated_at yang
+// kosong dengan created_at. Butuh MongoDB 4.2+ untuk update berbentuk pipeline.
+//
+// Migrasi ini hanya berjalan sekali untuk data lama. Dokumen yang dibuat
+// setelahnya tidak ikut diisi, jadi setiap handler dan store yang membuat
+// dokumen tetap wajib mengisi created_at dan updated_at sendiri; sort,
+// cursor dan ETag bergantung pada keduanya.
+func backfillTimestamps(ctx context.Context, db *mongo.Database) error {
+	for _, name := range []string{
+		store.CollectionUsers,
+		store.CollectionBoardingHouses,
+		store.CollectionRooms,
+		store.CollectionTransactions,
+		store.CollectionFacilities,
+		store.CollectionCategories,
+		store.CollectionCustomFacilities,
+	} {
+		coll := db.Collection(name)
+		if _, err := coll.UpdateMany(ctx,
+			bson.M{"created_at": nil},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"created_at": bson.M{"$toDate": "$_id"}}}}},
+		); err != nil {
+			return fmt.Errorf("%s: backfill created_at: %w", name, err)
+		}
+		if _, err := coll.UpdateMany(ctx,
+			bson.M{"updated_at": nil},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"updated_at": "$created_at"}}}},
+		); err != nil {
+			return fmt.Errorf("%s: backfill updated_at: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// idempotencyTTL membuat MongoDB menghapus record Idempotency-Key setelah
+// expires_at lewat.
+func idempotencyTTL(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db, store.CollectionIdempotencyKeys, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+	})
+}
+
+// updatedAtIndexes mempercepat Stores.Version yang mencari updated_at
+// terbaru di koleksi yang dibaca endpoint publik ber-ETag.
+func updatedAtIndexes(ctx context.Context, db *mongo.Database) error {
+	for _, name := range []string{
+		store.CollectionUsers,
+		store.CollectionBoardingHouses,
+		store.CollectionRooms,
+		store.CollectionFacilities,
+		store.CollectionCategories,
+		store.CollectionCustomFacilities,
+	} {
+		if err := createIndexes(ctx, db, name, mongo.IndexModel{
+			Keys:    bson.D{{Key: "updated_at", Value: -1}},
+			Options: options.Index().SetName("updated_at"),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sessionIndexes menghapus session setelah refresh token kedaluwarsa dan
+// mempercepat RevokeAll per user.
+func sessionIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db, store.CollectionSessions,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_id"),
+		},
+	)
+}
+
+// passwordResetIndexes menghapus token reset setelah kedaluwarsa dan
+// mempercepat pencarian token dari link email.
+func passwordResetIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db, store.CollectionPasswordResets,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetName("token_hash_unique").SetUnique(true),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_id"),
+		},
+	)
+}
+
+// oauthStateTTL menghapus catatan state OAuth setelah cookie state-nya
+// kedaluwarsa.
+func oauthStateTTL(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db, store.CollectionOAuthStates, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+	})
+}
@@ -0,0 +1,184 @@
+// Package migrations membuat index dan menjalankan migrasi data MongoDB
+// secara berurutan. Versi yang sudah dijalankan dicatat di koleksi
+// "migrations" sehingga setiap migrasi hanya dijalankan sekali.
+//
+// Migrasi baru ditambahkan di akhir All dengan Version lebih besar dari
+// yang terakhir; migrasi yang sudah dirilis tidak boleh diubah. Setiap Up
+// harus aman dijalankan ulang, karena dua instance (mis. cold start Vercel)
+// bisa menjalankan migrasi yang sama bersamaan sebelum tercatat.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection adalah koleksi tempat versi migrasi yang sudah dijalankan dicatat.
+const Collection = "migrations"
+
+// Migration adalah satu langkah perubahan skema atau data.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// Record adalah dokumen di koleksi migrations.
+type Record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+	DurationMS  int64     `bson:"duration_ms"`
+}
+
+// Status adalah keadaan satu migrasi terhadap database.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Applied mengembalikan migrasi yang sudah tercatat, per versi.
+func Applied(ctx context.Context, db *mongo.Database) (map[int]Record, error) {
+	return mongoRecords{db}.applied(ctx)
+}
+
+// records menyimpan versi yang sudah dijalankan. Implementasinya
+// mongoRecords; test memakai versi di memori.
+type records interface {
+	applied(ctx context.Context) (map[int]Record, error)
+	record(ctx context.Context, r Record) error
+}
+
+// mongoRecords menyimpan Record di koleksi migrations.
+type mongoRecords struct {
+	db *mongo.Database
+}
+
+func (m mongoRecords) applied(ctx context.Context) (map[int]Record, error) {
+	cursor, err := m.db.Collection(Collection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("migrations: read applied versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("migrations: read applied versions: %w", err)
+	}
+	applied := make(map[int]Record, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// record memakai upsert agar instance lain yang menjalankan migrasi yang
+// sama tidak membuat error duplikat.
+func (m mongoRecords) record(ctx context.Context, r Record) error {
+	_, err := m.db.Collection(Collection).ReplaceOne(ctx, bson.M{"_id": r.Version}, r, options.Replace().SetUpsert(true))
+	return err
+}
+
+// Statuses mengembalikan semua migrasi di list beserta status-nya.
+func Statuses(ctx context.Context, db *mongo.Database, list []Migration) ([]Status, error) {
+	applied, err := Applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(list))
+	for _, m := range sorted(list) {
+		r, ok := applied[m.Version]
+		statuses = append(statuses, Status{Migration: m, Applied: ok, AppliedAt: r.AppliedAt})
+	}
+	return statuses, nil
+}
+
+// Up menjalankan migrasi di list yang belum tercatat, urut dari versi
+// terkecil, dan mengembalikan versi yang baru dijalankan. Berhenti di
+// migrasi pertama yang gagal; migrasi sebelumnya tetap tercatat.
+func Up(ctx context.Context, db *mongo.Database, list []Migration) ([]int, error) {
+	return up(ctx, db, mongoRecords{db}, list)
+}
+
+func up(ctx context.Context, db *mongo.Database, recs records, list []Migration) ([]int, error) {
+	if err := validate(list); err != nil {
+		return nil, err
+	}
+	applied, err := recs.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []int
+	for _, m := range sorted(list) {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		start := time.Now()
+		slog.Info("running migration", "version", m.Version, "description", m.Description)
+		if err := m.Up(ctx, db); err != nil {
+			return ran, fmt.Errorf("migrations: version %d (%s): %w", m.Version, m.Description, err)
+		}
+		record := Record{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now(),
+			DurationMS:  time.Since(start).Milliseconds(),
+		}
+		if err := recs.record(ctx, record); err != nil {
+			return ran, fmt.Errorf("migrations: record version %d: %w", m.Version, err)
+		}
+		slog.Info("migration applied", "version", m.Version, "duration_ms", record.DurationMS)
+		ran = append(ran, m.Version)
+	}
+	return ran, nil
+}
+
+// Run menjalankan semua migrasi di All yang belum tercatat.
+func Run(ctx context.Context, db *mongo.Database) ([]int, error) {
+	return Up(ctx, db, All)
+}
+
+func sorted(list []Migration) []Migration {
+	out := append([]Migration(nil), list...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
+
+func validate(list []Migration) error {
+	seen := map[int]bool{}
+	for _, m := range list {
+		if m.Version <= 0 {
+			return fmt.Errorf("migrations: version must be positive, got %d", m.Version)
+		}
+		if seen[m.Version] {
+			return fmt.Errorf("migrations: duplicate version %d", m.Version)
+		}
+		if m.Up == nil {
+			return fmt.Errorf("migrations: version %d has no Up", m.Version)
+		}
+		seen[m.Version] = true
+	}
+	return nil
+}
+
+// createIndexes membuat index di koleksi. Index yang sudah ada dengan
+// definisi sama dilewati oleh MongoDB, jadi aman dijalankan ulang.
+func createIndexes(ctx context.Context, db *mongo.Database, collection string, models ...mongo.IndexModel) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(ctx, models)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%s: existing documents violate a unique index, remove the duplicates first: %w", collection, err)
+		}
+		return fmt.Errorf("%s: %w", collection, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// memRecords adalah records di memori, pengganti koleksi migrations.
+type memRecords map[int]Record
+
+func (m memRecords) applied(context.Context) (map[int]Record, error) {
+	out := make(map[int]Record, len(m))
+	for v, r := range m {
+		out[v] = r
+	}
+	return out, nil
+}
+
+func (m memRecords) record(_ context.Context, r Record) error {
+	m[r.Version] = r
+	return nil
+}
+
+func TestUp(t *testing.T) {
+	ctx := context.Background()
+	var ran []int
+	step := func(version int, err error) Migration {
+		return Migration{Version: version, Description: fmt.Sprint("step ", version), Up: func(context.Context, *mongo.Database) error {
+			ran = append(ran, version)
+			return err
+		}}
+	}
+
+	// Urutan di list tidak menentukan: selalu dari versi terkecil
+	recs := memRecords{}
+	list := []Migration{step(3, nil), step(1, nil), step(2, nil)}
+	got, err := up(ctx, nil, recs, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != "[1 2 3]" || fmt.Sprint(ran) != "[1 2 3]" {
+		t.Errorf("first run: returned %v, ran %v; want [1 2 3]", got, ran)
+	}
+	for _, v := range []int{1, 2, 3} {
+		if r, ok := recs[v]; !ok || r.Description != fmt.Sprint("step ", v) || r.AppliedAt.IsZero() {
+			t.Errorf("version %d recorded as %+v", v, r)
+		}
+	}
+
+	// Dijalankan ulang: tidak ada yang berjalan lagi
+	ran = nil
+	got, err = up(ctx, nil, recs, list)
+	if err != nil || len(got) != 0 || len(ran) != 0 {
+		t.Errorf("second run: returned %v, ran %v, err %v; want nothing", got, ran, err)
+	}
+
+	// Migrasi baru saja yang berjalan; berhenti di yang gagal, sebelumnya tetap tercatat
+	ran = nil
+	failed := errors.New("boom")
+	list = append(list, step(5, failed), step(4, nil), step(6, nil))
+	got, err = up(ctx, nil, recs, list)
+	if !errors.Is(err, failed) {
+		t.Errorf("err = %v, want the failure from version 5", err)
+	}
+	if fmt.Sprint(got) != "[4]" || fmt.Sprint(ran) != "[4 5]" {
+		t.Errorf("failing run: returned %v, ran %v; want [4] and [4 5]", got, ran)
+	}
+	if _, ok := recs[5]; ok {
+		t.Error("failed version 5 was recorded")
+	}
+	if _, ok := recs[6]; ok {
+		t.Error("version 6 ran after a failure")
+	}
+
+	for name, list := range map[string][]Migration{
+		"duplicate version": {step(1, nil), step(1, nil)},
+		"zero version":      {step(0, nil)},
+		"missing Up":        {{Version: 1}},
+	} {
+		ran = nil
+		if _, err := up(ctx, nil, memRecords{}, list); err == nil || len(ran) != 0 {
+			t.Errorf("%s: err %v, ran %v; want an error before running anything", name, err, ran)
+		}
+	}
+}
+
+// TestAll memastikan daftar migrasi yang dirilis valid dan versinya urut
+// tanpa celah, agar migrasi baru tidak salah diberi nomor.
+func TestAll(t *testing.T) {
+	if err := validate(All); err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range All {
+		if m.Version != i+1 {
+			t.Errorf("All[%d] has version %d, want %d", i, m.Version, i+1)
+		}
+	}
+}
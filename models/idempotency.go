@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// IdempotencyKey menyimpan response pertama untuk header Idempotency-Key,
+// agar request yang diulang (misal double-click checkout) mendapat response
+// yang sama tanpa menjalankan handler lagi.
+type IdempotencyKey struct {
+	ID          string    `bson:"_id"`          // Hash dari scope dan key
+	RequestHash string    `bson:"request_hash"` // Hash method, path, query dan body
+	Completed   bool      `bson:"completed"`    // false selama request pertama masih diproses
+	Status      int       `bson:"status,omitempty"`
+	ContentType string    `bson:"content_type,omitempty"`
+	Body        []byte    `bson:"body,omitempty"`
+	CreatedAt   time.Time `bson:"created_at"`
+	ExpiresAt   time.Time `bson:"expires_at"` // Dihapus TTL index MongoDB setelah waktu ini
+}
@@ -18,7 +18,19 @@ type User struct {
 	CreatedAt         time.Time          `bson:"created_at,omitempty" json:"created_at,omitempty"`         // Waktu pembuatan
 	UpdatedAt         time.Time          `bson:"updated_at,omitempty" json:"updated_at,omitempty"`         // Waktu pembaruan
 	IsRoleAssigned    bool               `bson:"is_role_assigned" json:"is_role_assigned"`
-	VerificationToken string             `bson:"verification_token,omitempty" json:"verification_token,omitempty"` // Token verifikasi
+	VerificationToken string             `bson:"verification_token,omitempty" json:"verification_token,omitempty"` // Token verifikasi, tetap disimpan setelah dipakai
+
+	VerificationExpiresAt *time.Time `bson:"verification_expires_at,omitempty" json:"verification_expires_at,omitempty"`
+	VerificationSentAt    *time.Time `bson:"verification_sent_at,omitempty" json:"verification_sent_at,omitempty"` // Untuk membatasi resend
+}
+
+// VerificationExpired memeriksa apakah link verifikasi sudah kedaluwarsa.
+// Token lama tanpa verification_expires_at berlaku ttl sejak akun dibuat.
+func (u *User) VerificationExpired(now time.Time, ttl time.Duration) bool {
+	if u.VerificationExpiresAt != nil {
+		return !now.Before(*u.VerificationExpiresAt)
+	}
+	return !now.Before(u.CreatedAt.Add(ttl))
 }
 
 type Category struct {
@@ -74,6 +86,7 @@ type Room struct {
 	CustomFacilities []primitive.ObjectID `bson:"custom_facilities,omitempty" json:"custom_facilities,omitempty"`
 	Status           string               `bson:"status,omitempty" json:"status,omitempty"`
 	NumberAvailable  int                  `bson:"number_available,omitempty" json:"number_available,omitempty"`
+	Capacity         int                  `bson:"capacity,omitempty" json:"capacity,omitempty"`     // Jumlah kamar termasuk yang sedang disewa, dasar kosctl recompute-availability
 	Images           []string             `bson:"images,omitempty" json:"images,omitempty"`         // Array of image URLs
 	CreatedAt        time.Time            `bson:"created_at,omitempty" json:"created_at,omitempty"` // Waktu pembuatan
 	UpdatedAt        time.Time            `bson:"updated_at,omitempty" json:"updated_at,omitempty"` // Waktu pembaruan
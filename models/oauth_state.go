@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// OAuthState mencatat state OAuth Google yang sudah dipakai di
+// /auth/callback, agar callback yang sama tidak bisa diputar ulang.
+type OAuthState struct {
+	ID        string    `bson:"_id"` // Hash SHA-256 dari state
+	UsedAt    time.Time `bson:"used_at"`
+	ExpiresAt time.Time `bson:"expires_at"` // Dihapus TTL index setelah cookie state kedaluwarsa
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PasswordReset adalah permintaan reset password dari /auth/forgot-password.
+// Token di link email hanya disimpan sebagai hash SHA-256 dan hanya bisa
+// dipakai sekali.
+type PasswordReset struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	TokenHash string             `bson:"token_hash"`
+	IP        string             `bson:"ip,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+	ExpiresAt time.Time          `bson:"expires_at"`        // Dihapus TTL index setelahnya
+	UsedAt    *time.Time         `bson:"used_at,omitempty"` // Diisi saat dipakai atau diganti permintaan baru
+}
+
+// Usable memeriksa apakah token belum dipakai dan belum kedaluwarsa.
+func (r *PasswordReset) Usable(now time.Time) bool {
+	return r.UsedAt == nil && now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session adalah satu login (per perangkat). Access token JWT membawa _id
+// session di klaim "sid"; refresh token hanya disimpan sebagai hash
+// SHA-256 dan diganti setiap kali dipakai.
+type Session struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	UserID      primitive.ObjectID `bson:"user_id"`
+	RefreshHash string             `bson:"refresh_hash"`
+	UserAgent   string             `bson:"user_agent,omitempty"`
+	IP          string             `bson:"ip,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	RefreshedAt time.Time          `bson:"refreshed_at"`
+	ExpiresAt   time.Time          `bson:"expires_at"`           // Refresh token kedaluwarsa; dihapus TTL index setelahnya
+	RevokedAt   *time.Time         `bson:"revoked_at,omitempty"` // Diisi saat logout atau refresh token lama dipakai ulang
+}
+
+// Active memeriksa apakah session belum dicabut dan belum kedaluwarsa.
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
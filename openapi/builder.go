@@ -0,0 +1,297 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/organisasi/kosconnectbackend/query"
+	"github.com/organisasi/kosconnectbackend/validation"
+)
+
+const bearerAuth = "bearerAuth"
+
+// builder mengumpulkan operasi ke dalam Document.
+type builder struct {
+	doc *Document
+	reg *schemaRegistry
+}
+
+func newBuilder(info Info) *builder {
+	reg := newSchemaRegistry()
+	// Envelope dari package response
+	reg.schemas["Meta"] = object(nil, map[string]*Schema{
+		"message":     str("Pesan untuk ditampilkan ke pengguna"),
+		"total":       {Type: "integer", Description: "Endpoint list: jumlah data yang cocok dengan filter"},
+		"page":        {Type: "integer", Description: "Endpoint list: halaman saat ini, tidak ada jika memakai cursor"},
+		"limit":       {Type: "integer", Description: "Endpoint list: ukuran halaman"},
+		"next_cursor": str("Endpoint list: kirim sebagai ?cursor= untuk halaman berikutnya, tidak ada di halaman terakhir"),
+	})
+	reg.schemas["Error"] = object([]string{"code", "message"}, map[string]*Schema{
+		"code":    str("Kode error yang stabil, misal VALIDATION_FAILED atau ROOM_NOT_FOUND"),
+		"message": str("Pesan error untuk manusia, bisa berubah"),
+		"details": {Description: "Detail tambahan. Untuk VALIDATION_FAILED berisi {\"fields\": [FieldError]}"},
+	})
+	reg.ref(validation.FieldError{})
+	reg.schemas["ErrorResponse"] = object([]string{"data", "error"}, map[string]*Schema{
+		"data":  {Nullable: true, Description: "Selalu null"},
+		"error": ref("Error"),
+	})
+	return &builder{
+		doc: &Document{
+			OpenAPI: "3.0.3",
+			Info:    info,
+			Paths:   map[string]*PathItem{},
+			Components: Components{
+				Schemas: reg.schemas,
+				SecuritySchemes: map[string]*SecurityScheme{
+					bearerAuth: {
+						Type:         "http",
+						Scheme:       "bearer",
+						BearerFormat: "JWT",
+						Description:  "Token dari /auth/login atau /auth/googleauth, dikirim sebagai `Authorization: Bearer <token>`.",
+					},
+				},
+			},
+		},
+		reg: reg,
+	}
+}
+
+// group membuat tag baru; semua operasi dari group memakai tag tersebut.
+func (b *builder) group(name, description string) *group {
+	b.doc.Tags = append(b.doc.Tags, Tag{Name: name, Description: description})
+	return &group{b: b, tag: name}
+}
+
+type group struct {
+	b   *builder
+	tag string
+}
+
+// op mendaftarkan operasi. path memakai template OpenAPI ("/api/rooms/{id}");
+// parameter path otomatis ditambahkan, ID dianggap ObjectID hex.
+func (g *group) op(method, path, operationID, summary string) *operation {
+	op := &Operation{
+		Tags:        []string{g.tag},
+		Summary:     summary,
+		OperationID: operationID,
+		Responses:   map[string]*Response{},
+	}
+	for _, s := range strings.Split(path, "/") {
+		if !strings.HasPrefix(s, "{") {
+			continue
+		}
+		name := strings.Trim(s, "{}")
+		schema := str("")
+		if name == "id" || strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "ID") {
+			schema = objectID("")
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+
+	item, ok := g.b.doc.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		g.b.doc.Paths[path] = item
+	}
+	if item.Operation(method) != nil {
+		panic("openapi: duplicate operation " + method + " " + path)
+	}
+	item.set(method, op)
+	return &operation{op: op, reg: g.b.reg}
+}
+
+type operation struct {
+	op  *Operation
+	reg *schemaRegistry
+}
+
+func (o *operation) describe(description string) *operation {
+	o.op.Description = description
+	return o
+}
+
+// secured menandai operasi yang dilindungi JWTAuthMiddleware.
+func (o *operation) secured() *operation {
+	o.op.Security = []map[string][]string{{bearerAuth: {}}}
+	return o.fails(http.StatusUnauthorized)
+}
+
+// pathParam mengganti deskripsi atau schema parameter path yang dibuat otomatis.
+func (o *operation) pathParam(name, description string, schema *Schema) *operation {
+	for i := range o.op.Parameters {
+		if o.op.Parameters[i].Name == name && o.op.Parameters[i].In == "path" {
+			o.op.Parameters[i].Description = description
+			if schema != nil {
+				o.op.Parameters[i].Schema = schema
+			}
+		}
+	}
+	return o
+}
+
+func (o *operation) query(name, description string, required bool, schema *Schema) *operation {
+	o.op.Parameters = append(o.op.Parameters, Parameter{Name: name, In: "query", Description: description, Required: required, Schema: schema})
+	return o
+}
+
+// jsonBody menerima struct model (dibangkitkan lewat reflection) atau *Schema.
+func (o *operation) jsonBody(body any) *operation {
+	o.op.RequestBody = &RequestBody{
+		Required: true,
+		Content:  map[string]*MediaType{"application/json": {Schema: o.schema(body)}},
+	}
+	return o
+}
+
+// formBody untuk handler yang membaca c.PostForm dan file upload.
+func (o *operation) formBody(schema *Schema) *operation {
+	o.op.RequestBody = &RequestBody{
+		Required: true,
+		Content:  map[string]*MediaType{"multipart/form-data": {Schema: schema}},
+	}
+	return o
+}
+
+// returns menambahkan response sukses dengan data dibungkus envelope
+// {"data": ..., "error": null, "meta": ...}. data berupa struct model atau *Schema.
+func (o *operation) returns(status int, description string, data any) *operation {
+	return o.raw(status, description, envelope(o.schema(data)))
+}
+
+// message adalah response sukses tanpa data, pesannya ada di meta.message.
+func (o *operation) message(status int, description string) *operation {
+	return o.raw(status, description, envelope(&Schema{Nullable: true}))
+}
+
+// raw menambahkan response apa adanya, untuk endpoint di luar envelope
+// (metric, dokumentasi, file statis). body nil berarti tanpa content JSON.
+func (o *operation) raw(status int, description string, body *Schema) *operation {
+	resp := &Response{Description: description}
+	if body != nil {
+		resp.Content = map[string]*MediaType{"application/json": {Schema: body}}
+	}
+	o.op.Responses[strconv.Itoa(status)] = resp
+	return o
+}
+
+func envelope(data *Schema) *Schema {
+	return object([]string{"data", "error"}, map[string]*Schema{
+		"data":  data,
+		"error": {Nullable: true, Description: "Selalu null untuk response sukses"},
+		"meta":  ref("Meta"),
+	})
+}
+
+// redirect adalah response redirect ke frontend.
+func (o *operation) redirect(status int, description string) *operation {
+	o.op.Responses[strconv.Itoa(status)] = &Response{
+		Description: description,
+		Headers:     map[string]*Header{"Location": {Schema: &Schema{Type: "string", Format: "uri"}}},
+	}
+	return o
+}
+
+// fails menambahkan response error {"data": null, "error": {...}} untuk status yang diberikan.
+func (o *operation) fails(statuses ...int) *operation {
+	for _, status := range statuses {
+		o.op.Responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status),
+			Content:     map[string]*MediaType{"application/json": {Schema: ref("ErrorResponse")}},
+		}
+	}
+	return o
+}
+
+// rateLimited menambahkan response 429 RATE_LIMITED dengan header Retry-After.
+func (o *operation) rateLimited() *operation {
+	o.op.Responses[strconv.Itoa(http.StatusTooManyRequests)] = &Response{
+		Description: "Terlalu banyak request (rate_limit di config)",
+		Headers: map[string]*Header{"Retry-After": {
+			Description: "Detik sampai request boleh dicoba lagi",
+			Schema:      &Schema{Type: "integer"},
+		}},
+		Content: map[string]*MediaType{"application/json": {Schema: ref("ErrorResponse")}},
+	}
+	return o
+}
+
+// idempotent menambahkan header Idempotency-Key dan response 409 untuk key
+// yang dipakai ulang dengan request berbeda atau masih diproses.
+func (o *operation) idempotent() *operation {
+	o.op.Parameters = append(o.op.Parameters, Parameter{
+		Name:        "Idempotency-Key",
+		In:          "header",
+		Description: "Key unik per percobaan (misal UUID). Request ulang dengan key dan body yang sama mendapat response pertama dengan header Idempotent-Replayed: true",
+		Schema:      &Schema{Type: "string"},
+	})
+	o.op.Responses[strconv.Itoa(http.StatusConflict)] = &Response{
+		Description: "IDEMPOTENCY_KEY_REUSED untuk body berbeda, IDEMPOTENCY_IN_PROGRESS jika request pertama belum selesai",
+		Content:     map[string]*MediaType{"application/json": {Schema: ref("ErrorResponse")}},
+	}
+	return o
+}
+
+// cached menambahkan If-None-Match, header ETag dan Cache-Control pada
+// response 200, serta response 304. Panggil setelah returns.
+func (o *operation) cached() *operation {
+	o.op.Parameters = append(o.op.Parameters, Parameter{
+		Name:        "If-None-Match",
+		In:          "header",
+		Description: "ETag dari response sebelumnya; 304 tanpa body jika data belum berubah",
+		Schema:      &Schema{Type: "string"},
+	})
+	headers := map[string]*Header{
+		"ETag":          {Description: "Weak ETag dari updated_at data yang dibaca endpoint", Schema: &Schema{Type: "string"}},
+		"Cache-Control": {Description: "Dari http_cache di config", Schema: &Schema{Type: "string"}},
+	}
+	if resp := o.op.Responses[strconv.Itoa(http.StatusOK)]; resp != nil {
+		resp.Headers = headers
+	}
+	o.op.Responses[strconv.Itoa(http.StatusNotModified)] = &Response{
+		Description: "Data belum berubah sejak ETag di If-None-Match",
+		Headers:     headers,
+	}
+	return o
+}
+
+// paginated menambahkan parameter page, limit, cursor, sort dan filter
+// dari spec (lihat dto/query.go) serta response 400 untuk nilai yang salah.
+func (o *operation) paginated(spec query.Spec) *operation {
+	var sorts []string
+	for name := range spec.Sort {
+		sorts = append(sorts, name, "-"+name)
+	}
+	sort.Strings(sorts)
+	o.query("page", "Halaman, mulai dari 1", false, &Schema{Type: "integer", Example: 1})
+	o.query("limit", "Ukuran halaman (pagination.default_limit dan max_limit di config)", false, &Schema{Type: "integer", Example: 20})
+	o.query("cursor", "next_cursor dari response sebelumnya, pengganti page", false, str(""))
+	o.query("sort", "Urutan, awalan - untuk menurun. Default "+spec.DefaultSort, false, &Schema{Type: "string", Enum: sorts})
+
+	filters := make([]string, 0, len(spec.Filters))
+	for param := range spec.Filters {
+		filters = append(filters, param)
+	}
+	sort.Strings(filters)
+	for _, param := range filters {
+		schema := str("")
+		if spec.Filters[param].Kind == query.ObjectID {
+			schema = objectID("")
+		}
+		o.query(param, "Filter: "+spec.Filters[param].Field+" sama dengan nilai ini", false, schema)
+	}
+	return o.fails(http.StatusBadRequest)
+}
+
+func (o *operation) schema(v any) *Schema {
+	if s, ok := v.(*Schema); ok {
+		return s
+	}
+	return o.reg.ref(v)
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
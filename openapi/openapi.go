@@ -0,0 +1,229 @@
+// Package openapi membangun dokumen OpenAPI 3 untuk semua route KosConnect
+// dan menyajikannya di /openapi.json beserta Swagger UI di /docs.
+//
+// Schema model dibangkitkan dari struct di package models (lihat schema.go),
+// sedangkan daftar operasi ada di paths.go. Setiap route baru di package
+// routes wajib ditambahkan ke paths.go, test di package ini akan gagal jika
+// ada route gin yang belum terdokumentasi.
+package openapi
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Document adalah root dokumen OpenAPI 3.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem menampung operasi per HTTP method untuk satu path.
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Head   *Operation `json:"head,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// Operation mengembalikan operasi untuk method tertentu, atau nil.
+func (p *PathItem) Operation(method string) *Operation {
+	switch method {
+	case http.MethodGet:
+		return p.Get
+	case http.MethodHead:
+		return p.Head
+	case http.MethodPost:
+		return p.Post
+	case http.MethodPut:
+		return p.Put
+	case http.MethodPatch:
+		return p.Patch
+	case http.MethodDelete:
+		return p.Delete
+	}
+	return nil
+}
+
+func (p *PathItem) set(method string, op *Operation) {
+	switch method {
+	case http.MethodGet:
+		p.Get = op
+	case http.MethodHead:
+		p.Head = op
+	case http.MethodPost:
+		p.Post = op
+	case http.MethodPut:
+		p.Put = op
+	case http.MethodPatch:
+		p.Patch = op
+	case http.MethodDelete:
+		p.Delete = op
+	default:
+		panic("openapi: unsupported method " + method)
+	}
+}
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path, query, header, cookie
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Lookup mencari operasi untuk method dan path gin (misal "/api/rooms/:id").
+// Nama parameter path diabaikan karena gin mengizinkan nama berbeda per
+// method di posisi yang sama (POST /api/rooms/:boardingHouseID vs
+// PUT /api/rooms/:id), sedangkan OpenAPI menganggapnya path yang sama.
+func (d *Document) Lookup(method, ginPath string) *Operation {
+	want := normalizePath(GinPath(ginPath))
+	for path, item := range d.Paths {
+		if normalizePath(path) == want {
+			if op := item.Operation(method); op != nil {
+				return op
+			}
+		}
+	}
+	return nil
+}
+
+// GinPath mengubah path gin (":id", "*filepath") menjadi template OpenAPI ("{id}").
+func GinPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func normalizePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, "{") {
+			segments[i] = "{}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+var (
+	specOnce sync.Once
+	spec     *Document
+)
+
+// Spec mengembalikan dokumen OpenAPI. Dokumen dibangun sekali lalu dipakai ulang.
+func Spec() *Document {
+	specOnce.Do(func() { spec = build() })
+	return spec
+}
+
+// Handler menyajikan dokumen OpenAPI sebagai JSON dengan serverURL sebagai
+// server default (biasanya base_url dari config).
+func Handler(serverURL string) gin.HandlerFunc {
+	doc := *Spec()
+	if serverURL != "" {
+		doc.Servers = []Server{{URL: serverURL}}
+	}
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, &doc)
+	}
+}
+
+// SwaggerUI menyajikan halaman Swagger UI yang membaca dokumen dari specURL.
+// Asset Swagger UI diambil dari CDN supaya tidak perlu ikut di-deploy.
+func SwaggerUI(specURL string) gin.HandlerFunc {
+	page := strings.ReplaceAll(swaggerUIPage, "{{SPEC_URL}}", specURL)
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	}
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>KosConnect API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({ url: "{{SPEC_URL}}", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
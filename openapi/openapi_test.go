@@ -0,0 +1,83 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/openapi"
+	"github.com/organisasi/kosconnectbackend/server"
+	"github.com/organisasi/kosconnectbackend/store"
+)
+
+func newRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.ReleaseMode)
+	slog.SetDefault(server.NewLogger(io.Discard, "info"))
+
+	cfg := config.Default()
+	cfg.JWTSecret = "test-secret"
+	return server.NewServer(cfg, store.NewMemory())
+}
+
+// Setiap route yang terdaftar di gin harus ada di dokumen OpenAPI.
+func TestSpecCoversAllRoutes(t *testing.T) {
+	router := newRouter(t)
+	doc := openapi.Spec()
+
+	for _, route := range router.Routes() {
+		if doc.Lookup(route.Method, route.Path) == nil {
+			t.Errorf("route %s %s is not documented in openapi/paths.go (expected path %s)",
+				route.Method, route.Path, openapi.GinPath(route.Path))
+		}
+	}
+}
+
+// Sebaliknya, dokumen tidak boleh berisi operasi yang tidak punya route.
+func TestSpecHasNoStaleOperations(t *testing.T) {
+	router := newRouter(t)
+	doc := openapi.Spec()
+
+	covered := map[*openapi.Operation]bool{}
+	for _, route := range router.Routes() {
+		covered[doc.Lookup(route.Method, route.Path)] = true
+	}
+	for path, item := range doc.Paths {
+		for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+			if op := item.Operation(method); op != nil && !covered[op] {
+				t.Errorf("operation %s %s has no registered gin route", method, path)
+			}
+		}
+	}
+}
+
+func TestServeSpec(t *testing.T) {
+	router := newRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json = %d, want 200", w.Code)
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" || len(doc.Paths) == 0 {
+		t.Fatalf("unexpected document: openapi=%q paths=%d", doc.OpenAPI, len(doc.Paths))
+	}
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != config.Default().BaseURL {
+		t.Errorf("servers = %+v, want base_url", doc.Servers)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Fatalf("GET /docs = %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+}
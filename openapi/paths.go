@@ -0,0 +1,466 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/organisasi/kosconnectbackend/dto"
+	"github.com/organisasi/kosconnectbackend/models"
+)
+
+// build menyusun dokumen dari semua route di package routes dan server.
+// Urutan group mengikuti routes.go.
+func build() *Document {
+	b := newBuilder(Info{
+		Title:       "KosConnect API",
+		Description: "Backend KosConnect: autentikasi, kos (boarding house), kamar, fasilitas dan transaksi dengan pembayaran Midtrans.",
+		Version:     "1.0.0",
+	})
+
+	opsRoutes(b)
+	authRoutes(b)
+	userRoutes(b)
+	customFacilityRoutes(b)
+	categoryRoutes(b)
+	boardingHouseRoutes(b)
+	facilityRoutes(b)
+	roomRoutes(b)
+	transactionRoutes(b)
+	midtransRoutes(b)
+
+	return b.doc
+}
+
+func opsRoutes(b *builder) {
+	g := b.group("ops", "Health check, metric, dokumentasi dan file statis")
+
+	g.op(http.MethodGet, "/healthz", "Healthz", "Liveness probe").
+		returns(http.StatusOK, "Proses hidup", object([]string{"status"}, map[string]*Schema{"status": enum("", "ok")}))
+
+	dependency := object([]string{"status"}, map[string]*Schema{
+		"status":     enum("", "ok", "error"),
+		"error":      str("Penyebab jika status error"),
+		"latency_ms": &Schema{Type: "number"},
+	})
+	checks := object(nil, map[string]*Schema{"mongodb": dependency, "midtrans": dependency, "github": dependency})
+	g.op(http.MethodGet, "/readyz", "Readyz", "Readiness probe: MongoDB, Midtrans dan GitHub").
+		describe("Jika ada dependensi yang gagal, response 503 NOT_READY dengan hasil pemeriksaan di error.details.checks.").
+		returns(http.StatusOK, "Semua dependensi siap", object([]string{"status", "checks"}, map[string]*Schema{
+			"status": enum("", "ready"),
+			"checks": checks,
+		})).
+		fails(http.StatusServiceUnavailable)
+
+	g.op(http.MethodGet, "/metrics", "Metrics", "Metric Prometheus (text exposition format)").
+		raw(http.StatusOK, "Metric dalam format text/plain", nil)
+
+	g.op(http.MethodGet, "/openapi.json", "OpenAPI", "Dokumen OpenAPI ini").
+		raw(http.StatusOK, "Dokumen OpenAPI 3", &Schema{Type: "object"})
+	g.op(http.MethodGet, "/docs", "SwaggerUI", "Swagger UI untuk dokumen OpenAPI").
+		raw(http.StatusOK, "Halaman HTML", nil)
+
+	g.op(http.MethodGet, "/images/{filepath}", "GetImage", "File gambar statis dari folder public/images").
+		pathParam("filepath", "Nama file, misal logokos.png", nil).
+		raw(http.StatusOK, "Isi file", nil).
+		raw(http.StatusNotFound, "File tidak ditemukan", nil)
+	g.op(http.MethodHead, "/images/{filepath}", "HeadImage", "Header file gambar statis").
+		pathParam("filepath", "Nama file, misal logokos.png", nil).
+		raw(http.StatusOK, "Header file", nil)
+}
+
+func authRoutes(b *builder) {
+	g := b.group("auth", "Registrasi, login dan Google OAuth")
+	loginResult := object(nil, map[string]*Schema{
+		"role":          enum("", "user", "owner", "admin"),
+		"token":         str("JWT untuk header Authorization"),
+		"refresh_token": str("Untuk POST /auth/refresh, juga diset di cookie refreshToken"),
+		"expires_in":    {Type: "integer", Description: "Detik sampai token kedaluwarsa"},
+	})
+
+	g.op(http.MethodPost, "/auth/register", "Register", "Registrasi user baru dan kirim email verifikasi").
+		jsonBody(dto.RegisterRequest{}).
+		message(http.StatusOK, "Registrasi berhasil, cek email untuk verifikasi").
+		fails(http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError).
+		rateLimited()
+
+	g.op(http.MethodGet, "/auth/verify", "VerifyEmail", "Verifikasi email dari link di email").
+		query("token", "Token verifikasi dari email", true, str("")).
+		redirect(http.StatusFound, "Redirect ke halaman login frontend: ?verified=true, ?verified=true&reason=already_verified, "+
+			"?verified=false&reason=expired atau ?verified=false&reason=invalid").
+		fails(http.StatusInternalServerError)
+
+	g.op(http.MethodPost, "/auth/resend-verification", "ResendVerification", "Kirim ulang link verifikasi email").
+		describe("Response selalu sama, baik email terdaftar, sudah terverifikasi, atau baru dikirimi email "+
+			"dalam auth.verification_resend_interval. Link sebelumnya tidak berlaku lagi.").
+		jsonBody(dto.ResendVerificationRequest{}).
+		message(http.StatusOK, "Link verifikasi dikirim jika email terdaftar dan belum terverifikasi").
+		fails(http.StatusBadRequest, http.StatusInternalServerError).
+		rateLimited()
+
+	g.op(http.MethodPost, "/auth/login", "Login", "Login dengan email dan password").
+		jsonBody(dto.LoginRequest{}).
+		returns(http.StatusOK, "Login berhasil, token juga diset di cookie authToken dan refreshToken", loginResult).
+		fails(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError).
+		rateLimited()
+
+	g.op(http.MethodPost, "/auth/refresh", "Refresh", "Tukar refresh token dengan token baru").
+		describe("Refresh token dibaca dari body atau cookie refreshToken dan hanya bisa dipakai sekali. "+
+			"Refresh token lama yang dipakai lagi mencabut session-nya.").
+		jsonBody(dto.RefreshRequest{}).
+		returns(http.StatusOK, "Token baru", loginResult).
+		fails(http.StatusBadRequest, http.StatusUnauthorized).
+		rateLimited()
+
+	g.op(http.MethodPost, "/auth/logout", "Logout", "Cabut session saat ini, atau semua session dengan all=true").secured().
+		jsonBody(dto.LogoutRequest{}).
+		message(http.StatusOK, "Logout berhasil, cookie token dihapus").
+		fails(http.StatusBadRequest)
+
+	g.op(http.MethodPost, "/auth/forgot-password", "ForgotPassword", "Kirim link reset password ke email").
+		describe("Response selalu sama, baik email terdaftar maupun tidak. Link berlaku selama auth.password_reset_ttl "+
+			"dan hanya link dari permintaan terakhir yang bisa dipakai.").
+		jsonBody(dto.ForgotPasswordRequest{}).
+		message(http.StatusOK, "Link reset password dikirim jika email terdaftar").
+		fails(http.StatusBadRequest, http.StatusInternalServerError).
+		rateLimited()
+
+	g.op(http.MethodPost, "/auth/reset-password", "ResetForgottenPassword", "Ganti password dengan token dari email forgot-password").
+		describe("Token hanya bisa dipakai sekali. Semua session user dicabut setelah password diganti.").
+		jsonBody(dto.ResetForgottenPasswordRequest{}).
+		message(http.StatusOK, "Password diganti, user harus login ulang").
+		fails(http.StatusBadRequest, http.StatusInternalServerError).
+		rateLimited()
+
+	g.op(http.MethodGet, "/auth/google/login", "HandleGoogleLogin", "Mulai login Google OAuth").
+		describe("State dan PKCE verifier baru disimpan di cookie oauthState (signed, berlaku google.state_ttl) "+
+			"yang diperiksa di /auth/callback.").
+		redirect(http.StatusTemporaryRedirect, "Redirect ke halaman consent Google").
+		fails(http.StatusInternalServerError)
+
+	g.op(http.MethodGet, "/auth/callback", "HandleGoogleCallback", "Callback Google OAuth").
+		describe("State harus cocok dengan cookie oauthState dan hanya bisa dipakai sekali.").
+		query("state", "State OAuth dari HandleGoogleLogin", true, str("")).
+		query("code", "Authorization code dari Google", true, str("")).
+		redirect(http.StatusFound, "Redirect ke frontend: /auth-assign-role untuk user baru atau /auth jika role sudah ada").
+		fails(http.StatusBadRequest, http.StatusInternalServerError)
+
+	g.op(http.MethodPut, "/auth/assign-role", "AssignRole", "Pilih role setelah login Google pertama kali").
+		describe("Hanya untuk akun yang belum punya role; role admin tidak bisa dipilih.").
+		jsonBody(dto.AssignRoleRequest{}).
+		message(http.StatusOK, "Role tersimpan").
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+
+	g.op(http.MethodPost, "/auth/googleauth", "GoogleAuth", "Tukar Google ID token dengan JWT").
+		describe("id_token dari Google Identity Services diverifikasi terhadap JWKS Google (google.certs_url): "+
+			"tanda tangan, aud (google.client_id), iss, exp dan email_verified. User dibuat jika email belum terdaftar; "+
+			"role kosong di response berarti user harus memilih role lewat /auth/assign-role.").
+		jsonBody(dto.GoogleAuthRequest{}).
+		returns(http.StatusOK, "Login berhasil", loginResult).
+		fails(http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError).
+		rateLimited()
+}
+
+func userRoutes(b *builder) {
+	g := b.group("users", "Akun pengguna")
+	public := b.reg.ref(dto.PublicUser{})
+	self := b.reg.ref(dto.SelfUser{})
+	admin := b.reg.ref(dto.AdminUser{})
+
+	g.op(http.MethodPost, "/api/users/", "CreateUser", "Admin membuat user").secured().
+		jsonBody(dto.CreateUserRequest{}).
+		message(http.StatusOK, "User dibuat").
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusConflict)
+	g.op(http.MethodGet, "/api/users/", "GetAllUsers", "Admin melihat semua user").secured().
+		paginated(dto.UserListQuery).
+		returns(http.StatusOK, "Daftar user", arrayOf(admin)).
+		fails(http.StatusForbidden)
+	g.op(http.MethodGet, "/api/users/owner", "GetAllOwners", "Semua user dengan role owner").secured().
+		describe("Admin mendapat AdminUser, selain admin hanya PublicUser.").
+		paginated(dto.OwnerListQuery).
+		returns(http.StatusOK, "Daftar owner", oneOf(arrayOf(admin), arrayOf(public)))
+	g.op(http.MethodGet, "/api/users/{id}/owner", "GetOwnerByID", "Detail owner").secured().
+		returns(http.StatusOK, "ID dan nama owner", object(nil, map[string]*Schema{"owner_id": objectID(""), "owner_name": str("")})).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodGet, "/api/users/me", "GetMyAccount", "Akun user yang sedang login").secured().
+		returns(http.StatusOK, "Akun", self).
+		fails(http.StatusNotFound)
+	g.op(http.MethodGet, "/api/users/{id}", "GetUserByID", "Detail user").secured().
+		describe("Admin mendapat AdminUser, pemilik akun SelfUser, user lain PublicUser.").
+		returns(http.StatusOK, "User", oneOf(admin, self, public)).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodPut, "/api/users/me", "UpdateMe", "Ubah akun sendiri").secured().
+		describe("Hanya field yang diisi yang diubah. Mengganti email membuat verified_email kembali false.").
+		jsonBody(dto.UpdateUserRequest{}).
+		message(http.StatusOK, "User diperbarui").
+		fails(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict)
+	g.op(http.MethodPut, "/api/users/{id}", "UpdateUser", "Admin atau pemilik akun mengubah data user").secured().
+		describe("Hanya field yang diisi yang diubah. Mengganti email membuat verified_email kembali false.").
+		jsonBody(dto.UpdateUserRequest{}).
+		message(http.StatusOK, "User diperbarui").
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict)
+	g.op(http.MethodPut, "/api/users/{id}/role", "UpdateUserRole", "Admin mengubah role user").secured().
+		jsonBody(dto.UpdateRoleRequest{}).
+		message(http.StatusOK, "Role diperbarui").
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+	g.op(http.MethodPut, "/api/users/change-password", "ChangePassword", "Ganti password user yang login").secured().
+		jsonBody(dto.ChangePasswordRequest{}).
+		message(http.StatusOK, "Password diganti").
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodPut, "/api/users/{id}/reset-password", "ResetPassword", "Admin mereset password user").secured().
+		jsonBody(dto.ResetPasswordRequest{}).
+		message(http.StatusOK, "Password direset").
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+	g.op(http.MethodDelete, "/api/users/{id}", "DeleteUser", "Hapus user (admin, atau akun sendiri)").secured().
+		message(http.StatusOK, "User dihapus").
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+}
+
+func customFacilityRoutes(b *builder) {
+	g := b.group("customFacilities", "Fasilitas tambahan berbayar milik owner")
+	facility := b.reg.ref(models.CustomFacility{})
+
+	g.op(http.MethodPost, "/api/customFacilities/", "CreateCustomFacility", "Buat custom facility").secured().
+		describe("Owner otomatis menjadi pemilik; admin wajib mengisi owner_id.").
+		jsonBody(dto.CustomFacilityRequest{}).
+		returns(http.StatusCreated, "Custom facility dibuat", facility).
+		fails(http.StatusBadRequest, http.StatusForbidden)
+	g.op(http.MethodGet, "/api/customFacilities/", "GetAllCustomFacilities", "Semua custom facility").secured().
+		paginated(dto.CustomFacilityListQuery).
+		returns(http.StatusOK, "Daftar custom facility", arrayOf(facility))
+	g.op(http.MethodGet, "/api/customFacilities/{id}", "GetCustomFacilityByID", "Detail custom facility").secured().
+		returns(http.StatusOK, "Custom facility", facility).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodPut, "/api/customFacilities/{id}", "UpdateCustomFacility", "Ubah nama dan harga custom facility").secured().
+		describe("owner_id diabaikan.").
+		jsonBody(dto.CustomFacilityRequest{}).
+		returns(http.StatusOK, "Custom facility diperbarui", facility).
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+	g.op(http.MethodDelete, "/api/customFacilities/{id}", "DeleteCustomFacility", "Hapus custom facility").secured().
+		message(http.StatusOK, "Custom facility dihapus").
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+	g.op(http.MethodGet, "/api/customFacilities/owner", "GetCustomFacilitiesByOwnerID", "Custom facility milik owner yang login").secured().
+		returns(http.StatusOK, "Daftar custom facility", arrayOf(facility)).
+		fails(http.StatusForbidden)
+	g.op(http.MethodGet, "/api/customFacilities/admin", "GetCustomFacilitiesByOwnerIDAdmin", "Admin melihat custom facility milik owner tertentu").secured().
+		query("owner_id", "ID owner", true, objectID("")).
+		returns(http.StatusOK, "Daftar custom facility", arrayOf(facility)).
+		fails(http.StatusBadRequest, http.StatusForbidden)
+}
+
+func categoryRoutes(b *builder) {
+	g := b.group("categories", "Kategori kos (putra, putri, campur, ...)")
+	category := b.reg.ref(models.Category{})
+
+	g.op(http.MethodGet, "/api/categories/", "GetAllCategories", "Semua kategori").
+		returns(http.StatusOK, "Daftar kategori", arrayOf(category)).
+		cached()
+	g.op(http.MethodGet, "/api/categories/{id}", "GetCategoryByID", "Detail kategori").
+		returns(http.StatusOK, "Kategori", category).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodPost, "/api/categories/", "CreateCategory", "Buat kategori").secured().
+		jsonBody(dto.CategoryRequest{}).
+		returns(http.StatusOK, "Kategori dibuat", category).
+		fails(http.StatusBadRequest)
+	g.op(http.MethodPut, "/api/categories/{id}", "UpdateCategory", "Ubah kategori").secured().
+		jsonBody(dto.CategoryRequest{}).
+		message(http.StatusOK, "Kategori diperbarui").
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodDelete, "/api/categories/{id}", "DeleteCategory", "Hapus kategori").secured().
+		message(http.StatusOK, "Kategori dihapus").
+		fails(http.StatusBadRequest, http.StatusNotFound)
+}
+
+func boardingHouseRoutes(b *builder) {
+	g := b.group("boardingHouses", "Kos milik owner")
+	house := b.reg.ref(models.BoardingHouse{})
+	houseWithNames := object(nil, map[string]*Schema{
+		"boarding_house": house,
+		"category":       str("Nama kategori"),
+		"owner":          str("Nama owner"),
+		"facilities":     arrayOf(str("Nama fasilitas")),
+	})
+	createForm := withImages(b.reg.inline(dto.BoardingHouseForm{}))
+	createForm.Properties["owner_id"].Description = "Wajib jika yang membuat admin, diabaikan untuk owner"
+
+	g.op(http.MethodGet, "/api/boardingHouses/", "GetAllBoardingHouse", "Semua kos").
+		paginated(dto.BoardingHouseListQuery).
+		returns(http.StatusOK, "Daftar kos", arrayOf(house))
+	g.op(http.MethodGet, "/api/boardingHouses/{id}/detail", "GetBoardingHouseDetails", "Detail kos beserta owner, kategori dan fasilitas").
+		returns(http.StatusOK, "Hasil agregasi detail kos", &Schema{Type: "object"}).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodGet, "/api/boardingHouses/{id}", "GetBoardingHouseByID", "Kos berdasarkan ID").
+		returns(http.StatusOK, "Kos dengan nama kategori, owner dan fasilitas", houseWithNames).
+		cached().
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodPost, "/api/boardingHouses/", "CreateBoardingHouse", "Buat kos").secured().
+		formBody(createForm).
+		returns(http.StatusCreated, "Kos dibuat", house).
+		fails(http.StatusBadRequest, http.StatusForbidden)
+	g.op(http.MethodGet, "/api/boardingHouses/owner", "GetBoardingHouseByOwnerID", "Kos milik owner yang login").secured().
+		returns(http.StatusOK, "Daftar kos", arrayOf(house)).
+		fails(http.StatusForbidden)
+	g.op(http.MethodPut, "/api/boardingHouses/{id}", "UpdateBoardingHouse", "Ubah kos").secured().
+		describe("Hanya field yang diisi yang diubah. Jika ada file images, gambar lama diganti.").
+		formBody(withImages(b.reg.inline(dto.UpdateBoardingHouseForm{}))).
+		returns(http.StatusOK, "Kos diperbarui", houseWithNames).
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+	g.op(http.MethodDelete, "/api/boardingHouses/{id}", "DeleteBoardingHouse", "Hapus kos").secured().
+		message(http.StatusOK, "Kos dihapus").
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+}
+
+func facilityRoutes(b *builder) {
+	g := b.group("facility", "Fasilitas umum untuk kamar dan kos")
+	facility := b.reg.ref(models.Facility{})
+	facilityType := enum("", "room", "boarding_house")
+
+	g.op(http.MethodPost, "/api/facility/", "CreateFacility", "Buat fasilitas").secured().
+		jsonBody(dto.FacilityRequest{}).
+		returns(http.StatusCreated, "Fasilitas dibuat", facility).
+		fails(http.StatusBadRequest)
+	g.op(http.MethodGet, "/api/facility/", "GetAllFacilities", "Semua fasilitas").secured().
+		returns(http.StatusOK, "Daftar fasilitas", arrayOf(facility))
+	g.op(http.MethodGet, "/api/facility/type", "GetFacilitiesByType", "Fasilitas berdasarkan type").secured().
+		query("type", "Jenis fasilitas", true, facilityType).
+		returns(http.StatusOK, "Daftar fasilitas", arrayOf(facility)).
+		fails(http.StatusBadRequest)
+	g.op(http.MethodGet, "/api/facility/{id}", "GetFacilityByID", "Detail fasilitas").secured().
+		returns(http.StatusOK, "Fasilitas", facility).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodPut, "/api/facility/{id}", "UpdateFacility", "Ubah fasilitas").secured().
+		jsonBody(dto.FacilityRequest{}).
+		message(http.StatusOK, "Fasilitas diperbarui").
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodDelete, "/api/facility/{id}", "DeleteFacility", "Hapus fasilitas").secured().
+		message(http.StatusOK, "Fasilitas dihapus").
+		fails(http.StatusBadRequest, http.StatusNotFound)
+}
+
+func roomRoutes(b *builder) {
+	g := b.group("rooms", "Kamar di dalam kos")
+	room := b.reg.ref(models.Room{})
+	aggregate := &Schema{Type: "object"}
+	form := withImages(b.reg.inline(dto.RoomForm{}))
+	form.Properties["size"].Description = "Misal 3x4"
+	form.Properties["number_available"].Description = "Jumlah kamar tersedia; status menjadi Tersedia jika >= 1"
+	for _, price := range []string{"price_monthly", "price_quarterly", "price_semi_annual", "price_yearly"} {
+		form.Properties[price].Description = "Harga dalam Rupiah tanpa pemisah, minimal satu harga harus diisi"
+	}
+
+	g.op(http.MethodGet, "/api/rooms/{id}/detail", "GetRoomDetailsByID", "Detail kamar beserta kos dan fasilitas").
+		returns(http.StatusOK, "Hasil agregasi detail kamar", aggregate).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodGet, "/api/rooms/{id}/pages", "GetRoomDetailPages", "Data halaman detail kamar").
+		returns(http.StatusOK, "Hasil agregasi halaman detail", aggregate).
+		cached().
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodGet, "/api/rooms/home", "GetRoomsForLandingPage", "Kamar untuk landing page").
+		paginated(dto.RoomLandingPageQuery).
+		returns(http.StatusOK, "Hasil agregasi landing page", arrayOf(aggregate)).
+		cached()
+	g.op(http.MethodGet, "/api/rooms/", "GetAllRooms", "Semua kamar").
+		paginated(dto.RoomListQuery).
+		returns(http.StatusOK, "Daftar kamar", arrayOf(room))
+	g.op(http.MethodGet, "/api/rooms/{id}", "GetRoomByID", "Kamar berdasarkan ID").secured().
+		returns(http.StatusOK, "Kamar", room).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodGet, "/api/rooms/boarding-house/{id}", "GetRoomByBoardingHouseID", "Kamar dalam satu kos").secured().
+		pathParam("id", "ID boarding house", nil).
+		returns(http.StatusOK, "Daftar kamar", arrayOf(room)).
+		fails(http.StatusBadRequest, http.StatusForbidden)
+	g.op(http.MethodPost, "/api/rooms/{id}", "CreateRoom", "Buat kamar di sebuah kos").secured().
+		describe("Parameter path di gin bernama :boardingHouseID. Body multipart, harga dan jumlah kamar dikirim sebagai teks angka.").
+		pathParam("id", "ID boarding house tempat kamar dibuat", nil).
+		formBody(form).
+		returns(http.StatusCreated, "Kamar dibuat", object(nil, map[string]*Schema{
+			"room":             room,
+			"prices_formatted": &Schema{Type: "object", AdditionalProperties: str("Harga dalam format Rupiah")},
+		})).
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+	g.op(http.MethodPut, "/api/rooms/{id}", "UpdateRoom", "Ubah kamar").secured().
+		formBody(form).
+		message(http.StatusOK, "Kamar diperbarui").
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+	g.op(http.MethodDelete, "/api/rooms/{id}", "DeleteRoom", "Hapus kamar").secured().
+		message(http.StatusOK, "Kamar dihapus").
+		fails(http.StatusBadRequest, http.StatusNotFound)
+}
+
+func transactionRoutes(b *builder) {
+	g := b.group("transaction", "Transaksi sewa kamar")
+	transaction := b.reg.ref(models.Transaction{})
+	list := arrayOf(transaction)
+
+	g.op(http.MethodPost, "/api/transaction/", "CreateTransaction", "Buat transaksi sewa").secured().
+		describe("ID kamar, kos, owner dan user dikirim lewat query string, sisanya di body JSON. "+
+			"409 ROOM_NOT_AVAILABLE jika kamar sudah penuh, termasuk jika kamar terakhir diambil booking lain bersamaan.").
+		query("room_id", "ID kamar", true, objectID("")).
+		query("boarding_house_id", "ID kos", true, objectID("")).
+		query("owner_id", "ID owner kos", true, objectID("")).
+		query("user_id", "ID penyewa", true, objectID("")).
+		jsonBody(dto.CreateTransactionRequest{}).
+		returns(http.StatusOK, "Transaksi dibuat", object(nil, map[string]*Schema{
+			"transaction_id": objectID(""),
+			"details": object(nil, map[string]*Schema{
+				"room_price":       {Type: "number"},
+				"facilities_price": {Type: "number"},
+				"subtotal":         {Type: "number"},
+				"ppn":              {Type: "number", Description: "PPN 11% dari subtotal"},
+				"total":            {Type: "number"},
+			}),
+		})).
+		fails(http.StatusBadRequest, http.StatusNotFound).
+		rateLimited().
+		idempotent()
+	g.op(http.MethodGet, "/api/transaction/", "GetAllTransactions", "Semua transaksi").secured().
+		paginated(dto.TransactionListQuery).
+		returns(http.StatusOK, "Daftar transaksi", list)
+	g.op(http.MethodGet, "/api/transaction/{id}", "GetTransactionByID", "Detail transaksi").secured().
+		returns(http.StatusOK, "Transaksi", transaction).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	g.op(http.MethodGet, "/api/transaction/user", "GetTransactionsByUser", "Transaksi milik user yang login").secured().
+		returns(http.StatusOK, "Daftar transaksi", list)
+	g.op(http.MethodGet, "/api/transaction/admin/user/{id}", "GetTransactionsUserByAdmin", "Admin melihat transaksi seorang user").secured().
+		pathParam("id", "ID user", nil).
+		returns(http.StatusOK, "Daftar transaksi", list).
+		fails(http.StatusBadRequest)
+	g.op(http.MethodGet, "/api/transaction/owner", "GetTransactionsByOwner", "Transaksi untuk kos milik owner yang login").secured().
+		returns(http.StatusOK, "Daftar transaksi", list).
+		fails(http.StatusForbidden)
+	g.op(http.MethodGet, "/api/transaction/admin/owner/{id}", "GetTransactionsOwnerByAdmin", "Admin melihat transaksi seorang owner").secured().
+		pathParam("id", "ID owner", nil).
+		returns(http.StatusOK, "Daftar transaksi", list).
+		fails(http.StatusBadRequest)
+	g.op(http.MethodGet, "/api/transaction/status/{status}", "GetTransactionsByPaymentStatus", "Transaksi berdasarkan status pembayaran").secured().
+		pathParam("status", "Status pembayaran", str("Misal pending, paid, settlement, expire")).
+		returns(http.StatusOK, "Daftar transaksi", list)
+	g.op(http.MethodPut, "/api/transaction/{id}/payment-status", "UpdateTransaction", "Ubah status dan metode pembayaran").secured().
+		describe("Hanya admin, atau owner kos dari transaksi tersebut.").
+		jsonBody(dto.UpdateTransactionRequest{}).
+		returns(http.StatusOK, "Transaksi diperbarui", object(nil, map[string]*Schema{"transaction_id": objectID("")})).
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+	g.op(http.MethodDelete, "/api/transaction/{id}", "DeleteTransaction", "Hapus transaksi (admin)").secured().
+		message(http.StatusOK, "Transaksi dihapus").
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+}
+
+func midtransRoutes(b *builder) {
+	g := b.group("midtrans", "Pembayaran lewat Midtrans Snap")
+
+	g.op(http.MethodPost, "/transactions/{transaction_id}/payment", "CreatePayment", "Buat Snap token untuk transaksi").
+		returns(http.StatusOK, "URL halaman pembayaran Snap", object(nil, map[string]*Schema{
+			"redirectURL": {Type: "string", Format: "uri"},
+		})).
+		fails(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout).
+		idempotent()
+	g.op(http.MethodPost, "/midtrans/notification", "PaymentNotification", "Webhook notifikasi pembayaran dari Midtrans").
+		describe("Order yang tidak dikenal tetap dibalas 200 supaya Midtrans tidak mengulang notifikasi.").
+		jsonBody(dto.PaymentNotification{}).
+		message(http.StatusOK, "Status pembayaran diperbarui").
+		fails(http.StatusBadRequest)
+}
+
+// withImages menambahkan field file images ke schema form multipart.
+func withImages(form *Schema) *Schema {
+	form.Properties["images"] = arrayOf(binary("File gambar, diupload ke GitHub"))
+	return form
+}
@@ -0,0 +1,200 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Schema adalah subset JSON Schema yang dipakai OpenAPI 3.0.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Example              any                `json:"example,omitempty"`
+}
+
+var (
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// objectID adalah schema untuk primitive.ObjectID (hex 24 karakter).
+func objectID(description string) *Schema {
+	return &Schema{Type: "string", Pattern: "^[0-9a-fA-F]{24}$", Description: description, Example: "6756b8e4a1b2c3d4e5f60718"}
+}
+
+// schemaRegistry membangkitkan schema dari struct Go berdasarkan tag json
+// dan menyimpan struct bernama sebagai components/schemas.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: map[string]*Schema{}}
+}
+
+// ref mendaftarkan tipe v sebagai component dan mengembalikan $ref ke sana.
+func (r *schemaRegistry) ref(v any) *Schema {
+	return r.schemaFor(reflect.TypeOf(v))
+}
+
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case objectIDType:
+		return objectID("")
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		if _, ok := r.schemas[t.Name()]; !ok {
+			r.schemas[t.Name()] = &Schema{} // placeholder untuk tipe rekursif
+			r.schemas[t.Name()] = r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+	return &Schema{}
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			// Struct embedded diratakan seperti encoding/json
+			embedded := r.structSchema(f.Type)
+			for key, prop := range embedded.Properties {
+				s.Properties[key] = prop
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = f.Tag.Get("form") // DTO multipart/query dari package dto
+		}
+		if name == "" {
+			name = f.Name
+		}
+		rules := f.Tag.Get("binding")
+		field := r.schemaFor(f.Type)
+		if field.Ref == "" {
+			applyRules(field, rules)
+		}
+		if strings.Contains(name, "password") {
+			field.Format = "password"
+		}
+		s.Properties[name] = field
+		if strings.HasPrefix(rules, "required") && !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
+
+// inline mengembalikan schema struct v tanpa mendaftarkannya sebagai component,
+// untuk body yang masih perlu ditambah property (misal file images di form).
+func (r *schemaRegistry) inline(v any) *Schema {
+	return r.structSchema(reflect.TypeOf(v))
+}
+
+// applyRules menerjemahkan tag binding (lihat package validation) ke JSON Schema.
+// Aturan setelah "dive" berlaku untuk item array.
+func applyRules(s *Schema, rules string) {
+	target := s
+	for _, rule := range strings.Split(rules, ",") {
+		tag, param, _ := strings.Cut(rule, "=")
+		switch tag {
+		case "dive":
+			if s.Items != nil {
+				target = s.Items
+			}
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "oneof":
+			target.Enum = strings.Fields(param)
+		case "objectid":
+			*target = *objectID(target.Description)
+		case "phone_id":
+			target.Pattern = `^(\+62|62|0)8[1-9][0-9]{6,10}$`
+			target.Example = "081234567890"
+		case "payment_term":
+			target.Enum = validation.PaymentTerms
+		case "future_date":
+			target.Format = "date"
+			target.Description = "Hari ini atau setelahnya"
+		case "number":
+			target.Pattern = "^[0-9]+$"
+		case "json":
+			target.Description = "JSON array berisi ObjectID, misal [\"6756b8e4a1b2c3d4e5f60718\"]"
+		}
+	}
+}
+
+// object membuat schema object inline untuk body yang di controller
+// dideklarasikan sebagai struct anonim.
+func object(required []string, properties map[string]*Schema) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+func str(description string) *Schema {
+	return &Schema{Type: "string", Description: description}
+}
+
+func enum(description string, values ...string) *Schema {
+	return &Schema{Type: "string", Description: description, Enum: values}
+}
+
+func oneOf(schemas ...*Schema) *Schema {
+	return &Schema{OneOf: schemas}
+}
+
+func arrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+func binary(description string) *Schema {
+	return &Schema{Type: "string", Format: "binary", Description: description}
+}
@@ -0,0 +1,49 @@
+package query
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Cursor menandai dokumen terakhir sebuah halaman: nilai field sort dan
+// _id-nya. Disimpan sebagai BSON agar tipe nilai (tanggal, ObjectID,
+// angka) tetap sama saat dibandingkan di MongoDB.
+type Cursor struct {
+	Sort  string      `bson:"s"`
+	Value interface{} `bson:"v"`
+	ID    interface{} `bson:"id"`
+}
+
+// Encode mengubah cursor menjadi string aman untuk URL. Isinya bukan
+// rahasia, hanya tidak dimaksudkan untuk dibaca client.
+func (c Cursor) Encode() string {
+	data, err := bson.Marshal(c)
+	if err != nil {
+		// Nilai berasal dari dokumen MongoDB, jadi selalu bisa di-marshal
+		panic("query: encode cursor: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor membaca string dari Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c Cursor
+	if err := bson.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if c.Sort == "" || c.ID == nil {
+		return nil, errors.New("query: incomplete cursor")
+	}
+	return &c, nil
+}
+
+// Next membuat cursor halaman berikutnya dari dokumen terakhir halaman ini.
+func (q Query) Next(last bson.M) string {
+	return Cursor{Sort: q.Sort, Value: last[q.Field], ID: last["_id"]}.Encode()
+}
@@ -0,0 +1,273 @@
+// Package query membaca parameter pagination, sort dan filter dari query
+// string endpoint list lalu mengubahnya menjadi opsi find dan stage
+// pipeline MongoDB.
+//
+//	GET /api/transaction?payment_status=pending&sort=-created_at&limit=20&page=2
+//	GET /api/transaction?limit=20&cursor=<next_cursor dari response sebelumnya>
+//
+// Hanya sort dan filter yang terdaftar di Spec endpoint yang diterima;
+// parameter lain diabaikan. Filter berupa kesetaraan (field == nilai).
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/organisasi/kosconnectbackend/validation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Kind menentukan cara nilai filter dari query string dibaca.
+type Kind int
+
+const (
+	String   Kind = iota
+	ObjectID      // Hex 24 karakter, dibandingkan sebagai primitive.ObjectID
+)
+
+// Filter memetakan satu parameter query ke field dokumen.
+type Filter struct {
+	Field string
+	Kind  Kind
+}
+
+// Spec adalah whitelist sort dan filter untuk satu endpoint.
+type Spec struct {
+	// Sort memetakan nama di ?sort= ke field dokumen, misal
+	// {"created_at": "created_at", "name": "fullname"}.
+	Sort map[string]string
+	// DefaultSort dipakai jika ?sort= kosong, misal "-created_at".
+	DefaultSort string
+	Filters     map[string]Filter
+}
+
+// Limits membatasi ?limit= (lihat pagination di config).
+type Limits struct {
+	Default int
+	Max     int
+}
+
+// Query adalah hasil Parse.
+type Query struct {
+	Filter bson.M // Filter dari parameter yang ada di Spec.Filters
+	Sort   string // Nama sort seperti di ?sort=, misal "-created_at"
+	Field  string // Field dokumen yang diurutkan
+	Desc   bool
+	Limit  int
+	Page   int     // Mulai dari 1; 0 jika memakai cursor
+	After  *Cursor // Posisi dokumen terakhir halaman sebelumnya
+}
+
+// Parse membaca page, limit, cursor, sort dan filter. Semua kesalahan
+// dikembalikan sekaligus dalam bentuk error per field.
+func Parse(values url.Values, spec Spec, limits Limits) (Query, []validation.FieldError) {
+	q := Query{Filter: bson.M{}, Limit: limits.Default, Page: 1}
+	var errs []validation.FieldError
+	invalid := func(field, rule, message string) {
+		errs = append(errs, validation.FieldError{Field: field, Rule: rule, Message: message})
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > limits.Max {
+			invalid("limit", "range", fmt.Sprintf("must be a number between 1 and %d", limits.Max))
+		} else {
+			q.Limit = n
+		}
+	}
+
+	q.Sort = values.Get("sort")
+	if raw := values.Get("cursor"); raw != "" {
+		cursor, err := DecodeCursor(raw)
+		switch {
+		case err != nil:
+			invalid("cursor", "cursor", "is not a valid cursor")
+		case values.Get("page") != "":
+			invalid("page", "excluded_with", "cannot be combined with cursor")
+		case q.Sort != "" && q.Sort != cursor.Sort:
+			invalid("sort", "cursor", "must match the sort used to create the cursor")
+		default:
+			q.Sort = cursor.Sort
+			q.After = cursor
+			q.Page = 0
+		}
+	} else if raw := values.Get("page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			invalid("page", "min", "must be a number greater than or equal to 1")
+		} else {
+			q.Page = n
+		}
+	}
+
+	if q.Sort == "" {
+		q.Sort = spec.DefaultSort
+	}
+	name := strings.TrimPrefix(q.Sort, "-")
+	field, ok := spec.Sort[name]
+	if !ok {
+		invalid("sort", "oneof", "must be one of "+strings.Join(sortNames(spec), ", ")+", optionally prefixed with -")
+	}
+	q.Field = field
+	q.Desc = strings.HasPrefix(q.Sort, "-")
+
+	for param, filter := range spec.Filters {
+		raw := values.Get(param)
+		if raw == "" {
+			continue
+		}
+		switch filter.Kind {
+		case ObjectID:
+			id, err := primitive.ObjectIDFromHex(raw)
+			if err != nil {
+				invalid(param, "objectid", "must be a 24-character hex ObjectID")
+				continue
+			}
+			q.Filter[filter.Field] = id
+		default:
+			q.Filter[filter.Field] = raw
+		}
+	}
+	return q, errs
+}
+
+// sortNames mengembalikan nama sort yang diizinkan, terurut agar pesan
+// error selalu sama.
+func sortNames(spec Spec) []string {
+	names := make([]string, 0, len(spec.Sort))
+	for name := range spec.Sort {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (q Query) direction() int {
+	if q.Desc {
+		return -1
+	}
+	return 1
+}
+
+// SortDoc mengurutkan berdasarkan field lalu _id agar urutan stabil untuk
+// cursor meskipun nilai field sama.
+func (q Query) SortDoc() bson.D {
+	if q.Field == "_id" {
+		return bson.D{{Key: "_id", Value: q.direction()}}
+	}
+	return bson.D{{Key: q.Field, Value: q.direction()}, {Key: "_id", Value: q.direction()}}
+}
+
+// Match menggabungkan filter dari query string dengan base, filter wajib
+// dari handler (misal role owner). Nilai base menang jika field-nya sama.
+func (q Query) Match(base bson.M) bson.M {
+	filter := bson.M{}
+	for key, value := range q.Filter {
+		filter[key] = value
+	}
+	for key, value := range base {
+		filter[key] = value
+	}
+	return filter
+}
+
+// Skip adalah jumlah dokumen yang dilewati untuk ?page=.
+func (q Query) Skip() int64 {
+	if q.Page <= 1 {
+		return 0
+	}
+	return int64(q.Page-1) * int64(q.Limit)
+}
+
+// Seek adalah filter untuk dokumen setelah cursor, kosong jika tanpa cursor.
+//
+// Field sort yang null atau tidak ada (misal number_available 0 yang
+// omitempty) butuh cabang sendiri: MongoDB mengurutkannya paling awal
+// saat ascending dan paling akhir saat descending, tapi $gt/$lt tidak
+// pernah cocok dengan null. {field: null} cocok dengan keduanya.
+func (q Query) Seek() bson.M {
+	if q.After == nil {
+		return bson.M{}
+	}
+	op := "$gt"
+	if q.Desc {
+		op = "$lt"
+	}
+	if q.Field == "_id" {
+		return bson.M{"_id": bson.M{op: q.After.ID}}
+	}
+
+	sameValue := bson.M{q.Field: q.After.Value, "_id": bson.M{op: q.After.ID}}
+	switch {
+	case q.After.Value == nil && q.Desc:
+		// Null ada di akhir, tinggal sisa null setelah _id cursor
+		return sameValue
+	case q.After.Value == nil:
+		return bson.M{"$or": bson.A{
+			sameValue,
+			bson.M{q.Field: bson.M{"$ne": nil}},
+		}}
+	case q.Desc:
+		return bson.M{"$or": bson.A{
+			bson.M{q.Field: bson.M{op: q.After.Value}},
+			sameValue,
+			bson.M{q.Field: nil},
+		}}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{q.Field: bson.M{op: q.After.Value}},
+		sameValue,
+	}}
+}
+
+// FindOptions berisi sort, skip dan limit untuk Collection.Find. Limit
+// ditambah satu untuk mengetahui apakah masih ada halaman berikutnya.
+func (q Query) FindOptions() *options.FindOptions {
+	return options.Find().SetSort(q.SortDoc()).SetSkip(q.Skip()).SetLimit(int64(q.Limit) + 1)
+}
+
+// Stages ditambahkan di akhir pipeline agregasi. Hasilnya satu dokumen
+// {"items": [...], "total": [{"count": n}]}; items berisi paling banyak
+// Limit+1 dokumen seperti FindOptions.
+func (q Query) Stages() mongo.Pipeline {
+	items := bson.A{bson.M{"$sort": q.SortDoc()}}
+	if seek := q.Seek(); len(seek) > 0 {
+		items = bson.A{bson.M{"$match": seek}, bson.M{"$sort": q.SortDoc()}}
+	}
+	if skip := q.Skip(); skip > 0 {
+		items = append(items, bson.M{"$skip": skip})
+	}
+	items = append(items, bson.M{"$limit": q.Limit + 1})
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: q.Filter}},
+		{{Key: "$facet", Value: bson.M{
+			"items": items,
+			"total": bson.A{bson.M{"$count": "count"}},
+		}}},
+	}
+}
+
+// Key adalah representasi q yang stabil untuk key cache: dua Query dengan
+// Key sama menghasilkan halaman yang sama.
+func (q Query) Key() string {
+	after := ""
+	if q.After != nil {
+		after = q.After.Encode()
+	}
+	// fmt mencetak map dengan key terurut
+	return fmt.Sprintf("%v|%s|%d|%d|%s", q.Filter, q.Sort, q.Limit, q.Page, after)
+}
+
+// Page adalah satu halaman hasil list.
+type Page[T any] struct {
+	Items      []T
+	Total      int64  // Jumlah dokumen yang cocok dengan filter, tanpa pagination
+	NextCursor string // Kosong jika ini halaman terakhir
+}
@@ -0,0 +1,183 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// evalSeek mengevaluasi filter dari Seek terhadap doc dengan semantik
+// MongoDB untuk angka: $gt/$lt tidak cocok dengan null atau field yang tidak
+// ada, {field: null} cocok dengan keduanya, $ne null hanya dengan yang terisi.
+func evalSeek(t *testing.T, filter bson.M, doc bson.M) bool {
+	t.Helper()
+	for key, cond := range filter {
+		if key == "$or" {
+			matched := false
+			for _, branch := range cond.(bson.A) {
+				matched = matched || evalSeek(t, branch.(bson.M), doc)
+			}
+			if !matched {
+				return false
+			}
+			continue
+		}
+		value, present := number(doc[key])
+		ops, isOps := cond.(bson.M)
+		if !isOps {
+			want, wantPresent := number(cond)
+			if present != wantPresent || value != want {
+				return false
+			}
+			continue
+		}
+		for op, arg := range ops {
+			want, wantPresent := number(arg)
+			switch op {
+			case "$gt":
+				if !present || value <= want {
+					return false
+				}
+			case "$lt":
+				if !present || value >= want {
+					return false
+				}
+			case "$ne":
+				if wantPresent {
+					t.Fatalf("unexpected $ne %v", arg)
+				}
+				if !present {
+					return false
+				}
+			default:
+				t.Fatalf("unexpected operator %s", op)
+			}
+		}
+	}
+	return true
+}
+
+func number(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case nil:
+		return 0, false
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	}
+	panic(fmt.Sprintf("number: %T", v))
+}
+
+func TestSeekNullValues(t *testing.T) {
+	// rooms dengan number_available omitempty: 0 tidak ditulis, sebagian lagi null
+	docs := []bson.M{
+		{"_id": 1, "number_available": 3},
+		{"_id": 2},
+		{"_id": 3, "number_available": nil},
+		{"_id": 4, "number_available": 1},
+		{"_id": 5, "number_available": 3},
+		{"_id": 6},
+		{"_id": 7, "number_available": 2},
+	}
+	spec := Spec{Sort: map[string]string{"number_available": "number_available"}}
+
+	for _, sortParam := range []string{"number_available", "-number_available"} {
+		t.Run(sortParam, func(t *testing.T) {
+			// Urutan MongoDB: null/tidak ada paling awal saat ascending
+			want := slices.Clone(docs)
+			sort.SliceStable(want, func(i, j int) bool {
+				a, aok := number(want[i]["number_available"])
+				b, bok := number(want[j]["number_available"])
+				ai, bi := want[i]["_id"].(int), want[j]["_id"].(int)
+				less := !aok && bok || aok == bok && (a < b || a == b && ai < bi)
+				if sortParam[0] == '-' {
+					less = !less
+				}
+				return less
+			})
+
+			values := url.Values{"sort": {sortParam}}
+			var got []interface{}
+			for pages := 0; pages < len(docs); pages++ {
+				q, errs := Parse(values, spec, Limits{Default: 2, Max: 10})
+				if errs != nil {
+					t.Fatal(errs)
+				}
+				seek := q.Seek()
+				var page []bson.M
+				for _, doc := range want {
+					if evalSeek(t, seek, doc) {
+						page = append(page, doc)
+					}
+				}
+				for _, doc := range page[:min(q.Limit, len(page))] {
+					got = append(got, doc["_id"])
+				}
+				if len(page) <= q.Limit {
+					break
+				}
+				values = url.Values{"cursor": {q.Next(page[q.Limit-1])}}
+			}
+
+			var ids []interface{}
+			for _, doc := range want {
+				ids = append(ids, doc["_id"])
+			}
+			if fmt.Sprint(got) != fmt.Sprint(ids) {
+				t.Errorf("paged ids = %v, want %v", got, ids)
+			}
+		})
+	}
+}
+
+func TestSeekShape(t *testing.T) {
+	after := func(value interface{}, desc bool) Query {
+		return Query{Field: "price", Desc: desc, After: &Cursor{Sort: "price", Value: value, ID: 9}}
+	}
+	for name, tc := range map[string]struct {
+		q    Query
+		want bson.M
+	}{
+		"no cursor": {Query{Field: "price"}, bson.M{}},
+		"id only": {
+			Query{Field: "_id", After: &Cursor{Sort: "_id", ID: 9}},
+			bson.M{"_id": bson.M{"$gt": 9}},
+		},
+		"asc value": {after(5, false), bson.M{"$or": bson.A{
+			bson.M{"price": bson.M{"$gt": 5}},
+			bson.M{"price": 5, "_id": bson.M{"$gt": 9}},
+		}}},
+		"asc null": {after(nil, false), bson.M{"$or": bson.A{
+			bson.M{"price": nil, "_id": bson.M{"$gt": 9}},
+			bson.M{"price": bson.M{"$ne": nil}},
+		}}},
+		"desc value": {after(5, true), bson.M{"$or": bson.A{
+			bson.M{"price": bson.M{"$lt": 5}},
+			bson.M{"price": 5, "_id": bson.M{"$lt": 9}},
+			bson.M{"price": nil},
+		}}},
+		"desc null": {after(nil, true), bson.M{"price": nil, "_id": bson.M{"$lt": 9}}},
+	} {
+		if got := tc.q.Seek(); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("%s: Seek() = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestCursorMissingValue(t *testing.T) {
+	q := Query{Sort: "price", Field: "price"}
+	cursor, err := DecodeCursor(q.Next(bson.M{"_id": 9}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Value != nil {
+		t.Errorf("Value = %v, want nil for a missing field", cursor.Value)
+	}
+}
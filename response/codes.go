@@ -0,0 +1,40 @@
+package response
+
+// Code adalah kode error yang stabil untuk dibaca frontend.
+type Code string
+
+// Kode umum
+const (
+	CodeValidationFailed Code = "VALIDATION_FAILED" // Body, form atau query tidak valid
+	CodeInvalidID        Code = "INVALID_ID"        // ID bukan ObjectID yang valid
+	CodeUnauthorized     Code = "UNAUTHORIZED"      // Token tidak ada atau format salah
+	CodeInvalidToken     Code = "INVALID_TOKEN"     // Token JWT/verifikasi/reset password tidak valid atau kedaluwarsa
+	CodeSessionRevoked   Code = "SESSION_REVOKED"   // Session token sudah logout atau dicabut
+	CodeForbidden        Code = "FORBIDDEN"         // Role tidak punya akses
+	CodeRouteNotFound    Code = "ROUTE_NOT_FOUND"   // Tidak ada route untuk path tersebut
+	CodeMethodNotAllowed Code = "METHOD_NOT_ALLOWED"
+	CodeTimeout          Code = "TIMEOUT"      // Database atau layanan luar melewati batas waktu
+	CodeRateLimited      Code = "RATE_LIMITED" // Terlalu banyak request, lihat header Retry-After
+	CodeInternal         Code = "INTERNAL_ERROR"
+	CodeNotReady         Code = "NOT_READY" // Readiness check gagal
+)
+
+// Kode per domain
+const (
+	CodeInvalidCredentials     Code = "INVALID_CREDENTIALS"
+	CodeEmailAlreadyExists     Code = "EMAIL_ALREADY_EXISTS"
+	CodeEmailNotVerified       Code = "EMAIL_NOT_VERIFIED" // Login ditolak, lihat auth.require_verified_email
+	CodeUserNotFound           Code = "USER_NOT_FOUND"
+	CodeCategoryNotFound       Code = "CATEGORY_NOT_FOUND"
+	CodeBoardingHouseNotFound  Code = "BOARDING_HOUSE_NOT_FOUND"
+	CodeFacilityNotFound       Code = "FACILITY_NOT_FOUND"
+	CodeCustomFacilityNotFound Code = "CUSTOM_FACILITY_NOT_FOUND"
+	CodeRoomNotFound           Code = "ROOM_NOT_FOUND"
+	CodeRoomNotAvailable       Code = "ROOM_NOT_AVAILABLE"
+	CodeTransactionNotFound    Code = "TRANSACTION_NOT_FOUND"
+	CodeUploadFailed           Code = "UPLOAD_FAILED"           // Upload gambar ke GitHub gagal
+	CodePaymentFailed          Code = "PAYMENT_FAILED"          // Midtrans menolak atau gagal membuat transaksi
+	CodeEmailFailed            Code = "EMAIL_FAILED"            // Email tidak terkirim
+	CodeIdempotencyKeyReused   Code = "IDEMPOTENCY_KEY_REUSED"  // Idempotency-Key sama dengan request berbeda
+	CodeIdempotencyInProgress  Code = "IDEMPOTENCY_IN_PROGRESS" // Request pertama dengan key ini belum selesai
+)
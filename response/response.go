@@ -0,0 +1,71 @@
+// Package response berisi envelope JSON yang dipakai semua endpoint:
+//
+//	{"data": ..., "error": {"code": "...", "message": "...", "details": ...}, "meta": {...}}
+//
+// Response sukses mengisi data (dan meta jika perlu) dengan error bernilai null,
+// response gagal mengisi error dengan data bernilai null. Frontend sebaiknya
+// membaca error.code, bukan error.message, karena message bisa berubah.
+package response
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Envelope adalah bentuk tetap semua response JSON.
+type Envelope struct {
+	Data  any    `json:"data"`
+	Error *Error `json:"error"`
+	Meta  *Meta  `json:"meta,omitempty"`
+}
+
+// Error menjelaskan kegagalan request.
+type Error struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
+}
+
+// Meta berisi informasi tambahan di luar data.
+type Meta struct {
+	Message string `json:"message,omitempty"`
+
+	// Pagination endpoint list (lihat package query)
+	Total      *int64 `json:"total,omitempty"`       // Jumlah data yang cocok dengan filter
+	Page       int    `json:"page,omitempty"`        // Tidak ada jika memakai cursor
+	Limit      int    `json:"limit,omitempty"`       // Ukuran halaman
+	NextCursor string `json:"next_cursor,omitempty"` // Tidak ada di halaman terakhir
+}
+
+// Success mengirim response sukses dengan data dan meta opsional.
+func Success(c *gin.Context, status int, data any, meta *Meta) {
+	c.JSON(status, Envelope{Data: data, Meta: meta})
+}
+
+// OK mengirim data dengan status 200.
+func OK(c *gin.Context, data any) {
+	Success(c, http.StatusOK, data, nil)
+}
+
+// Message mengirim data beserta pesan untuk ditampilkan ke pengguna,
+// misal setelah create, update atau delete.
+func Message(c *gin.Context, status int, data any, message string) {
+	Success(c, status, data, &Meta{Message: message})
+}
+
+// Fail mengirim response error.
+func Fail(c *gin.Context, status int, code Code, message string) {
+	c.JSON(status, Envelope{Error: &Error{Code: code, Message: message}})
+}
+
+// FailDetails sama seperti Fail dengan detail tambahan, misal error per field.
+func FailDetails(c *gin.Context, status int, code Code, message string, details any) {
+	c.JSON(status, Envelope{Error: &Error{Code: code, Message: message, Details: details}})
+}
+
+// Abort mengirim response error lalu menghentikan handler berikutnya.
+// Dipakai oleh middleware.
+func Abort(c *gin.Context, status int, code Code, message string) {
+	c.AbortWithStatusJSON(status, Envelope{Error: &Error{Code: code, Message: message}})
+}
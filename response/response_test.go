@@ -0,0 +1,69 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestEnvelope memeriksa JSON yang dibaca frontend secara persis: data dan
+// error selalu ada (salah satunya null), meta hanya ada jika diisi.
+func TestEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	total, zero := int64(42), int64(0)
+	for _, tc := range []struct {
+		name   string
+		send   func(c *gin.Context)
+		status int
+		body   string
+	}{
+		{"ok", func(c *gin.Context) { OK(c, gin.H{"id": 1}) },
+			http.StatusOK, `{"data":{"id":1},"error":null}`},
+		{"ok without data", func(c *gin.Context) { OK(c, nil) },
+			http.StatusOK, `{"data":null,"error":null}`},
+		{"message", func(c *gin.Context) { Message(c, http.StatusCreated, gin.H{"id": 1}, "Created") },
+			http.StatusCreated, `{"data":{"id":1},"error":null,"meta":{"message":"Created"}}`},
+		{"page with cursor", func(c *gin.Context) {
+			Success(c, http.StatusOK, []int{1, 2}, &Meta{Total: &total, Limit: 2, NextCursor: "abc"})
+		}, http.StatusOK, `{"data":[1,2],"error":null,"meta":{"total":42,"limit":2,"next_cursor":"abc"}}`},
+		// total 0 tetap dikirim agar frontend bisa membedakan "kosong" dari "tidak dihitung"
+		{"last page", func(c *gin.Context) {
+			Success(c, http.StatusOK, []int{}, &Meta{Total: &zero, Page: 3, Limit: 20})
+		}, http.StatusOK, `{"data":[],"error":null,"meta":{"total":0,"page":3,"limit":20}}`},
+		{"fail", func(c *gin.Context) { Fail(c, http.StatusNotFound, CodeRoomNotFound, "Room not found") },
+			http.StatusNotFound, `{"data":null,"error":{"code":"ROOM_NOT_FOUND","message":"Room not found"}}`},
+		{"fail with details", func(c *gin.Context) {
+			FailDetails(c, http.StatusBadRequest, CodeValidationFailed, "Invalid input", gin.H{"fields": []string{"email"}})
+		}, http.StatusBadRequest, `{"data":null,"error":{"code":"VALIDATION_FAILED","message":"Invalid input","details":{"fields":["email"]}}}`},
+	} {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		tc.send(c)
+		if rec.Code != tc.status || rec.Body.String() != tc.body {
+			t.Errorf("%s: got %d %s\nwant %d %s", tc.name, rec.Code, rec.Body, tc.status, tc.body)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+			t.Errorf("%s: Content-Type %q", tc.name, ct)
+		}
+	}
+}
+
+func TestAbort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	reached := false
+	router.GET("/", func(c *gin.Context) {
+		Abort(c, http.StatusForbidden, CodeForbidden, "Forbidden")
+	}, func(c *gin.Context) { reached = true })
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if want := `{"data":null,"error":{"code":"FORBIDDEN","message":"Forbidden"}}`; rec.Code != http.StatusForbidden || rec.Body.String() != want {
+		t.Errorf("got %d %s, want 403 %s", rec.Code, rec.Body, want)
+	}
+	if reached {
+		t.Error("handler after Abort ran")
+	}
+}
@@ -1,176 +1,214 @@
 package routes
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/organisasi/kosconnectbackend/controllers"
 	"github.com/organisasi/kosconnectbackend/middlewares"
+	"github.com/organisasi/kosconnectbackend/openapi"
+	"github.com/organisasi/kosconnectbackend/store"
 )
 
-func AuthRoutes(router *gin.Engine) {
+// etag memasang ETag dan Cache-Control pada route publik. collections
+// adalah semua koleksi yang dibaca handler-nya, termasuk lewat $lookup.
+func etag(ctrl *controllers.Controller, cacheControl string, collections ...string) gin.HandlerFunc {
+	return middlewares.ETag(cacheControl, func(ctx context.Context) (string, error) {
+		return ctrl.Store.Version(ctx, collections...)
+	})
+}
+
+func AuthRoutes(router *gin.Engine, ctrl *controllers.Controller) {
 	authGroup := router.Group("/auth")
+	// Satu bucket per IP untuk register, login, refresh, email verifikasi, reset password dan googleauth
+	authLimit := ctrl.RateLimiter.Limit("auth", ctrl.Config.RateLimit.Auth)
 	{
-		authGroup.POST("/register", controllers.Register)
-		authGroup.GET("/verify", controllers.VerifyEmail)
-		authGroup.POST("/login", controllers.Login)
+		authGroup.POST("/register", authLimit, ctrl.Register)
+		authGroup.GET("/verify", ctrl.VerifyEmail)
+		authGroup.POST("/resend-verification", authLimit, ctrl.ResendVerification)
+		authGroup.POST("/login", authLimit, ctrl.Login)
+		authGroup.POST("/refresh", authLimit, ctrl.Refresh)
+		authGroup.POST("/forgot-password", authLimit, ctrl.ForgotPassword)
+		authGroup.POST("/reset-password", authLimit, ctrl.ResetForgottenPassword)
+		authGroup.POST("/logout", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.Logout)
 
 		// Tambahkan routes untuk OAuth Google
-		authGroup.GET("/google/login", controllers.HandleGoogleLogin)
-		authGroup.GET("/callback", controllers.HandleGoogleCallback)
-		authGroup.PUT("/assign-role", controllers.AssignRole)
-		authGroup.POST("/googleauth", controllers.GoogleAuth)
+		authGroup.GET("/google/login", ctrl.HandleGoogleLogin)
+		authGroup.GET("/callback", ctrl.HandleGoogleCallback)
+		authGroup.PUT("/assign-role", ctrl.AssignRole)
+		authGroup.POST("/googleauth", authLimit, ctrl.GoogleAuth)
 	}
 }
 
-func UserRoutes(router *gin.Engine) {
+func UserRoutes(router *gin.Engine, ctrl *controllers.Controller) {
 	api := router.Group("/api/users")
 	{
-		api.POST("/", middlewares.JWTAuthMiddleware(), controllers.CreateUser)                     // Admin creates a user
-		api.GET("/", middlewares.JWTAuthMiddleware(), controllers.GetAllUsers)                     // Admin views all users
-		api.GET("/owner", middlewares.JWTAuthMiddleware(), controllers.GetAllOwners)               // ambil semua data owner
-		api.GET("/:id/owner", middlewares.JWTAuthMiddleware(), controllers.GetOwnerByID)               // ambil semua data owner
-		api.GET("/me", middlewares.JWTAuthMiddleware(), controllers.GetMyAccount)                  // Logged-in user views their own account
-		api.GET("/:id", middlewares.JWTAuthMiddleware(), controllers.GetUserByID)                  // Get user by ID
-		api.PUT("/me", middlewares.JWTAuthMiddleware(), controllers.UpdateMe)                      // Update user details for user yg login
-		api.PUT("/:id", middlewares.JWTAuthMiddleware(), controllers.UpdateUser)                   // Update user details oleh admin
-		api.PUT("/:id/role", middlewares.JWTAuthMiddleware(), controllers.UpdateUserRole)          // Admin updates user role
-		api.PUT("/change-password", middlewares.JWTAuthMiddleware(), controllers.ChangePassword)   // berdasarkan pengguna yang login
-		api.PUT("/:id/reset-password", middlewares.JWTAuthMiddleware(), controllers.ResetPassword) // Admin bisa reset password pengguna lain
-		api.DELETE("/:id", middlewares.JWTAuthMiddleware(), controllers.DeleteUser)                // Delete a user
+		api.POST("/", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.CreateUser)                     // Admin creates a user
+		api.GET("/", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetAllUsers)                     // Admin views all users
+		api.GET("/owner", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetAllOwners)               // ambil semua data owner
+		api.GET("/:id/owner", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetOwnerByID)               // ambil semua data owner
+		api.GET("/me", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetMyAccount)                  // Logged-in user views their own account
+		api.GET("/:id", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetUserByID)                  // Get user by ID
+		api.PUT("/me", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.UpdateMe)                      // Update user details for user yg login
+		api.PUT("/:id", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.UpdateUser)                   // Update user details oleh admin
+		api.PUT("/:id/role", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.UpdateUserRole)          // Admin updates user role
+		api.PUT("/change-password", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.ChangePassword)   // berdasarkan pengguna yang login
+		api.PUT("/:id/reset-password", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.ResetPassword) // Admin bisa reset password pengguna lain
+		api.DELETE("/:id", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.DeleteUser)                // Delete a user
 	}
 }
 
-func CustomFacility(router *gin.Engine) {
+func CustomFacility(router *gin.Engine, ctrl *controllers.Controller) {
 	api := router.Group("/api/customFacilities")
 	{
 		// Hanya "owner" yang bisa membuat custom facility
-		api.POST("/", middlewares.JWTAuthMiddleware(), controllers.CreateCustomFacility)
+		api.POST("/", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.CreateCustomFacility)
 
 		// Semua pengguna bisa mengambil semua fasilitas
-		api.GET("/", middlewares.JWTAuthMiddleware(), controllers.GetAllCustomFacilities)
+		api.GET("/", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetAllCustomFacilities)
 
 		// Hanya "owner" atau pengguna dengan akses tertentu yang bisa mengambil fasilitas berdasarkan ID
-		api.GET("/:id", middlewares.JWTAuthMiddleware(), controllers.GetCustomFacilityByID)
+		api.GET("/:id", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetCustomFacilityByID)
 
 		// Hanya "owner" yang bisa mengupdate atau menghapus custom facility
-		api.PUT("/:id", middlewares.JWTAuthMiddleware(), controllers.UpdateCustomFacility)
-		api.DELETE("/:id", middlewares.JWTAuthMiddleware(), controllers.DeleteCustomFacility)
+		api.PUT("/:id", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.UpdateCustomFacility)
+		api.DELETE("/:id", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.DeleteCustomFacility)
 
 		// Rute untuk mengambil fasilitas khusus berdasarkan owner ID
-		api.GET("/owner", middlewares.JWTAuthMiddleware(), controllers.GetCustomFacilitiesByOwnerID)
+		api.GET("/owner", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetCustomFacilitiesByOwnerID)
 
 		// Rute untuk mengambil fasilitas khusus berdasarkan owner ID yang disimpan di query atau url disisi admin
-		api.GET("/admin", middlewares.JWTAuthMiddleware(), controllers.GetCustomFacilitiesByOwnerIDAdmin)
+		api.GET("/admin", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetCustomFacilitiesByOwnerIDAdmin)
 	}
 }
 
-func CategoryRoutes(router *gin.Engine) {
+func CategoryRoutes(router *gin.Engine, ctrl *controllers.Controller) {
 	api := router.Group("/api/categories")
 	{
-		api.GET("/", controllers.GetAllCategories)
-		api.GET("/:id", controllers.GetCategoryByID)
+		api.GET("/", etag(ctrl, ctrl.Config.HTTPCache.Categories, store.CollectionCategories), ctrl.GetAllCategories)
+		api.GET("/:id", ctrl.GetCategoryByID)
 
-		api.Use(middlewares.JWTAuthMiddleware())
+		api.Use(middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions))
 		{
-			api.POST("/", controllers.CreateCategory)
-			api.PUT("/:id", controllers.UpdateCategory)
-			api.DELETE("/:id", controllers.DeleteCategory)
+			api.POST("/", ctrl.CreateCategory)
+			api.PUT("/:id", ctrl.UpdateCategory)
+			api.DELETE("/:id", ctrl.DeleteCategory)
 		}
 	}
 }
 
-func BoardingHouse(router *gin.Engine) {
+func BoardingHouse(router *gin.Engine, ctrl *controllers.Controller) {
 	api := router.Group("/api/boardingHouses")
 	{
 		// Public route
-		api.GET("/", controllers.GetAllBoardingHouse)
-		api.GET("/:id/detail", controllers.GetBoardingHouseDetails)
-		api.GET("/:id", controllers.GetBoardingHouseByID)
+		api.GET("/", ctrl.GetAllBoardingHouse)
+		api.GET("/:id/detail", ctrl.GetBoardingHouseDetails)
+		api.GET("/:id", etag(ctrl, ctrl.Config.HTTPCache.BoardingHouse,
+			store.CollectionBoardingHouses, store.CollectionCategories, store.CollectionUsers, store.CollectionFacilities,
+		), ctrl.GetBoardingHouseByID)
 
 		// Protected routes - Requires JWT authentication
-		api.Use(middlewares.JWTAuthMiddleware())
+		api.Use(middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions))
 		{
-			api.POST("/", controllers.CreateBoardingHouse)
-			api.GET("/owner", controllers.GetBoardingHouseByOwnerID)
-			api.PUT("/:id", controllers.UpdateBoardingHouse)
-			api.DELETE("/:id", controllers.DeleteBoardingHouse)
+			api.POST("/", ctrl.CreateBoardingHouse)
+			api.GET("/owner", ctrl.GetBoardingHouseByOwnerID)
+			api.PUT("/:id", ctrl.UpdateBoardingHouse)
+			api.DELETE("/:id", ctrl.DeleteBoardingHouse)
 		}
 	}
 }
 
-func Facility(router *gin.Engine) {
+func Facility(router *gin.Engine, ctrl *controllers.Controller) {
 	api := router.Group("/api/facility")
 	{
-		api.POST("/", middlewares.JWTAuthMiddleware(), controllers.CreateFacility)
-		api.GET("/", middlewares.JWTAuthMiddleware(), controllers.GetAllFacilities)
+		api.POST("/", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.CreateFacility)
+		api.GET("/", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetAllFacilities)
 		// yg type ini buat get data fasilitas berdasarkan typenya, ada /api/facility/type?type=room dan /api/facility/type?type=boarding_house cara manggilnya
-		api.GET("/type", middlewares.JWTAuthMiddleware(), controllers.GetFacilitiesByType)
-		api.GET("/:id", middlewares.JWTAuthMiddleware(), controllers.GetFacilityByID)
-		api.PUT("/:id", middlewares.JWTAuthMiddleware(), controllers.UpdateFacility)
-		api.DELETE("/:id", middlewares.JWTAuthMiddleware(), controllers.DeleteFacility)
+		api.GET("/type", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetFacilitiesByType)
+		api.GET("/:id", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.GetFacilityByID)
+		api.PUT("/:id", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.UpdateFacility)
+		api.DELETE("/:id", middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions), ctrl.DeleteFacility)
 	}
 }
 
-func RoomRoutes(router *gin.Engine) {
+func RoomRoutes(router *gin.Engine, ctrl *controllers.Controller) {
 	// Group routes for room
 	api := router.Group("/api/rooms")
 	// Public endpoint to get room by ID
 
-	api.GET("/:id/detail", controllers.GetRoomDetailsByID)
-	api.GET("/:id/pages", controllers.GetRoomDetailPages)
-	api.GET("/home", controllers.GetRoomsForLandingPage)
+	api.GET("/:id/detail", ctrl.GetRoomDetailsByID)
+	api.GET("/:id/pages", etag(ctrl, ctrl.Config.HTTPCache.RoomPage,
+		store.CollectionRooms, store.CollectionBoardingHouses, store.CollectionUsers,
+		store.CollectionCategories, store.CollectionFacilities, store.CollectionCustomFacilities,
+	), ctrl.GetRoomDetailPages)
+	api.GET("/home", etag(ctrl, ctrl.Config.HTTPCache.LandingPage,
+		store.CollectionRooms, store.CollectionBoardingHouses, store.CollectionCategories,
+	), ctrl.GetRoomsForLandingPage)
 	// Public endpoint to get all rooms
-	api.GET("/", controllers.GetAllRooms)
+	api.GET("/", ctrl.GetAllRooms)
 
 	// Apply middleware for authorization (if needed)
-	api.Use(middlewares.JWTAuthMiddleware())
+	api.Use(middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions))
 	{
-		api.GET("/:id", controllers.GetRoomByID)
+		api.GET("/:id", ctrl.GetRoomByID)
 		// Public endpoint to get rooms by Boarding House ID
-		api.GET("/boarding-house/:id", controllers.GetRoomByBoardingHouseID)
+		api.GET("/boarding-house/:id", ctrl.GetRoomByBoardingHouseID)
 
 		// Protected endpoints for owners/admin to manage rooms
-		api.POST("/:boardingHouseID", controllers.CreateRoom)
-		api.PUT("/:id", controllers.UpdateRoom)    // Update room
-		api.DELETE("/:id", controllers.DeleteRoom) // Delete room
+		api.POST("/:boardingHouseID", ctrl.CreateRoom)
+		api.PUT("/:id", ctrl.UpdateRoom)    // Update room
+		api.DELETE("/:id", ctrl.DeleteRoom) // Delete room
 	}
 }
 
-func TransactionRoutes(router *gin.Engine) {
+func TransactionRoutes(router *gin.Engine, ctrl *controllers.Controller) {
 	// Route untuk callback dari Midtrans (publik)
-	router.POST("/midtrans/notification", controllers.PaymentNotification)
+	router.POST("/midtrans/notification", ctrl.PaymentNotification)
 
 	// Route untuk membuat pembayaran
-	router.POST("/transactions/:transaction_id/payment", controllers.CreatePayment)
+	idempotency := middlewares.Idempotency(ctrl.Store.Idempotency, ctrl.Config.IdempotencyTTL)
+	router.POST("/transactions/:transaction_id/payment", idempotency, ctrl.CreatePayment)
 
 	// Grup API dengan prefix /api/transaction
 	api := router.Group("/api/transaction")
-	api.Use(middlewares.JWTAuthMiddleware()) // Semua route dalam grup menggunakan middleware JWT
+	api.Use(middlewares.JWTAuthMiddleware(ctrl.Config, ctrl.Store.Sessions)) // Semua route dalam grup menggunakan middleware JWT
 	{
 		// Membuat transaksi baru
-		api.POST("/", controllers.CreateTransaction)
+		api.POST("/", ctrl.RateLimiter.Limit("booking", ctrl.Config.RateLimit.Booking), idempotency, ctrl.CreateTransaction)
 
 		// Mendapatkan semua transaksi (Admin dan Owner)
-		api.GET("/", controllers.GetAllTransactions)
+		api.GET("/", ctrl.GetAllTransactions)
 
 		// Mendapatkan detail transaksi berdasarkan ID
-		api.GET("/:id", controllers.GetTransactionByID)
+		api.GET("/:id", ctrl.GetTransactionByID)
 
 		// Mendapatkan transaksi milik pengguna tertentu (User)
-		api.GET("/user", controllers.GetTransactionsByUser)
-		api.GET("/admin/user/:id", controllers.GetTransactionsUserByAdmin)
+		api.GET("/user", ctrl.GetTransactionsByUser)
+		api.GET("/admin/user/:id", ctrl.GetTransactionsUserByAdmin)
 
 		// Mendapatkan transaksi milik owner tertentu (Owner)
-		api.GET("/owner", controllers.GetTransactionsByOwner)
-		api.GET("/admin/owner/:id", controllers.GetTransactionsOwnerByAdmin)
+		api.GET("/owner", ctrl.GetTransactionsByOwner)
+		api.GET("/admin/owner/:id", ctrl.GetTransactionsOwnerByAdmin)
 
 		// Mendapatkan transaksi berdasarkan status pembayaran (Pending, Paid, etc.)
-		api.GET("/status/:status", controllers.GetTransactionsByPaymentStatus)
+		api.GET("/status/:status", ctrl.GetTransactionsByPaymentStatus)
 
 		// Memperbarui status pembayaran transaksi (misalnya: Paid, Cancelled, dll.)
-		api.PUT("/:id/payment-status", controllers.UpdateTransaction)
+		api.PUT("/:id/payment-status", ctrl.UpdateTransaction)
 
 		// Menghapus transaksi (opsional, hanya untuk admin)
-		api.DELETE("/:id", controllers.DeleteTransaction)
+		api.DELETE("/:id", ctrl.DeleteTransaction)
 	}
 }
 
+
+func HealthRoutes(router *gin.Engine, ctrl *controllers.Controller) {
+	router.GET("/healthz", ctrl.Healthz) // Liveness
+	router.GET("/readyz", ctrl.Readyz)   // Readiness: MongoDB, Midtrans, GitHub
+}
+
+// Dokumentasi API: dokumen OpenAPI dan Swagger UI
+func DocsRoutes(router *gin.Engine, ctrl *controllers.Controller) {
+	router.GET("/openapi.json", openapi.Handler(ctrl.Config.BaseURL))
+	router.GET("/docs", openapi.SwaggerUI("/openapi.json"))
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/organisasi/kosconnectbackend/fixtures"
+)
+
+// TestAggregationCache memeriksa bahwa landing page dibaca dari cache dan
+// cache dikosongkan saat kategori diubah lewat API.
+func TestAggregationCache(t *testing.T) {
+	s := newTestServer(t)
+	token := s.token(fixtures.ID("admin"), "admin")
+
+	// Hit dibaca dari /metrics, tempat counter ini diekspos
+	hits := func() float64 {
+		t.Helper()
+		const series = `kosconnect_cache_requests_total{result="hit",view="room_landing_page"} `
+		for _, line := range strings.Split(s.do(http.MethodGet, "/metrics", "", "", http.StatusOK).Body.String(), "\n") {
+			if value, ok := strings.CutPrefix(line, series); ok {
+				var n float64
+				fmt.Sscan(value, &n)
+				return n
+			}
+		}
+		return 0
+	}
+
+	const home = "/api/rooms/home?sort=category_name&limit=100"
+	before := hits()
+	s.do(http.MethodGet, home, "", "", http.StatusOK)
+	s.do(http.MethodGet, home, "", "", http.StatusOK)
+	if got := hits() - before; got != 1 {
+		t.Errorf("landing page cache hits = %v, want 1", got)
+	}
+
+	s.do(http.MethodPut, "/api/categories/"+fixtures.ID("category-putra").Hex(), token, `{"name":"Putra Eksklusif"}`, http.StatusOK)
+	var page struct {
+		Data []struct {
+			CategoryName string `json:"category_name"`
+		} `json:"data"`
+	}
+	decode(t, s.do(http.MethodGet, home, "", "", http.StatusOK), &page)
+	renamed := false
+	for _, room := range page.Data {
+		renamed = renamed || room.CategoryName == "Putra Eksklusif"
+	}
+	if !renamed {
+		t.Error("landing page still shows the old category name after the update")
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/fixtures"
+	"github.com/organisasi/kosconnectbackend/store"
+)
+
+// TestEmailVerification memeriksa login user yang belum verifikasi, hasil
+// redirect /auth/verify dan pembatasan /auth/resend-verification.
+func TestEmailVerification(t *testing.T) {
+	s := newTestServer(t, noRateLimit, func(cfg *config.Config, _ *store.Stores) {
+		cfg.SMTP.Host, cfg.SMTP.Port = "127.0.0.1", 1 // Email gagal terkirim tanpa menunggu jaringan
+	})
+	ctx := context.Background()
+	userID := fixtures.ID("user-rizky")
+
+	login := func(want int) string {
+		t.Helper()
+		return s.do(http.MethodPost, "/auth/login", "", `{"email":"rizky@kosconnect.test","password":"password123"}`, want).Body.String()
+	}
+	verify := func(token, want string) {
+		t.Helper()
+		location := s.do(http.MethodGet, "/auth/verify?token="+token, "", "", http.StatusFound).Header().Get("Location")
+		if !strings.HasSuffix(location, "/login?"+want) {
+			t.Errorf("verify %q redirects to %s, want ?%s", token, location, want)
+		}
+	}
+	resend := func(email string) string {
+		t.Helper()
+		return s.do(http.MethodPost, "/auth/resend-verification", "", `{"email":"`+email+`"}`, http.StatusOK).Body.String()
+	}
+	token := func() string {
+		t.Helper()
+		user, err := s.stores.Users.FindByID(ctx, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return user.VerificationToken
+	}
+
+	if body := login(http.StatusForbidden); !strings.Contains(body, "EMAIL_NOT_VERIFIED") {
+		t.Errorf("login before verification: %s", body)
+	}
+	s.cfg.Auth.RequireVerifiedEmail = false
+	login(http.StatusOK)
+	s.cfg.Auth.RequireVerifiedEmail = true
+
+	if err := s.stores.Users.SetVerificationToken(ctx, userID, "expired-token", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	verify("expired-token", "verified=false&reason=expired")
+	verify("unknown-token", "verified=false&reason=invalid")
+
+	// Email verifikasi baru saja dikirim: resend tidak mengganti token
+	if known, unknown := resend("rizky@kosconnect.test"), resend("nobody@kosconnect.test"); known != unknown {
+		t.Errorf("resend-verification reveals registered emails:\n%s\n%s", known, unknown)
+	}
+	if token() != "expired-token" {
+		t.Fatal("resend-verification ignored verification_resend_interval")
+	}
+	s.cfg.Auth.VerificationResendInterval = 0
+	resend("rizky@kosconnect.test")
+	fresh := token()
+	if fresh == "expired-token" {
+		t.Fatal("resend-verification did not issue a new token")
+	}
+
+	verify(fresh, "verified=true")
+	verify(fresh, "verified=true&reason=already_verified")
+	login(http.StatusOK)
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestErrorEnvelope memastikan route yang tidak ada dan error dari
+// middleware memakai envelope yang sama dengan handler.
+func TestErrorEnvelope(t *testing.T) {
+	s := newTestServer(t)
+	for _, tc := range []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{http.MethodGet, "/api/tidak-ada", http.StatusNotFound, `{"data":null,"error":{"code":"ROUTE_NOT_FOUND","message":"Route not found"}}`},
+		{http.MethodDelete, "/healthz", http.StatusNotFound, `{"data":null,"error":{"code":"ROUTE_NOT_FOUND","message":"Route not found"}}`},
+		{http.MethodGet, "/api/users/me", http.StatusUnauthorized, `{"data":null,"error":{"code":"UNAUTHORIZED"`},
+	} {
+		rec := s.do(tc.method, tc.path, "", "", tc.status)
+		if !strings.HasPrefix(rec.Body.String(), tc.body) {
+			t.Errorf("%s %s = %s, want %s", tc.method, tc.path, rec.Body, tc.body)
+		}
+	}
+}
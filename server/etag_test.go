@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/fixtures"
+	"github.com/organisasi/kosconnectbackend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestETag memeriksa revalidasi If-None-Match pada endpoint publik:
+// 304 selama data tidak berubah, ETag baru setelah data berubah, dan
+// response error tidak membawa ETag.
+func TestETag(t *testing.T) {
+	s := newTestServer(t)
+	cfg, stores := s.cfg, s.stores
+
+	get := func(path, ifNoneMatch string, want int) *httptest.ResponseRecorder {
+		t.Helper()
+		req := s.request(http.MethodGet, path, "", "")
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		return s.send(req, want)
+	}
+
+	for path, cacheControl := range map[string]string{
+		"/api/rooms/home": cfg.HTTPCache.LandingPage,
+		"/api/rooms/" + fixtures.ID("room-melati-a").Hex() + "/pages": cfg.HTTPCache.RoomPage,
+		"/api/boardingHouses/" + fixtures.ID("kos-melati").Hex():      cfg.HTTPCache.BoardingHouse,
+		"/api/categories/": cfg.HTTPCache.Categories,
+	} {
+		first := get(path, "", http.StatusOK)
+		etag := first.Header().Get("ETag")
+		if !strings.HasPrefix(etag, `W/"`) {
+			t.Fatalf("GET %s: ETag %q, want a weak ETag", path, etag)
+		}
+		if got := first.Header().Get("Cache-Control"); got != cacheControl {
+			t.Errorf("GET %s: Cache-Control %q, want %q", path, got, cacheControl)
+		}
+		notModified := get(path, `"other", `+etag, http.StatusNotModified)
+		if notModified.Body.Len() != 0 || notModified.Header().Get("ETag") != etag {
+			t.Errorf("GET %s: 304 with body %q and ETag %q", path, notModified.Body, notModified.Header().Get("ETag"))
+		}
+	}
+
+	home := get("/api/rooms/home", "", http.StatusOK).Header().Get("ETag")
+	if other := get("/api/rooms/home?sort=room_name", "", http.StatusOK).Header().Get("ETag"); other == home {
+		t.Error("different query strings share an ETag")
+	}
+
+	categories := get("/api/categories/", "", http.StatusOK).Header().Get("ETag")
+	if err := stores.Categories.Create(context.Background(), &models.Category{
+		CategoryID: primitive.NewObjectID(),
+		Name:       "Eksklusif",
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if rec := get("/api/categories/", categories, http.StatusOK); rec.Header().Get("ETag") == categories {
+		t.Error("ETag unchanged after a category was created")
+	}
+
+	// Delete lalu create: jumlah dokumen dan updated_at terbaru sama seperti sebelumnya
+	all, err := stores.Categories.FindAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	newest := slices.MaxFunc(all, func(a, b models.Category) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+	categories = get("/api/categories/", "", http.StatusOK).Header().Get("ETag")
+	if err := stores.Categories.Delete(context.Background(), newest.CategoryID); err != nil {
+		t.Fatal(err)
+	}
+	if err := stores.Categories.Create(context.Background(), &models.Category{
+		CategoryID: primitive.NewObjectID(),
+		Name:       "Pengganti",
+		CreatedAt:  newest.CreatedAt,
+		UpdatedAt:  newest.UpdatedAt,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if rec := get("/api/categories/", categories, http.StatusOK); rec.Header().Get("ETag") == categories {
+		t.Error("ETag unchanged after a category was deleted and another created")
+	}
+
+	missing := get("/api/rooms/"+primitive.NewObjectID().Hex()+"/pages", "", http.StatusNotFound)
+	if missing.Header().Get("ETag") != "" || missing.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("404: ETag %q, Cache-Control %q; want no ETag and no-store", missing.Header().Get("ETag"), missing.Header().Get("Cache-Control"))
+	}
+}
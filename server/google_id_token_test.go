@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/store"
+)
+
+// TestGoogleAuthIDToken memeriksa /auth/googleauth dengan ID token yang
+// ditandatangani key lokal dan disajikan lewat JWKS lokal.
+func TestGoogleAuthIDToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forger, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"keys":[{"kid":"test","kty":"RSA","n":%q,"e":"AQAB"}]}`, base64.RawURLEncoding.EncodeToString(key.N.Bytes()))
+	}))
+	defer jwks.Close()
+
+	s := newTestServer(t, noRateLimit, func(cfg *config.Config, _ *store.Stores) {
+		cfg.Google.ClientID = "test-client"
+		cfg.Google.CertsURL = jwks.URL
+	})
+
+	idToken := func(signer *rsa.PrivateKey, email string) string {
+		t.Helper()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            "https://accounts.google.com",
+			"aud":            "test-client",
+			"sub":            email,
+			"email":          email,
+			"email_verified": true,
+			"name":           "Google User",
+			"iat":            time.Now().Unix(),
+			"exp":            time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = "test"
+		signed, err := token.SignedString(signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	googleAuth := func(body string, want int) (role string) {
+		t.Helper()
+		var resp struct {
+			Data struct {
+				Role string `json:"role"`
+			} `json:"data"`
+		}
+		rec := s.do(http.MethodPost, "/auth/googleauth", "", body, want)
+		if want == http.StatusOK {
+			decode(t, rec, &resp)
+		}
+		return resp.Data.Role
+	}
+
+	// Email saja tidak cukup lagi
+	googleAuth(`{"email":"admin@kosconnect.test","role":"admin"}`, http.StatusBadRequest)
+	googleAuth(`{"id_token":"`+idToken(forger, "admin@kosconnect.test")+`"}`, http.StatusUnauthorized)
+	googleAuth(`{"id_token":"`+idToken(key, "new@kosconnect.test")+`","role":"admin"}`, http.StatusBadRequest)
+
+	if role := googleAuth(`{"id_token":"`+idToken(key, "admin@kosconnect.test")+`","role":"user"}`, http.StatusOK); role != "admin" {
+		t.Errorf("existing admin logged in with role %q", role)
+	}
+
+	if role := googleAuth(`{"id_token":"`+idToken(key, "new@kosconnect.test")+`","role":"owner"}`, http.StatusOK); role != "owner" {
+		t.Errorf("new user got role %q, want owner", role)
+	}
+	user, err := s.stores.Users.FindByEmail(context.Background(), "new@kosconnect.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.VerifiedEmail || user.FullName != "Google User" || user.Password != "" {
+		t.Errorf("created user = %+v", user)
+	}
+	if role := googleAuth(`{"id_token":"`+idToken(key, "norole@kosconnect.test")+`"}`, http.StatusOK); role != "" {
+		t.Errorf("user without a chosen role got %q", role)
+	}
+}
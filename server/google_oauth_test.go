@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// googleStub menggantikan token endpoint dan userinfo Google. Token hanya
+// diberikan jika code_verifier cocok dengan code_challenge dari login.
+type googleStub struct {
+	t         *testing.T
+	challenge string
+	exchanges int
+}
+
+func (g *googleStub) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := `{"email":"admin@kosconnect.test","name":"Admin","verified_email":true}`
+	if req.URL.Host == "oauth2.googleapis.com" {
+		g.exchanges++
+		req.ParseForm()
+		sum := sha256.Sum256([]byte(req.PostForm.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+			g.t.Errorf("code_verifier does not match code_challenge %q", g.challenge)
+		}
+		body = `{"access_token":"access","token_type":"Bearer","expires_in":3600}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// TestGoogleOAuthState memeriksa state per request, PKCE dan penolakan
+// callback yang diputar ulang.
+func TestGoogleOAuthState(t *testing.T) {
+	s := newTestServer(t)
+	google := &googleStub{t: t}
+	client := &http.Client{Transport: google}
+
+	login := func() (state string, cookie *http.Cookie) {
+		t.Helper()
+		rec := s.do(http.MethodGet, "/auth/google/login", "", "", http.StatusTemporaryRedirect)
+		location, err := url.Parse(rec.Header().Get("Location"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := location.Query()
+		if q.Get("code_challenge_method") != "S256" || q.Get("state") == "" {
+			t.Fatalf("login redirect without state or PKCE: %s", location)
+		}
+		google.challenge = q.Get("code_challenge")
+		for _, c := range rec.Result().Cookies() {
+			if c.Name == "oauthState" {
+				cookie = c
+			}
+		}
+		if cookie == nil || !cookie.HttpOnly || cookie.Path != "/auth" {
+			t.Fatalf("oauthState cookie = %+v", cookie)
+		}
+		return q.Get("state"), cookie
+	}
+	callback := func(state string, cookie *http.Cookie, want int) {
+		t.Helper()
+		req := s.request(http.MethodGet, "/auth/callback?code=code&state="+url.QueryEscape(state), "", "")
+		req = req.WithContext(context.WithValue(req.Context(), oauth2.HTTPClient, client))
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		s.send(req, want)
+	}
+
+	first, firstCookie := login()
+	second, secondCookie := login()
+	if first == second || firstCookie.Value == secondCookie.Value {
+		t.Fatal("state is reused between logins")
+	}
+
+	callback(first, nil, http.StatusBadRequest)
+	callback(second, firstCookie, http.StatusBadRequest)
+	forged := *secondCookie
+	forged.Value = secondCookie.Value[:len(secondCookie.Value)-2] + "xx"
+	callback(second, &forged, http.StatusBadRequest)
+	if google.exchanges != 0 {
+		t.Fatalf("code exchanged %d times before state was validated", google.exchanges)
+	}
+
+	callback(second, secondCookie, http.StatusFound)
+	callback(second, secondCookie, http.StatusBadRequest)
+	if google.exchanges != 1 {
+		t.Fatalf("code exchanged %d times, want 1", google.exchanges)
+	}
+}
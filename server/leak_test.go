@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/openapi"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	leakPasswordHash = "$2a$10$leak-test-password-hash"
+	leakVerifyToken  = "leak-test-verification-token"
+)
+
+// seedLeakData mengisi store dengan satu data untuk setiap koleksi. Semua
+// user punya password dan verification_token yang nilainya dicari di response.
+func seedLeakData(t *testing.T, stores *store.Stores) map[string]primitive.ObjectID {
+	t.Helper()
+	ctx := context.Background()
+	ids := map[string]primitive.ObjectID{}
+	for _, role := range []string{"admin", "owner", "user"} {
+		user := models.User{
+			UserID:            primitive.NewObjectID(),
+			FullName:          "Leak " + role,
+			Email:             role + "@example.com",
+			Role:              role,
+			Password:          leakPasswordHash,
+			VerificationToken: leakVerifyToken,
+			IsRoleAssigned:    true,
+		}
+		if err := stores.Users.Create(ctx, &user); err != nil {
+			t.Fatal(err)
+		}
+		ids[role] = user.UserID
+	}
+
+	category := models.Category{CategoryID: primitive.NewObjectID(), Name: "Campur"}
+	facility := models.Facility{FacilityID: primitive.NewObjectID(), Name: "WiFi", Type: "room"}
+	custom := models.CustomFacility{CustomFacilityID: primitive.NewObjectID(), Name: "Laundry", Price: 50000, OwnerID: ids["owner"]}
+	house := models.BoardingHouse{
+		BoardingHouseID: primitive.NewObjectID(),
+		OwnerID:         ids["owner"],
+		CategoryID:      category.CategoryID,
+		Name:            "Kos Leak",
+		Facilities:      []primitive.ObjectID{facility.FacilityID},
+	}
+	room := models.Room{
+		RoomID:           primitive.NewObjectID(),
+		BoardingHouseID:  house.BoardingHouseID,
+		RoomType:         "A",
+		Price:            models.RoomPrice{Monthly: 1000000},
+		RoomFacilities:   []primitive.ObjectID{facility.FacilityID},
+		CustomFacilities: []primitive.ObjectID{custom.CustomFacilityID},
+		NumberAvailable:  2,
+	}
+	transaction := models.Transaction{
+		TransactionID:   primitive.NewObjectID(),
+		TransactionCode: "KCTLEAK",
+		UserID:          ids["user"],
+		OwnerID:         ids["owner"],
+		BoardingHouseID: house.BoardingHouseID,
+		RoomID:          room.RoomID,
+		PaymentStatus:   "pending",
+	}
+	for _, err := range []error{
+		stores.Categories.Create(ctx, &category),
+		stores.Facilities.Create(ctx, &facility),
+		stores.CustomFacilities.Create(ctx, &custom),
+		stores.BoardingHouses.Create(ctx, &house),
+		stores.Rooms.Create(ctx, &room),
+		stores.Transactions.Create(ctx, &transaction),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	ids["category"] = category.CategoryID
+	ids["facility"] = facility.FacilityID
+	ids["custom_facility"] = custom.CustomFacilityID
+	ids["boarding_house"] = house.BoardingHouseID
+	ids["room"] = room.RoomID
+	ids["transaction"] = transaction.TransactionID
+	return ids
+}
+
+// TestNoEndpointLeaksSecrets memanggil setiap route GET dengan setiap ID yang
+// ada sebagai parameter path, sebagai anonim, user, owner dan admin, lalu
+// memastikan password dan verification_token tidak pernah ikut di response.
+func TestNoEndpointLeaksSecrets(t *testing.T) {
+	var ids map[string]primitive.ObjectID
+	s := newTestServer(t, func(_ *config.Config, stores *store.Stores) { ids = seedLeakData(t, stores) })
+
+	tokens := map[string]string{"anonymous": ""}
+	for _, role := range []string{"admin", "owner", "user"} {
+		tokens[role] = s.token(ids[role], role)
+	}
+
+	forbidden := []string{`"password"`, `"verification_token"`, leakPasswordHash, leakVerifyToken}
+	succeeded := map[string]bool{}
+	for _, route := range s.router.Routes() {
+		// Dokumen OpenAPI memuat nama field password di schema request; diperiksa terpisah di bawah
+		if route.Method != http.MethodGet || route.Path == "/openapi.json" {
+			continue
+		}
+		for _, path := range expandParams(route.Path, ids) {
+			for audience, token := range tokens {
+				rec := s.do(http.MethodGet, path+"?owner_id="+ids["owner"].Hex()+"&type=room", token, "", anyStatus)
+				body := rec.Body.String()
+				for _, secret := range forbidden {
+					if strings.Contains(body, secret) {
+						t.Errorf("GET %s as %s: response contains %s\n%s", path, audience, secret, body)
+					}
+				}
+				if rec.Code == http.StatusOK {
+					succeeded[route.Path] = true
+				}
+			}
+		}
+	}
+
+	// Pastikan endpoint user benar-benar mengembalikan data, bukan hanya error
+	for _, path := range []string{"/api/users/", "/api/users/me", "/api/users/:id", "/api/users/owner", "/api/rooms/:id/pages", "/api/rooms/:id/detail", "/api/boardingHouses/:id/detail"} {
+		if !succeeded[path] {
+			t.Errorf("GET %s never returned 200; the leak check did not exercise it", path)
+		}
+	}
+}
+
+// TestMutationsDoNotLeakSecrets memeriksa response endpoint non-GET yang
+// membaca atau menulis user: login, register, dan create/update user oleh
+// admin maupun user sendiri.
+func TestMutationsDoNotLeakSecrets(t *testing.T) {
+	var ids map[string]primitive.ObjectID
+	s := newTestServer(t, func(cfg *config.Config, stores *store.Stores) {
+		cfg.SMTP.Host, cfg.SMTP.Port = fakeSMTP(t)
+		ids = seedLeakData(t, stores)
+	})
+
+	// User yang bisa login: password asli, token verifikasi masih tersimpan
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member := models.User{
+		UserID:            primitive.NewObjectID(),
+		FullName:          "Leak Member",
+		Email:             "member@example.com",
+		Role:              "user",
+		Password:          string(hash),
+		VerificationToken: leakVerifyToken,
+		VerifiedEmail:     true,
+		IsRoleAssigned:    true,
+	}
+	if err := s.stores.Users.Create(context.Background(), &member); err != nil {
+		t.Fatal(err)
+	}
+	admin := s.token(ids["admin"], "admin")
+	self := s.token(member.UserID, "user")
+
+	forbidden := []string{`"password"`, `"verification_token"`, leakPasswordHash, leakVerifyToken, string(hash), "$2a$"}
+	for _, tc := range []struct {
+		method, path, token, body string
+	}{
+		{http.MethodPost, "/auth/login", "", `{"email":"member@example.com","password":"password123"}`},
+		{http.MethodPost, "/auth/register", "", `{"fullname":"Baru","email":"baru@example.com","password":"password123"}`},
+		{http.MethodPost, "/api/users/", admin, `{"fullname":"Dibuat Admin","email":"dibuat@example.com","password":"password123","role":"owner"}`},
+		{http.MethodPut, "/api/users/" + ids["owner"].Hex(), admin, `{"fullname":"Owner Diubah"}`},
+		{http.MethodPut, "/api/users/" + member.UserID.Hex(), self, `{"fullname":"Member Diubah"}`},
+		{http.MethodPut, "/api/users/" + ids["user"].Hex() + "/role", admin, `{"role":"owner"}`},
+	} {
+		rec := s.do(tc.method, tc.path, tc.token, tc.body, anyStatus)
+		if rec.Code != http.StatusOK && rec.Code != http.StatusCreated {
+			t.Errorf("%s %s = %d, want success so the response is checked\n%s", tc.method, tc.path, rec.Code, rec.Body)
+		}
+		for _, secret := range forbidden {
+			if strings.Contains(rec.Body.String(), secret) {
+				t.Errorf("%s %s: response contains %s\n%s", tc.method, tc.path, secret, rec.Body)
+			}
+		}
+	}
+}
+
+// TestSpecResponsesHaveNoSecrets memastikan tidak ada schema response di
+// dokumen OpenAPI yang memuat field password atau verification_token.
+func TestSpecResponsesHaveNoSecrets(t *testing.T) {
+	doc := openapi.Spec()
+	raw, err := json.Marshal(doc.Components.Schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var components map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &components); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, item := range doc.Paths {
+		for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			for status, resp := range op.Responses {
+				for _, media := range resp.Content {
+					body, err := json.Marshal(media.Schema)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if field := secretField(string(body), components, map[string]bool{}); field != "" {
+						t.Errorf("%s %s response %s exposes %s", method, path, status, field)
+					}
+				}
+			}
+		}
+	}
+}
+
+// secretField mencari property password/verification_token di schema,
+// termasuk schema yang dirujuk lewat $ref.
+func secretField(schema string, components map[string]json.RawMessage, seen map[string]bool) string {
+	for _, field := range []string{`"password":`, `"verification_token":`} {
+		if strings.Contains(schema, field) {
+			return strings.TrimSuffix(field, ":")
+		}
+	}
+	for name, component := range components {
+		if seen[name] || !strings.Contains(schema, `"#/components/schemas/`+name+`"`) {
+			continue
+		}
+		seen[name] = true
+		if field := secretField(string(component), components, seen); field != "" {
+			return field
+		}
+	}
+	return ""
+}
+
+// expandParams mengganti setiap parameter path dengan setiap ID hasil seed.
+func expandParams(path string, ids map[string]primitive.ObjectID) []string {
+	paths := []string{path}
+	for _, segment := range strings.Split(path, "/") {
+		if !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+			continue
+		}
+		var next []string
+		for _, p := range paths {
+			if segment == ":status" {
+				next = append(next, strings.Replace(p, segment, "pending", 1))
+				continue
+			}
+			for _, id := range ids {
+				next = append(next, strings.Replace(p, segment, id.Hex(), 1))
+			}
+		}
+		paths = next
+	}
+	return paths
+}
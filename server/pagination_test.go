@@ -0,0 +1,153 @@
+package server
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/organisasi/kosconnectbackend/fixtures"
+	"github.com/organisasi/kosconnectbackend/models"
+)
+
+// TestListPagination menelusuri endpoint list dengan cursor dan page di
+// atas dataset fixtures: setiap data muncul tepat sekali, urutan sesuai
+// sort, dan total mengikuti filter.
+func TestListPagination(t *testing.T) {
+	s := newTestServer(t)
+	token := s.token(fixtures.ID("admin"), "admin")
+
+	type page struct {
+		Data []map[string]any `json:"data"`
+		Meta struct {
+			Total      int64  `json:"total"`
+			Page       int    `json:"page"`
+			NextCursor string `json:"next_cursor"`
+		} `json:"meta"`
+	}
+	get := func(path string, want int) page {
+		t.Helper()
+		var p page
+		decode(t, s.do(http.MethodGet, path, token, "", want), &p)
+		return p
+	}
+
+	for _, tc := range []struct {
+		path, id, sortKey string
+		desc              bool
+		total             int64
+	}{
+		{"/api/transaction/?limit=4", "transaction_id", "created_at", true, 10},
+		{"/api/transaction/?limit=3&sort=total&payment_status=pending", "transaction_id", "total", false, 2},
+		{"/api/rooms/home?limit=4&sort=room_name", "room_id", "room_name", false, 6},
+		{"/api/users/?limit=3&sort=-fullname", "user_id", "fullname", true, 8},
+		{"/api/users/owner?limit=2", "user_id", "fullname", false, 3},
+	} {
+		seen := map[any]bool{}
+		var last any
+		for path := tc.path; path != ""; {
+			p := get(path, http.StatusOK)
+			if p.Meta.Total != tc.total {
+				t.Errorf("GET %s: total %d, want %d", path, p.Meta.Total, tc.total)
+			}
+			for _, item := range p.Data {
+				if seen[item[tc.id]] {
+					t.Errorf("GET %s: %v returned twice", path, item[tc.id])
+				}
+				seen[item[tc.id]] = true
+				value := item[tc.sortKey]
+				if last != nil && value != nil && value != last && before(value, last) != tc.desc {
+					t.Errorf("GET %s: %s %v after %v is out of order", path, tc.sortKey, value, last)
+				}
+				last = value
+			}
+			path = ""
+			if p.Meta.NextCursor != "" {
+				path = tc.path + "&cursor=" + p.Meta.NextCursor
+			}
+		}
+		if int64(len(seen)) != tc.total {
+			t.Errorf("GET %s: walked %d items, want %d", tc.path, len(seen), tc.total)
+		}
+	}
+
+	if p := get("/api/transaction/?limit=4&page=3", http.StatusOK); len(p.Data) != 2 || p.Meta.Page != 3 || p.Meta.NextCursor != "" {
+		t.Errorf("page 3: %d items, page %d, next_cursor %q; want 2 items on the last page", len(p.Data), p.Meta.Page, p.Meta.NextCursor)
+	}
+	for _, path := range []string{
+		"/api/transaction/?sort=password",
+		"/api/transaction/?limit=1000",
+		"/api/transaction/?cursor=not-a-cursor",
+		"/api/transaction/?user_id=123",
+	} {
+		get(path, http.StatusBadRequest)
+	}
+}
+
+// TestCreatedRoomIsPaged membuat kamar lewat API lalu memastikan kamar itu
+// ikut terlihat saat list kamar ditelusuri dengan cursor, termasuk urutan
+// number_available yang field-nya tidak tertulis untuk kamar penuh.
+func TestCreatedRoomIsPaged(t *testing.T) {
+	s := newTestServer(t)
+
+	var body strings.Builder
+	form := multipart.NewWriter(&body)
+	for field, value := range map[string]string{
+		"room_type":         "Tipe Baru",
+		"price_monthly":     "900000",
+		"number_available":  "0",
+		"room_facilities":   "[]",
+		"custom_facilities": "[]",
+	} {
+		form.WriteField(field, value)
+	}
+	form.Close()
+	req := s.request(http.MethodPost, "/api/rooms/"+fixtures.ID("kos-melati").Hex(), s.token(fixtures.ID("admin"), "admin"), body.String())
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	var created struct {
+		Data struct {
+			Room models.Room `json:"room"`
+		} `json:"data"`
+	}
+	decode(t, s.send(req, http.StatusCreated), &created)
+	room := created.Data.Room
+	if room.CreatedAt.IsZero() || room.UpdatedAt.IsZero() {
+		t.Errorf("created room has no timestamps: created_at %v, updated_at %v", room.CreatedAt, room.UpdatedAt)
+	}
+
+	for _, sort := range []string{"-created_at", "created_at", "number_available", "-number_available"} {
+		found := false
+		path := "/api/rooms/?limit=2&sort=" + sort
+		for next := path; next != ""; {
+			var page struct {
+				Data []models.Room `json:"data"`
+				Meta struct {
+					NextCursor string `json:"next_cursor"`
+				} `json:"meta"`
+			}
+			decode(t, s.do(http.MethodGet, next, "", "", http.StatusOK), &page)
+			for _, item := range page.Data {
+				found = found || item.RoomID == room.RoomID
+			}
+			next = ""
+			if page.Meta.NextCursor != "" {
+				next = path + "&cursor=" + page.Meta.NextCursor
+			}
+		}
+		if !found {
+			t.Errorf("GET %s: created room %s never returned", path, room.RoomID.Hex())
+		}
+	}
+}
+
+// before membandingkan nilai JSON: angka sebagai angka, selain itu sebagai
+// string (tanggal RFC 3339 terurut sebagai string).
+func before(a, b any) bool {
+	x, okA := a.(float64)
+	y, okB := b.(float64)
+	if okA && okB {
+		return x < y
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/fixtures"
+	"github.com/organisasi/kosconnectbackend/helper"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestPasswordReset memeriksa bahwa forgot-password tidak membedakan email
+// terdaftar, token reset hanya bisa dipakai sekali dan reset mencabut
+// semua session.
+func TestPasswordReset(t *testing.T) {
+	s := newTestServer(t, noRateLimit, func(cfg *config.Config, _ *store.Stores) {
+		cfg.SMTP.Host, cfg.SMTP.Port = "127.0.0.1", 1 // Email gagal terkirim tanpa menunggu jaringan
+	})
+	adminID := fixtures.ID("admin")
+
+	newReset := func(token string, expiresAt time.Time) {
+		t.Helper()
+		reset := models.PasswordReset{ID: primitive.NewObjectID(), UserID: adminID, TokenHash: helper.HashToken(token), CreatedAt: time.Now(), ExpiresAt: expiresAt}
+		if err := s.stores.PasswordResets.Create(context.Background(), &reset); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reset := func(token string, want int) {
+		t.Helper()
+		s.do(http.MethodPost, "/auth/reset-password", "", `{"token":"`+token+`","new_password":"newpassword123"}`, want)
+	}
+	forgot := func(email string) string {
+		t.Helper()
+		return s.do(http.MethodPost, "/auth/forgot-password", "", `{"email":"`+email+`"}`, http.StatusOK).Body.String()
+	}
+	login := func(password string, want int) {
+		t.Helper()
+		s.do(http.MethodPost, "/auth/login", "", `{"email":"admin@kosconnect.test","password":"`+password+`"}`, want)
+	}
+
+	// Permintaan baru membatalkan link sebelumnya
+	newReset("older", time.Now().Add(time.Hour))
+	if known, unknown := forgot("admin@kosconnect.test"), forgot("nobody@kosconnect.test"); known != unknown {
+		t.Errorf("forgot-password reveals registered emails:\n%s\n%s", known, unknown)
+	}
+	reset("older", http.StatusBadRequest)
+
+	newReset("expired", time.Now().Add(-time.Minute))
+	reset("expired", http.StatusBadRequest)
+	reset("unknown", http.StatusBadRequest)
+
+	session := s.token(adminID, "admin")
+	s.do(http.MethodGet, "/api/users/me", session, "", http.StatusOK)
+	newReset("valid", time.Now().Add(time.Hour))
+	s.do(http.MethodPost, "/auth/reset-password", "", `{"token":"valid","new_password":"short"}`, http.StatusBadRequest)
+	reset("valid", http.StatusOK)
+	reset("valid", http.StatusBadRequest)
+	s.do(http.MethodGet, "/api/users/me", session, "", http.StatusUnauthorized)
+
+	login("password123", http.StatusUnauthorized)
+	login("newpassword123", http.StatusOK)
+}
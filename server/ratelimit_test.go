@@ -0,0 +1,23 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// TestRateLimitUsesConnectionIP memastikan X-Forwarded-For palsu tidak
+// membuat bucket rate limit baru dengan config bawaan (tanpa trusted proxy).
+func TestRateLimitUsesConnectionIP(t *testing.T) {
+	s := newTestServer(t)
+
+	burst := s.cfg.RateLimit.Auth.Burst
+	for i := 0; i <= burst; i++ {
+		req := s.request(http.MethodPost, "/auth/login", "", `{}`)
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i+1))
+		rec := s.send(req, anyStatus)
+		if limited := rec.Code == http.StatusTooManyRequests; limited != (i == burst) {
+			t.Fatalf("request %d with X-Forwarded-For %s = %d", i+1, req.Header.Get("X-Forwarded-For"), rec.Code)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package server merakit gin engine KosConnect. Engine dibangun sekali saat
+// startup lalu dipakai ulang oleh entrypoint Vercel (main.go) dan binary
+// cmd/server.
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/organisasi/kosconnectbackend/cache"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/controllers"
+	"github.com/organisasi/kosconnectbackend/metrics"
+	"github.com/organisasi/kosconnectbackend/middlewares"
+	"github.com/organisasi/kosconnectbackend/response"
+	"github.com/organisasi/kosconnectbackend/routes"
+	"github.com/organisasi/kosconnectbackend/store"
+	"github.com/organisasi/kosconnectbackend/validation"
+)
+
+// NewLogger membuat logger JSON dengan level dari config (log_level).
+// Entrypoint memasangnya dengan slog.SetDefault sebelum memanggil NewServer.
+func NewLogger(w io.Writer, level string) *slog.Logger {
+	var lvl slog.Level
+	lvl.UnmarshalText([]byte(level)) // Sudah divalidasi oleh Config.Validate
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl}))
+}
+
+// NewServer membuat router dengan middleware dan semua route terdaftar.
+// Log request ditulis lewat slog.Default, lihat NewLogger.
+func NewServer(cfg *config.Config, stores *store.Stores) *gin.Engine {
+	validation.Setup()
+
+	router := gin.New()
+	// IP client untuk rate limit dan log hanya diambil dari X-Forwarded-For
+	// jika dikirim proxy yang dipercaya; daftar sudah divalidasi Config.Validate
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		slog.Error("invalid trusted proxies, trusting none", "error", err)
+		router.SetTrustedProxies(nil)
+	}
+	router.Use(middlewares.RequestLogger(slog.Default()), middlewares.Metrics(), gin.CustomRecovery(recovered))
+
+	// Metric Prometheus
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Menyajikan file statis dari folder "public"
+	router.Static("/images", "./public/images")
+
+	// Apply CORS Middleware
+	router.Use(middlewares.CORSMiddleware(cfg.AllowedOrigins))
+
+	// Cache agregasi publik, dikosongkan oleh operasi tulis lewat stores ini
+	if cfg.Cache.Enabled {
+		stores = store.WithCache(stores, cache.NewLRU(cfg.Cache.Size, cfg.Cache.TTL))
+	}
+
+	// Register routes
+	ctrl := controllers.New(cfg, stores)
+	routes.HealthRoutes(router, ctrl)
+	routes.DocsRoutes(router, ctrl)
+	routes.AuthRoutes(router, ctrl)
+	routes.UserRoutes(router, ctrl)
+	routes.CustomFacility(router, ctrl)
+	routes.CategoryRoutes(router, ctrl)
+	routes.BoardingHouse(router, ctrl)
+	routes.Facility(router, ctrl)
+	routes.RoomRoutes(router, ctrl)
+	routes.TransactionRoutes(router, ctrl)
+
+	// Path yang tidak dikenal juga dibalas dengan envelope error
+	router.NoRoute(func(c *gin.Context) {
+		response.Fail(c, http.StatusNotFound, response.CodeRouteNotFound, "Route not found")
+	})
+
+	return router
+}
+
+// recovered membalas panic di handler dengan 500 INTERNAL_ERROR.
+// Stack trace tetap ditulis oleh gin.CustomRecovery.
+func recovered(c *gin.Context, err any) {
+	middlewares.Logger(c).Error("panic recovered", "panic", err)
+	response.Abort(c, http.StatusInternalServerError, response.CodeInternal, "Internal server error")
+}
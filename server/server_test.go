@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/fixtures"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func benchConfig() *config.Config {
+	gin.SetMode(gin.ReleaseMode)
+	slog.SetDefault(NewLogger(io.Discard, "info"))
+
+	cfg := config.Default()
+	cfg.JWTSecret = "bench-secret"
+	return cfg
+}
+
+func serve(b *testing.B, h http.Handler) {
+	req := httptest.NewRequest(http.MethodGet, "/api/categories/", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		b.Fatalf("GET /api/categories/ = %d, want 200", rec.Code)
+	}
+}
+
+// BenchmarkEnginePerRequest mengukur cara lama: engine dibangun ulang di setiap request.
+func BenchmarkEnginePerRequest(b *testing.B) {
+	cfg, stores := benchConfig(), store.NewMemory()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		serve(b, NewServer(cfg, stores))
+	}
+}
+
+// BenchmarkEngineShared mengukur engine yang dibangun sekali oleh NewServer.
+func BenchmarkEngineShared(b *testing.B) {
+	router := NewServer(benchConfig(), store.NewMemory())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		serve(b, router)
+	}
+}
+
+// testServer adalah server lengkap di atas store memori, dipakai test
+// endpoint di package ini.
+type testServer struct {
+	t      *testing.T
+	cfg    *config.Config
+	stores *store.Stores
+	router *gin.Engine
+}
+
+// newTestServer membuat server dengan config test dan store memori berisi
+// fixtures. setup dijalankan sebelum NewServer untuk mengubah config atau
+// mengganti store; config tetap bisa diubah lewat s.cfg setelahnya.
+func newTestServer(t *testing.T, setup ...func(cfg *config.Config, stores *store.Stores)) *testServer {
+	t.Helper()
+	cfg := benchConfig()
+	stores := store.NewMemory()
+	if _, err := fixtures.LoadDefault(context.Background(), stores); err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range setup {
+		fn(cfg, stores)
+	}
+	return &testServer{t: t, cfg: cfg, stores: stores, router: NewServer(cfg, stores)}
+}
+
+// noRateLimit adalah setup untuk test yang memanggil endpoint auth berkali-kali.
+func noRateLimit(cfg *config.Config, _ *store.Stores) { cfg.RateLimit.Enabled = false }
+
+// anyStatus dipakai sebagai want di do/send jika status diperiksa sendiri.
+const anyStatus = 0
+
+// do mengirim request dengan body JSON lalu memeriksa statusnya. token
+// kosong berarti request anonim.
+func (s *testServer) do(method, path, token, body string, want int) *httptest.ResponseRecorder {
+	s.t.Helper()
+	return s.send(s.request(method, path, token, body), want)
+}
+
+// request menyiapkan request seperti do, untuk test yang perlu menambah
+// header, cookie atau context sebelum send.
+func (s *testServer) request(method, path, token, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+// send menjalankan req di server dan gagal jika status bukan want.
+func (s *testServer) send(req *http.Request, want int) *httptest.ResponseRecorder {
+	s.t.Helper()
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if want != anyStatus && rec.Code != want {
+		s.t.Fatalf("%s %s = %d, want %d\n%s", req.Method, req.URL, rec.Code, want, rec.Body)
+	}
+	return rec
+}
+
+// token membuat session untuk userID lalu menandatangani access token
+// untuk session itu, seperti Login.
+func (s *testServer) token(userID primitive.ObjectID, role string) string {
+	s.t.Helper()
+	now := time.Now()
+	session := models.Session{ID: primitive.NewObjectID(), UserID: userID, CreatedAt: now, RefreshedAt: now, ExpiresAt: now.Add(time.Hour)}
+	if err := s.stores.Sessions.Create(context.Background(), &session); err != nil {
+		s.t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID.Hex(),
+		"role":    role,
+		"sid":     session.ID.Hex(),
+		"exp":     now.Add(time.Hour).Unix(),
+	})
+	signed, err := token.SignedString([]byte(s.cfg.JWTSecret))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return signed
+}
+
+// decode membaca body JSON response ke v.
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+}
+
+// fakeSMTP menjalankan server SMTP lokal yang menerima semua email tanpa
+// mengirimnya, untuk handler yang gagal jika email tidak terkirim.
+func fakeSMTP(t *testing.T) (host string, port int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				text := textproto.NewConn(conn)
+				text.PrintfLine("220 fake ESMTP")
+				for {
+					line, err := text.ReadLine()
+					if err != nil {
+						return
+					}
+					cmd, _, _ := strings.Cut(line, " ")
+					switch strings.ToUpper(cmd) {
+					case "EHLO", "HELO":
+						text.PrintfLine("250-fake\r\n250 AUTH PLAIN")
+					case "AUTH":
+						text.PrintfLine("235 ok")
+					case "DATA":
+						text.PrintfLine("354 go ahead")
+						text.ReadDotBytes()
+						text.PrintfLine("250 ok")
+					case "QUIT":
+						text.PrintfLine("221 bye")
+						return
+					default:
+						text.PrintfLine("250 ok")
+					}
+				}
+			}()
+		}
+	}()
+	return "127.0.0.1", ln.Addr().(*net.TCPAddr).Port
+}
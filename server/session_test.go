@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestSessionLifecycle memeriksa rotasi refresh token, pencabutan session
+// saat refresh token lama dipakai lagi, dan logout.
+func TestSessionLifecycle(t *testing.T) {
+	s := newTestServer(t, noRateLimit)
+
+	type tokens struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	login := func() tokens {
+		t.Helper()
+		var resp struct {
+			Data tokens `json:"data"`
+		}
+		decode(t, s.do(http.MethodPost, "/auth/login", "", `{"email":"admin@kosconnect.test","password":"password123"}`, http.StatusOK), &resp)
+		if resp.Data.Token == "" || resp.Data.RefreshToken == "" {
+			t.Fatalf("login returned %+v", resp.Data)
+		}
+		return resp.Data
+	}
+	refresh := func(token string, want int) tokens {
+		t.Helper()
+		var resp struct {
+			Data tokens `json:"data"`
+		}
+		rec := s.do(http.MethodPost, "/auth/refresh", "", `{"refresh_token":"`+token+`"}`, want)
+		if want == http.StatusOK {
+			decode(t, rec, &resp)
+		}
+		return resp.Data
+	}
+	me := func(token string, want int) {
+		t.Helper()
+		s.do(http.MethodGet, "/api/users/me", token, "", want)
+	}
+
+	first := login()
+	me(first.Token, http.StatusOK)
+
+	rotated := refresh(first.RefreshToken, http.StatusOK)
+	if rotated.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh did not rotate the refresh token")
+	}
+	me(rotated.Token, http.StatusOK)
+
+	// Refresh token lama dipakai lagi: session dicabut, token baru ikut mati
+	refresh(first.RefreshToken, http.StatusUnauthorized)
+	refresh(rotated.RefreshToken, http.StatusUnauthorized)
+	me(rotated.Token, http.StatusUnauthorized)
+
+	// Logout hanya mencabut session yang dipakai
+	other, last := login(), login()
+	s.do(http.MethodPost, "/auth/logout", other.Token, "", http.StatusOK)
+	me(other.Token, http.StatusUnauthorized)
+	refresh(other.RefreshToken, http.StatusUnauthorized)
+	me(last.Token, http.StatusOK)
+
+	s.do(http.MethodPost, "/auth/logout", last.Token, `{"all":true}`, http.StatusOK)
+	me(last.Token, http.StatusUnauthorized)
+}
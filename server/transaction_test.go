@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/config"
+	"github.com/organisasi/kosconnectbackend/fixtures"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/store"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestUpdateTransactionPaymentStatus memanggil PUT
+// /api/transaction/:id/payment-status dan memeriksa perubahannya di store.
+func TestUpdateTransactionPaymentStatus(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.token(fixtures.ID("admin"), "admin")
+	owner := s.token(fixtures.ID("owner-budi"), "owner")
+	otherOwner := s.token(fixtures.ID("owner-siti"), "owner")
+	tenant := s.token(fixtures.ID("user-andi"), "user")
+
+	put := func(token, id, body string, want int) {
+		t.Helper()
+		s.do(http.MethodPut, "/api/transaction/"+id+"/payment-status", token, body, want)
+	}
+	status := func(id primitive.ObjectID) (string, string) {
+		t.Helper()
+		tx, err := s.stores.Transactions.FindByID(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx.PaymentStatus, tx.PaymentMethod
+	}
+
+	// trx-pending milik user-andi di kos owner-budi: penyewanya sendiri dan
+	// owner lain tidak boleh menandainya lunas
+	id := fixtures.ID("trx-pending")
+	put(tenant, id.Hex(), `{"payment_status":"paid"}`, http.StatusForbidden)
+	put(otherOwner, id.Hex(), `{"payment_status":"paid"}`, http.StatusForbidden)
+	if got, _ := status(id); got != "pending" {
+		t.Fatalf("payment_status changed to %q by a forbidden request", got)
+	}
+
+	put(owner, id.Hex(), `{"payment_status":"paid","payment_method":"cash"}`, http.StatusOK)
+	if got, method := status(id); got != "paid" || method != "cash" {
+		t.Errorf("transaction after owner update: status %q, method %q", got, method)
+	}
+	put(admin, id.Hex(), `{"payment_status":"cancelled"}`, http.StatusOK)
+	if got, _ := status(id); got != "cancelled" {
+		t.Errorf("transaction after admin update: status %q", got)
+	}
+
+	put(admin, primitive.NewObjectID().Hex(), `{"payment_status":"paid"}`, http.StatusNotFound)
+	put(admin, "not-an-id", `{"payment_status":"paid"}`, http.StatusBadRequest)
+	put(admin, id.Hex(), `{"payment_status":"settled"}`, http.StatusBadRequest)
+}
+
+// racingRooms menahan DecrementAvailable sampai semua booking tiba di sana,
+// sehingga semuanya sudah lolos cek number_available di controller.
+type racingRooms struct {
+	store.RoomStore
+	mu      sync.Mutex
+	waiting int
+	ready   chan struct{}
+}
+
+func (r *racingRooms) DecrementAvailable(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	if r.waiting--; r.waiting == 0 {
+		close(r.ready)
+	}
+	r.mu.Unlock()
+	select {
+	case <-r.ready:
+	case <-time.After(5 * time.Second):
+	}
+	return r.RoomStore.DecrementAvailable(ctx, id)
+}
+
+// TestConcurrentBookingsForLastRoom memeriksa dua booking bersamaan untuk
+// kamar terakhir: hanya satu yang berhasil, yang lain 409 tanpa transaksi
+// tersisa, dan number_available tidak negatif.
+func TestConcurrentBookingsForLastRoom(t *testing.T) {
+	const bookings = 2
+	roomID := fixtures.ID("room-melati-b")
+	var before []models.Transaction
+	s := newTestServer(t, func(_ *config.Config, stores *store.Stores) {
+		ctx := context.Background()
+		if err := stores.Rooms.Update(ctx, roomID, bson.M{"number_available": 1}); err != nil {
+			t.Fatal(err)
+		}
+		var err error
+		if before, err = stores.Transactions.FindByRoom(ctx, roomID); err != nil {
+			t.Fatal(err)
+		}
+		stores.Rooms = &racingRooms{RoomStore: stores.Rooms, waiting: bookings, ready: make(chan struct{})}
+	})
+	token := s.token(fixtures.ID("user-andi"), "user")
+
+	path := "/api/transaction/?room_id=" + roomID.Hex() +
+		"&boarding_house_id=" + fixtures.ID("kos-melati").Hex() +
+		"&owner_id=" + fixtures.ID("owner-budi").Hex() +
+		"&user_id=" + fixtures.ID("user-andi").Hex()
+	body := `{"payment_term":"monthly","check_in_date":"` + time.Now().AddDate(0, 0, 7).Format("2006-01-02") + `",` +
+		`"personal_info":{"full_name":"Andi Pratama","email":"andi@kosconnect.test","phone_number":"081322220001"}}`
+
+	recs := make([]*httptest.ResponseRecorder, bookings)
+	var wg sync.WaitGroup
+	for i := range recs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recs[i] = s.do(http.MethodPost, path, token, body, anyStatus)
+		}(i)
+	}
+	wg.Wait()
+
+	statuses := map[int]int{}
+	for _, rec := range recs {
+		statuses[rec.Code]++
+		if rec.Code == http.StatusConflict && !strings.Contains(rec.Body.String(), `"code":"ROOM_NOT_AVAILABLE"`) {
+			t.Errorf("409 body %s, want ROOM_NOT_AVAILABLE", rec.Body)
+		}
+	}
+	if statuses[http.StatusOK] != 1 || statuses[http.StatusConflict] != 1 {
+		t.Fatalf("statuses %v, want one 200 and one 409\n%s\n%s", statuses, recs[0].Body, recs[1].Body)
+	}
+
+	room, err := s.stores.Rooms.FindByID(context.Background(), roomID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if room.NumberAvailable != 0 {
+		t.Errorf("number_available = %d, want 0", room.NumberAvailable)
+	}
+	after, err := s.stores.Transactions.FindByRoom(context.Background(), roomID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created := len(after) - len(before); created != 1 {
+		t.Errorf("%d transactions created for the room, want 1", created)
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/organisasi/kosconnectbackend/fixtures"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestAssignRole memastikan /auth/assign-role tanpa login hanya bisa
+// memberi role user atau owner kepada akun yang belum punya role.
+func TestAssignRole(t *testing.T) {
+	s := newTestServer(t)
+	fresh := models.User{UserID: primitive.NewObjectID(), FullName: "Google Baru", Email: "baru@kosconnect.test", VerifiedEmail: true}
+	if err := s.stores.Users.Create(context.Background(), &fresh); err != nil {
+		t.Fatal(err)
+	}
+
+	assign := func(email, role string, want int) {
+		t.Helper()
+		s.do(http.MethodPut, "/auth/assign-role", "", `{"email":"`+email+`","role":"`+role+`"}`, want)
+	}
+
+	assign(fresh.Email, "admin", http.StatusBadRequest)
+	assign(fresh.Email, "owner", http.StatusOK)
+	user, err := s.stores.Users.FindByID(context.Background(), fresh.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != "owner" || !user.IsRoleAssigned {
+		t.Errorf("role %q, is_role_assigned %v; want owner, true", user.Role, user.IsRoleAssigned)
+	}
+
+	// Role yang sudah ada tidak bisa diganti, termasuk menurunkan admin
+	assign(fresh.Email, "user", http.StatusForbidden)
+	assign("admin@kosconnect.test", "user", http.StatusForbidden)
+	if admin, _ := s.stores.Users.FindByID(context.Background(), fixtures.ID("admin")); admin.Role != "admin" {
+		t.Errorf("admin role changed to %q", admin.Role)
+	}
+	assign("missing@kosconnect.test", "user", http.StatusNotFound)
+}
+
+// TestUpdateUserEmail memeriksa PUT /api/users/me dan /api/users/:id: email
+// yang sudah dipakai akun lain 409 seperti Register, dan email baru harus
+// diverifikasi ulang.
+func TestUpdateUserEmail(t *testing.T) {
+	s := newTestServer(t)
+	andi := fixtures.ID("user-andi")
+	userToken := s.token(andi, "user")
+	adminToken := s.token(fixtures.ID("admin"), "admin")
+
+	put := func(path, token, body string, want int, code response.Code) {
+		t.Helper()
+		rec := s.do(http.MethodPut, path, token, body, want)
+		if code != "" && !strings.Contains(rec.Body.String(), `"code":"`+string(code)+`"`) {
+			t.Errorf("PUT %s %s: body %s, want code %s", path, body, rec.Body, code)
+		}
+	}
+	user := func() *models.User {
+		t.Helper()
+		user, err := s.stores.Users.FindByID(context.Background(), andi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return user
+	}
+
+	// Email milik akun lain ditolak tanpa mengubah apa pun
+	put("/api/users/me", userToken, `{"email":"dewi@kosconnect.test","fullname":"Andi Baru"}`, http.StatusConflict, response.CodeEmailAlreadyExists)
+	put("/api/users/"+andi.Hex(), adminToken, `{"email":"dewi@kosconnect.test"}`, http.StatusConflict, response.CodeEmailAlreadyExists)
+	if u := user(); u.Email != "andi@kosconnect.test" || u.FullName != "Andi Pratama" || !u.VerifiedEmail {
+		t.Errorf("after conflict: email %q, fullname %q, verified %v", u.Email, u.FullName, u.VerifiedEmail)
+	}
+
+	// Mengirim email yang sama tidak mencabut verifikasi
+	put("/api/users/me", userToken, `{"email":"andi@kosconnect.test","fullname":"Andi P."}`, http.StatusOK, "")
+	if u := user(); u.FullName != "Andi P." || !u.VerifiedEmail {
+		t.Errorf("same email: fullname %q, verified %v", u.FullName, u.VerifiedEmail)
+	}
+
+	put("/api/users/me", userToken, `{"email":"andi.baru@kosconnect.test"}`, http.StatusOK, "")
+	if u := user(); u.Email != "andi.baru@kosconnect.test" || u.VerifiedEmail {
+		t.Errorf("new email: email %q, verified %v; want unverified", u.Email, u.VerifiedEmail)
+	}
+
+	// Perubahan oleh admin juga mencabut verifikasi
+	if err := s.stores.Users.MarkEmailVerified(context.Background(), andi); err != nil {
+		t.Fatal(err)
+	}
+	put("/api/users/"+andi.Hex(), adminToken, `{"email":"andi.lagi@kosconnect.test"}`, http.StatusOK, "")
+	if u := user(); u.Email != "andi.lagi@kosconnect.test" || u.VerifiedEmail {
+		t.Errorf("admin change: email %q, verified %v; want unverified", u.Email, u.VerifiedEmail)
+	}
+
+	put("/api/users/"+primitive.NewObjectID().Hex(), adminToken, `{"email":"siapa@kosconnect.test"}`, http.StatusNotFound, response.CodeUserNotFound)
+	put("/api/users/"+fixtures.ID("user-dewi").Hex(), userToken, `{"fullname":"Dewi"}`, http.StatusForbidden, response.CodeForbidden)
+}
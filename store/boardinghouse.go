@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BoardingHouseStore menyimpan data kos (koleksi "boardinghouses").
+// Parameter ownerID pada Update dan Delete membatasi operasi ke kos milik
+// owner tersebut; isi dengan primitive.NilObjectID untuk tanpa batasan (admin).
+type BoardingHouseStore interface {
+	Create(ctx context.Context, boardingHouse *models.BoardingHouse) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.BoardingHouse, error)
+	FindAll(ctx context.Context) ([]models.BoardingHouse, error)
+	List(ctx context.Context, q query.Query) (query.Page[models.BoardingHouse], error)
+	FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]models.BoardingHouse, error)
+	Details(ctx context.Context, id primitive.ObjectID) ([]bson.M, error)
+	Update(ctx context.Context, id, ownerID primitive.ObjectID, set interface{}) error
+	Delete(ctx context.Context, id, ownerID primitive.ObjectID) error
+}
+
+// boardingHouseViews menjalankan query gabungan (join) untuk kos.
+type boardingHouseViews interface {
+	boardingHouseDetails(ctx context.Context, id primitive.ObjectID) ([]bson.M, error)
+}
+
+type boardingHouseStore struct {
+	coll  collection[models.BoardingHouse]
+	views boardingHouseViews
+}
+
+func (s *boardingHouseStore) Create(ctx context.Context, boardingHouse *models.BoardingHouse) error {
+	return s.coll.insert(ctx, boardingHouse)
+}
+
+func (s *boardingHouseStore) FindByID(ctx context.Context, id primitive.ObjectID) (*models.BoardingHouse, error) {
+	return s.coll.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *boardingHouseStore) FindAll(ctx context.Context) ([]models.BoardingHouse, error) {
+	return s.coll.find(ctx, bson.M{})
+}
+
+func (s *boardingHouseStore) List(ctx context.Context, q query.Query) (query.Page[models.BoardingHouse], error) {
+	return s.coll.list(ctx, bson.M{}, q)
+}
+
+func (s *boardingHouseStore) FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]models.BoardingHouse, error) {
+	return s.coll.find(ctx, bson.M{"owner_id": ownerID})
+}
+
+func (s *boardingHouseStore) Details(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	return s.views.boardingHouseDetails(ctx, id)
+}
+
+func (s *boardingHouseStore) Update(ctx context.Context, id, ownerID primitive.ObjectID, set interface{}) error {
+	return s.coll.update(ctx, scoped(id, ownerID), bson.M{"$set": set})
+}
+
+func (s *boardingHouseStore) Delete(ctx context.Context, id, ownerID primitive.ObjectID) error {
+	return s.coll.delete(ctx, scoped(id, ownerID))
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+
+	"github.com/organisasi/kosconnectbackend/cache"
+	"github.com/organisasi/kosconnectbackend/metrics"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WithCache mengembalikan salinan s yang membaca agregasi publik (landing
+// page, detail kamar dan detail kos) lewat c. Setiap create, update dan
+// delete kamar, kos, kategori, fasilitas dan custom facility lewat salinan
+// ini mengosongkan seluruh c, karena satu dokumen bisa muncul di banyak
+// hasil agregasi.
+//
+// Invalidasi hanya berlaku di proses ini. Jika aplikasi berjalan di lebih
+// dari satu instance, perubahan dari instance lain baru terlihat setelah
+// TTL cache habis. Nama owner (koleksi users) juga hanya diperbarui lewat TTL.
+func WithCache(s *Stores, c cache.Cache) *Stores {
+	cached := *s
+	cached.Rooms = &cachedRoomStore{RoomStore: s.Rooms, cache: c}
+	cached.BoardingHouses = &cachedBoardingHouseStore{BoardingHouseStore: s.BoardingHouses, cache: c}
+	cached.Categories = &cachedCategoryStore{CategoryStore: s.Categories, cache: c}
+	cached.Facilities = &cachedFacilityStore{FacilityStore: s.Facilities, cache: c}
+	cached.CustomFacilities = &cachedCustomFacilityStore{CustomFacilityStore: s.CustomFacilities, cache: c}
+	return &cached
+}
+
+// cached membaca key dari c atau menjalankan load lalu menyimpan hasilnya.
+// view menjadi label metric cache_requests_total.
+func cached[T any](c cache.Cache, view, key string, load func() (T, error)) (T, error) {
+	if value, ok := c.Get(view + ":" + key); ok {
+		metrics.CacheRequests.WithLabelValues(view, "hit").Inc()
+		return value.(T), nil
+	}
+	metrics.CacheRequests.WithLabelValues(view, "miss").Inc()
+
+	generation := c.Generation()
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	c.Set(view+":"+key, value, generation)
+	return value, nil
+}
+
+// purge mengosongkan c setelah operasi tulis, juga jika operasinya gagal
+// karena perubahan bisa saja sudah terjadi (misal timeout).
+func purge(c cache.Cache, err error) error {
+	c.Purge()
+	metrics.CachePurges.Inc()
+	return err
+}
+
+type cachedRoomStore struct {
+	RoomStore
+	cache cache.Cache
+}
+
+func (s *cachedRoomStore) Details(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	return cached(s.cache, "room_details", id.Hex(), func() ([]bson.M, error) {
+		return s.RoomStore.Details(ctx, id)
+	})
+}
+
+func (s *cachedRoomStore) DetailPage(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	return cached(s.cache, "room_detail_page", id.Hex(), func() ([]bson.M, error) {
+		return s.RoomStore.DetailPage(ctx, id)
+	})
+}
+
+func (s *cachedRoomStore) LandingPage(ctx context.Context, q query.Query) (query.Page[bson.M], error) {
+	return cached(s.cache, "room_landing_page", q.Key(), func() (query.Page[bson.M], error) {
+		return s.RoomStore.LandingPage(ctx, q)
+	})
+}
+
+func (s *cachedRoomStore) Create(ctx context.Context, room *models.Room) error {
+	return purge(s.cache, s.RoomStore.Create(ctx, room))
+}
+
+func (s *cachedRoomStore) Update(ctx context.Context, id primitive.ObjectID, set interface{}) error {
+	return purge(s.cache, s.RoomStore.Update(ctx, id, set))
+}
+
+// DecrementAvailable dipanggil saat booking; status kamar di landing page ikut berubah.
+func (s *cachedRoomStore) DecrementAvailable(ctx context.Context, id primitive.ObjectID) error {
+	return purge(s.cache, s.RoomStore.DecrementAvailable(ctx, id))
+}
+
+func (s *cachedRoomStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return purge(s.cache, s.RoomStore.Delete(ctx, id))
+}
+
+type cachedBoardingHouseStore struct {
+	BoardingHouseStore
+	cache cache.Cache
+}
+
+func (s *cachedBoardingHouseStore) Details(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	return cached(s.cache, "boarding_house_details", id.Hex(), func() ([]bson.M, error) {
+		return s.BoardingHouseStore.Details(ctx, id)
+	})
+}
+
+func (s *cachedBoardingHouseStore) Create(ctx context.Context, boardingHouse *models.BoardingHouse) error {
+	return purge(s.cache, s.BoardingHouseStore.Create(ctx, boardingHouse))
+}
+
+func (s *cachedBoardingHouseStore) Update(ctx context.Context, id, ownerID primitive.ObjectID, set interface{}) error {
+	return purge(s.cache, s.BoardingHouseStore.Update(ctx, id, ownerID, set))
+}
+
+func (s *cachedBoardingHouseStore) Delete(ctx context.Context, id, ownerID primitive.ObjectID) error {
+	return purge(s.cache, s.BoardingHouseStore.Delete(ctx, id, ownerID))
+}
+
+type cachedCategoryStore struct {
+	CategoryStore
+	cache cache.Cache
+}
+
+func (s *cachedCategoryStore) Create(ctx context.Context, category *models.Category) error {
+	return purge(s.cache, s.CategoryStore.Create(ctx, category))
+}
+
+func (s *cachedCategoryStore) Update(ctx context.Context, id primitive.ObjectID, set interface{}) error {
+	return purge(s.cache, s.CategoryStore.Update(ctx, id, set))
+}
+
+func (s *cachedCategoryStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return purge(s.cache, s.CategoryStore.Delete(ctx, id))
+}
+
+type cachedFacilityStore struct {
+	FacilityStore
+	cache cache.Cache
+}
+
+func (s *cachedFacilityStore) Create(ctx context.Context, facility *models.Facility) error {
+	return purge(s.cache, s.FacilityStore.Create(ctx, facility))
+}
+
+func (s *cachedFacilityStore) Update(ctx context.Context, id primitive.ObjectID, set interface{}) error {
+	return purge(s.cache, s.FacilityStore.Update(ctx, id, set))
+}
+
+func (s *cachedFacilityStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return purge(s.cache, s.FacilityStore.Delete(ctx, id))
+}
+
+type cachedCustomFacilityStore struct {
+	CustomFacilityStore
+	cache cache.Cache
+}
+
+func (s *cachedCustomFacilityStore) Create(ctx context.Context, facility *models.CustomFacility) error {
+	return purge(s.cache, s.CustomFacilityStore.Create(ctx, facility))
+}
+
+func (s *cachedCustomFacilityStore) Update(ctx context.Context, id, ownerID primitive.ObjectID, set interface{}) error {
+	return purge(s.cache, s.CustomFacilityStore.Update(ctx, id, ownerID, set))
+}
+
+func (s *cachedCustomFacilityStore) Delete(ctx context.Context, id, ownerID primitive.ObjectID) error {
+	return purge(s.cache, s.CustomFacilityStore.Delete(ctx, id, ownerID))
+}
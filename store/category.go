@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CategoryStore menyimpan kategori kos (koleksi "categories").
+type CategoryStore interface {
+	Create(ctx context.Context, category *models.Category) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Category, error)
+	FindAll(ctx context.Context) ([]models.Category, error)
+	Update(ctx context.Context, id primitive.ObjectID, set interface{}) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type categoryStore struct {
+	coll collection[models.Category]
+}
+
+func (s *categoryStore) Create(ctx context.Context, category *models.Category) error {
+	return s.coll.insert(ctx, category)
+}
+
+func (s *categoryStore) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Category, error) {
+	return s.coll.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *categoryStore) FindAll(ctx context.Context) ([]models.Category, error) {
+	return s.coll.find(ctx, bson.M{})
+}
+
+func (s *categoryStore) Update(ctx context.Context, id primitive.ObjectID, set interface{}) error {
+	return s.coll.update(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+}
+
+func (s *categoryStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return s.coll.delete(ctx, bson.M{"_id": id})
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CustomFacilityStore menyimpan fasilitas tambahan milik owner (koleksi
+// "customFacility"). Parameter ownerID bekerja sama seperti di
+// BoardingHouseStore.
+type CustomFacilityStore interface {
+	Create(ctx context.Context, facility *models.CustomFacility) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.CustomFacility, error)
+	FindByIDAndOwner(ctx context.Context, id, ownerID primitive.ObjectID) (*models.CustomFacility, error)
+	FindAll(ctx context.Context) ([]models.CustomFacility, error)
+	List(ctx context.Context, q query.Query) (query.Page[models.CustomFacility], error)
+	FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]models.CustomFacility, error)
+	Update(ctx context.Context, id, ownerID primitive.ObjectID, set interface{}) error
+	Delete(ctx context.Context, id, ownerID primitive.ObjectID) error
+}
+
+type customFacilityStore struct {
+	coll collection[models.CustomFacility]
+}
+
+func (s *customFacilityStore) Create(ctx context.Context, facility *models.CustomFacility) error {
+	return s.coll.insert(ctx, facility)
+}
+
+func (s *customFacilityStore) FindByID(ctx context.Context, id primitive.ObjectID) (*models.CustomFacility, error) {
+	return s.coll.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *customFacilityStore) FindByIDAndOwner(ctx context.Context, id, ownerID primitive.ObjectID) (*models.CustomFacility, error) {
+	return s.coll.findOne(ctx, scoped(id, ownerID))
+}
+
+func (s *customFacilityStore) FindAll(ctx context.Context) ([]models.CustomFacility, error) {
+	return s.coll.find(ctx, bson.M{})
+}
+
+func (s *customFacilityStore) List(ctx context.Context, q query.Query) (query.Page[models.CustomFacility], error) {
+	return s.coll.list(ctx, bson.M{}, q)
+}
+
+func (s *customFacilityStore) FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]models.CustomFacility, error) {
+	return s.coll.find(ctx, bson.M{"owner_id": ownerID})
+}
+
+func (s *customFacilityStore) Update(ctx context.Context, id, ownerID primitive.ObjectID, set interface{}) error {
+	return s.coll.update(ctx, scoped(id, ownerID), bson.M{"$set": set})
+}
+
+func (s *customFacilityStore) Delete(ctx context.Context, id, ownerID primitive.ObjectID) error {
+	return s.coll.delete(ctx, scoped(id, ownerID))
+}
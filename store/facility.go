@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FacilityStore menyimpan fasilitas umum (koleksi "facilities").
+type FacilityStore interface {
+	Create(ctx context.Context, facility *models.Facility) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Facility, error)
+	FindByIDAndType(ctx context.Context, id primitive.ObjectID, facilityType string) (*models.Facility, error)
+	FindAll(ctx context.Context) ([]models.Facility, error)
+	FindByType(ctx context.Context, facilityType string) ([]models.Facility, error)
+	Update(ctx context.Context, id primitive.ObjectID, set interface{}) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type facilityStore struct {
+	coll collection[models.Facility]
+}
+
+func (s *facilityStore) Create(ctx context.Context, facility *models.Facility) error {
+	return s.coll.insert(ctx, facility)
+}
+
+func (s *facilityStore) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Facility, error) {
+	return s.coll.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *facilityStore) FindByIDAndType(ctx context.Context, id primitive.ObjectID, facilityType string) (*models.Facility, error) {
+	return s.coll.findOne(ctx, bson.M{"_id": id, "type": facilityType})
+}
+
+func (s *facilityStore) FindAll(ctx context.Context) ([]models.Facility, error) {
+	return s.coll.find(ctx, bson.M{})
+}
+
+func (s *facilityStore) FindByType(ctx context.Context, facilityType string) ([]models.Facility, error) {
+	return s.coll.find(ctx, bson.M{"type": facilityType})
+}
+
+func (s *facilityStore) Update(ctx context.Context, id primitive.ObjectID, set interface{}) error {
+	return s.coll.update(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+}
+
+func (s *facilityStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return s.coll.delete(ctx, bson.M{"_id": id})
+}
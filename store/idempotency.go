@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// IdempotencyStore menyimpan response per Idempotency-Key (koleksi
+// "idempotency_keys"). Record kedaluwarsa dihapus oleh TTL index di
+// MongoDB; pemanggil tetap harus mengecek ExpiresAt.
+type IdempotencyStore interface {
+	// Reserve menyimpan record baru yang belum selesai. ErrDuplicate jika ID sudah ada.
+	Reserve(ctx context.Context, key *models.IdempotencyKey) error
+	Find(ctx context.Context, id string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, id string, status int, contentType string, body []byte) error
+	Delete(ctx context.Context, id string) error
+}
+
+type idempotencyStore struct {
+	coll collection[models.IdempotencyKey]
+}
+
+func (s *idempotencyStore) Reserve(ctx context.Context, key *models.IdempotencyKey) error {
+	return s.coll.insert(ctx, key)
+}
+
+func (s *idempotencyStore) Find(ctx context.Context, id string) (*models.IdempotencyKey, error) {
+	return s.coll.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *idempotencyStore) Complete(ctx context.Context, id string, status int, contentType string, body []byte) error {
+	return s.coll.update(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"completed":    true,
+		"status":       status,
+		"content_type": contentType,
+		"body":         body,
+	}})
+}
+
+func (s *idempotencyStore) Delete(ctx context.Context, id string) error {
+	return s.coll.delete(ctx, bson.M{"_id": id})
+}
@@ -0,0 +1,392 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewMemory membuat Stores yang menyimpan data di memori. Dipakai untuk
+// pengujian dan development lokal tanpa MongoDB. Dokumen disimpan dalam
+// bentuk bson sehingga tag bson di models (omitempty, nama field) tetap
+// berlaku sama seperti di Mongo.
+func NewMemory() *Stores {
+	db := &memDB{tables: map[string][]bson.M{}}
+	views := memViews{db: db}
+	return &Stores{
+		Users:            &userStore{coll: memCollection[models.User]{db: db, name: CollectionUsers}},
+		BoardingHouses:   &boardingHouseStore{coll: memCollection[models.BoardingHouse]{db: db, name: CollectionBoardingHouses}, views: views},
+		Rooms:            &roomStore{coll: memCollection[models.Room]{db: db, name: CollectionRooms}, views: views},
+		Transactions:     &transactionStore{coll: memCollection[models.Transaction]{db: db, name: CollectionTransactions}},
+		Facilities:       &facilityStore{coll: memCollection[models.Facility]{db: db, name: CollectionFacilities}},
+		Categories:       &categoryStore{coll: memCollection[models.Category]{db: db, name: CollectionCategories}},
+		CustomFacilities: &customFacilityStore{coll: memCollection[models.CustomFacility]{db: db, name: CollectionCustomFacilities}},
+		Idempotency:      &idempotencyStore{coll: memCollection[models.IdempotencyKey]{db: db, name: CollectionIdempotencyKeys}},
+		Sessions:         &sessionStore{coll: memCollection[models.Session]{db: db, name: CollectionSessions}},
+		PasswordResets:   &passwordResetStore{coll: memCollection[models.PasswordReset]{db: db, name: CollectionPasswordResets}},
+		OAuthStates:      &oauthStateStore{coll: memCollection[models.OAuthState]{db: db, name: CollectionOAuthStates}},
+		ping:             func(ctx context.Context) error { return ctx.Err() },
+		version:          views.collectionVersion,
+	}
+}
+
+// memDB menyimpan dokumen per koleksi sesuai urutan insert.
+type memDB struct {
+	mu     sync.RWMutex
+	tables map[string][]bson.M
+}
+
+// uniqueFields meniru unique index dari migrasi (versi 1 dan 7) agar
+// ErrDuplicate juga muncul tanpa MongoDB. Seperti partial index
+// hasString, hanya nilai string yang dibandingkan; users.email di MongoDB
+// tidak partial, tetapi setiap user selalu punya email.
+var uniqueFields = map[string][]string{
+	CollectionUsers:          {"email"},
+	CollectionBoardingHouses: {"slug"},
+	CollectionTransactions:   {"transaction_code"},
+	CollectionPasswordResets: {"token_hash"},
+}
+
+// conflict mengembalikan ErrDuplicate jika doc memakai nilai unik milik
+// dokumen lain di koleksi. Pemanggil harus memegang lock.
+func (db *memDB) conflict(name string, doc bson.M) error {
+	for _, field := range uniqueFields[name] {
+		value, ok := doc[field].(string)
+		if !ok {
+			continue
+		}
+		for _, other := range db.tables[name] {
+			if other[field] == value && !reflect.DeepEqual(other["_id"], doc["_id"]) {
+				return fmt.Errorf("%w: %s %q in %s", ErrDuplicate, field, value, name)
+			}
+		}
+	}
+	return nil
+}
+
+// updateDoc menjalankan update pada salinan dokumen ke-i lalu menggantinya
+// jika tidak melanggar nilai unik, sehingga update yang gagal tidak
+// mengubah apa pun. Pemanggil harus memegang lock.
+func (db *memDB) updateDoc(name string, i int, update bson.M) error {
+	doc := maps.Clone(db.tables[name][i])
+	if err := applyUpdate(doc, update); err != nil {
+		return err
+	}
+	if err := db.conflict(name, doc); err != nil {
+		return err
+	}
+	db.tables[name][i] = doc
+	return nil
+}
+
+// byID mencari dokumen berdasarkan _id. Pemanggil harus memegang lock.
+func (db *memDB) byID(name string, id interface{}) bson.M {
+	if id == nil {
+		return nil
+	}
+	for _, doc := range db.tables[name] {
+		if reflect.DeepEqual(doc["_id"], id) {
+			return doc
+		}
+	}
+	return nil
+}
+
+type memCollection[T any] struct {
+	db   *memDB
+	name string
+}
+
+func (m memCollection[T]) insert(ctx context.Context, doc *T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	normalized, err := toDoc(doc)
+	if err != nil {
+		return err
+	}
+	if _, ok := normalized["_id"]; !ok {
+		normalized["_id"] = primitive.NewObjectID()
+	}
+
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+	if m.db.byID(m.name, normalized["_id"]) != nil {
+		return fmt.Errorf("%w: _id %v in %s", ErrDuplicate, normalized["_id"], m.name)
+	}
+	if err := m.db.conflict(m.name, normalized); err != nil {
+		return err
+	}
+	m.db.tables[m.name] = append(m.db.tables[m.name], normalized)
+	return nil
+}
+
+func (m memCollection[T]) findOne(ctx context.Context, filter bson.M) (*T, error) {
+	docs, err := m.find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, ErrNotFound
+	}
+	return &docs[0], nil
+}
+
+func (m memCollection[T]) find(ctx context.Context, filter bson.M) ([]T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	normalized, err := toDoc(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	m.db.mu.RLock()
+	defer m.db.mu.RUnlock()
+	var docs []T
+	for _, doc := range m.db.tables[m.name] {
+		if !matches(doc, normalized) {
+			continue
+		}
+		var out T
+		if err := fromDoc(doc, &out); err != nil {
+			return nil, err
+		}
+		docs = append(docs, out)
+	}
+	return docs, nil
+}
+
+func (m memCollection[T]) update(ctx context.Context, filter, update bson.M) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	normalized, err := toDoc(filter)
+	if err != nil {
+		return err
+	}
+
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+	for i, doc := range m.db.tables[m.name] {
+		if matches(doc, normalized) {
+			return m.db.updateDoc(m.name, i, update)
+		}
+	}
+	return ErrNotFound
+}
+
+func (m memCollection[T]) updateMany(ctx context.Context, filter, update bson.M) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	normalized, err := toDoc(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+	var n int64
+	for i, doc := range m.db.tables[m.name] {
+		if matches(doc, normalized) {
+			if err := m.db.updateDoc(m.name, i, update); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m memCollection[T]) delete(ctx context.Context, filter bson.M) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	normalized, err := toDoc(filter)
+	if err != nil {
+		return err
+	}
+
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+	table := m.db.tables[m.name]
+	for i, doc := range table {
+		if matches(doc, normalized) {
+			m.db.tables[m.name] = append(table[:i:i], table[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// toDoc mengubah struct atau map menjadi bson.M lewat encoder bson agar
+// tipe nilai sama dengan yang dikembalikan MongoDB.
+func toDoc(v interface{}) (bson.M, error) {
+	data, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func fromDoc(doc bson.M, out interface{}) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, out)
+}
+
+// matches mendukung filter kesetaraan sederhana dan operator perbandingan
+// ($gt, $gte, $lt, $lte); nilai nil cocok dengan field yang tidak ada, sama
+// seperti query {"field": null} di MongoDB.
+func matches(doc, filter bson.M) bool {
+	for key, want := range filter {
+		got, ok := doc[key]
+		if ops, isOps := operators(want); isOps {
+			if !ok || !matchOperators(got, ops) {
+				return false
+			}
+			continue
+		}
+		if !ok {
+			if want != nil {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// operators mengembalikan v sebagai dokumen operator, misal {"$gt": 0}.
+func operators(v interface{}) (bson.M, bool) {
+	ops, ok := v.(bson.M)
+	if !ok || len(ops) == 0 {
+		return nil, false
+	}
+	for op := range ops {
+		if !strings.HasPrefix(op, "$") {
+			return nil, false
+		}
+	}
+	return ops, true
+}
+
+// matchOperators membandingkan nilai dengan urutan BSON seperti MongoDB:
+// nilai beda tipe (misal string dengan angka) tidak pernah cocok.
+// Operator yang tidak didukung tidak cocok dengan dokumen apa pun.
+func matchOperators(got interface{}, ops bson.M) bool {
+	for op, want := range ops {
+		if typeOrder(got) != typeOrder(want) {
+			return false
+		}
+		c := compareValues(got, want)
+		switch op {
+		case "$gt":
+			if c <= 0 {
+				return false
+			}
+		case "$gte":
+			if c < 0 {
+				return false
+			}
+		case "$lt":
+			if c >= 0 {
+				return false
+			}
+		case "$lte":
+			if c > 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// applyUpdate menjalankan operator $set, $unset dan $inc pada dokumen.
+func applyUpdate(doc, update bson.M) error {
+	for op, value := range update {
+		fields, err := toDoc(value)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case "$set":
+			for key, v := range fields {
+				doc[key] = v
+			}
+		case "$unset":
+			for key := range fields {
+				delete(doc, key)
+			}
+		case "$inc":
+			for key, v := range fields {
+				sum, err := addNumbers(doc[key], v)
+				if err != nil {
+					return fmt.Errorf("store: $inc %s: %w", key, err)
+				}
+				doc[key] = sum
+			}
+		default:
+			return fmt.Errorf("store: unsupported update operator %s", op)
+		}
+	}
+	return nil
+}
+
+func addNumbers(a, b interface{}) (interface{}, error) {
+	if a == nil {
+		return b, nil
+	}
+	switch x := a.(type) {
+	case int32:
+		if y, ok := b.(int32); ok {
+			return x + y, nil
+		}
+		if y, ok := b.(int64); ok {
+			return int64(x) + y, nil
+		}
+	case int64:
+		if y, ok := b.(int32); ok {
+			return x + int64(y), nil
+		}
+		if y, ok := b.(int64); ok {
+			return x + y, nil
+		}
+	case float64:
+		if y, ok := toFloat(b); ok {
+			return x + y, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot add %T and %T", a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (m memCollection[T]) list(ctx context.Context, filter bson.M, q query.Query) (query.Page[T], error) {
+	if err := ctx.Err(); err != nil {
+		return query.Page[T]{}, err
+	}
+	normalized, err := toDoc(q.Match(filter))
+	if err != nil {
+		return query.Page[T]{}, err
+	}
+
+	m.db.mu.RLock()
+	defer m.db.mu.RUnlock()
+	total, docs := pageDocs(m.db.tables[m.name], normalized, q)
+	return decodePage[T](q, total, docs)
+}
+
+// pageDocs meniru Find dengan q.FindOptions() dan q.Seek(): filter,
+// urutkan, lewati dokumen sampai cursor atau halaman, lalu ambil Limit+1.
+// total adalah jumlah dokumen yang cocok dengan filter.
+func pageDocs(docs []bson.M, filter bson.M, q query.Query) (int64, []bson.M) {
+	var matched []bson.M
+	for _, doc := range docs {
+		if matches(doc, filter) {
+			matched = append(matched, doc)
+		}
+	}
+	total := int64(len(matched))
+
+	order := func(a, b bson.M) int {
+		c := compareValues(a[q.Field], b[q.Field])
+		if c == 0 {
+			c = compareValues(a["_id"], b["_id"])
+		}
+		if q.Desc {
+			return -c
+		}
+		return c
+	}
+	slices.SortStableFunc(matched, order)
+
+	if q.After != nil {
+		after := bson.M{q.Field: q.After.Value, "_id": q.After.ID}
+		i := 0
+		for i < len(matched) && order(matched[i], after) <= 0 {
+			i++
+		}
+		matched = matched[i:]
+	}
+	if skip := q.Skip(); skip > 0 {
+		matched = matched[min(int(skip), len(matched)):]
+	}
+	return total, matched[:min(q.Limit+1, len(matched))]
+}
+
+// compareValues mengikuti urutan perbandingan BSON MongoDB: null, angka,
+// string, dokumen, array, binary, ObjectID, boolean, lalu tanggal.
+func compareValues(a, b interface{}) int {
+	if c := cmp.Compare(typeOrder(a), typeOrder(b)); c != 0 {
+		return c
+	}
+	switch x := a.(type) {
+	case string:
+		return strings.Compare(x, b.(string))
+	case primitive.ObjectID:
+		y := b.(primitive.ObjectID)
+		return bytes.Compare(x[:], y[:])
+	case bool:
+		return cmp.Compare(boolOrder(x), boolOrder(b.(bool)))
+	case primitive.DateTime:
+		return cmp.Compare(x, b.(primitive.DateTime))
+	}
+	if x, ok := toFloat(a); ok {
+		y, _ := toFloat(b)
+		return cmp.Compare(x, y)
+	}
+	return 0
+}
+
+func typeOrder(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 1
+	case int32, int64, float64:
+		return 2
+	case string:
+		return 3
+	case bson.M, bson.D:
+		return 4
+	case bson.A:
+		return 5
+	case primitive.Binary:
+		return 6
+	case primitive.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case primitive.DateTime:
+		return 9
+	}
+	return 10
+}
+
+func boolOrder(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMatches(t *testing.T) {
+	id := primitive.NewObjectID()
+	doc := bson.M{"_id": id, "role": "owner", "count": int32(2), "deleted": nil}
+	for _, tc := range []struct {
+		name   string
+		filter bson.M
+		want   bool
+	}{
+		{"empty filter", bson.M{}, true},
+		{"equal fields", bson.M{"_id": id, "role": "owner"}, true},
+		{"different value", bson.M{"role": "user"}, false},
+		{"different type", bson.M{"count": int64(2)}, false},
+		{"null matches missing", bson.M{"email": nil}, true},
+		{"null matches null", bson.M{"deleted": nil}, true},
+		{"value does not match missing", bson.M{"email": "a@example.com"}, false},
+		{"null does not match a value", bson.M{"role": nil}, false},
+		{"$gt", bson.M{"count": bson.M{"$gt": int32(1)}}, true},
+		{"$gt equal value", bson.M{"count": bson.M{"$gt": int32(2)}}, false},
+		{"$gte across number types", bson.M{"count": bson.M{"$gte": int64(2)}}, true},
+		{"$lt and $gt together", bson.M{"count": bson.M{"$gt": 1.5, "$lt": int32(3)}}, true},
+		{"$lte", bson.M{"count": bson.M{"$lte": int32(1)}}, false},
+		{"$gt on a missing field", bson.M{"email": bson.M{"$gt": int32(0)}}, false},
+		{"$gt on a different type", bson.M{"role": bson.M{"$gt": int32(0)}}, false},
+		{"unsupported operator", bson.M{"count": bson.M{"$ne": int32(1)}}, false},
+	} {
+		if got := matches(doc, tc.filter); got != tc.want {
+			t.Errorf("%s: matches(%v) = %v, want %v", tc.name, tc.filter, got, tc.want)
+		}
+	}
+}
+
+func TestApplyUpdate(t *testing.T) {
+	doc := bson.M{"name": "A", "count": int32(1), "big": int64(10), "score": 1.5, "temp": "x"}
+	err := applyUpdate(doc, bson.M{
+		"$set":   bson.M{"name": "B", "added": true},
+		"$unset": bson.M{"temp": ""},
+		"$inc":   bson.M{"count": 2, "big": int32(-1), "score": 1, "fresh": int64(5)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bson.M{"name": "B", "added": true, "count": int32(3), "big": int64(9), "score": 2.5, "fresh": int64(5)}
+	if len(doc) != len(want) {
+		t.Errorf("doc = %v, want %v", doc, want)
+	}
+	for key, value := range want {
+		if doc[key] != value {
+			t.Errorf("%s = %#v, want %#v", key, doc[key], value)
+		}
+	}
+
+	if err := applyUpdate(bson.M{"name": "A"}, bson.M{"$inc": bson.M{"name": 1}}); err == nil {
+		t.Error("$inc on a string succeeded")
+	}
+	if err := applyUpdate(bson.M{}, bson.M{"$push": bson.M{"tags": "a"}}); err == nil {
+		t.Error("unsupported operator succeeded")
+	}
+}
+
+func TestMemoryNotFound(t *testing.T) {
+	ctx := context.Background()
+	stores := NewMemory()
+	missing := primitive.NewObjectID()
+
+	if _, err := stores.Users.FindByID(ctx, missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindByID: err = %v, want ErrNotFound", err)
+	}
+	if err := stores.Users.Update(ctx, missing, bson.M{"fullname": "X"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update: err = %v, want ErrNotFound", err)
+	}
+	if err := stores.Users.Delete(ctx, missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete: err = %v, want ErrNotFound", err)
+	}
+	users, err := stores.Users.FindAll(ctx)
+	if err != nil || len(users) != 0 {
+		t.Errorf("FindAll on empty store = %v, %v", users, err)
+	}
+	// updateMany tanpa dokumen yang cocok bukan error, seperti UpdateMany di MongoDB
+	if n, err := stores.PasswordResets.UseAll(ctx, missing); err != nil || n != 0 {
+		t.Errorf("UseAll = %d, %v; want 0, nil", n, err)
+	}
+
+	// Hanya satu dokumen yang dihapus, dan setelah itu tidak ditemukan lagi
+	user := models.User{UserID: primitive.NewObjectID(), Email: "a@example.com", FullName: "A"}
+	if err := stores.Users.Create(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+	if err := stores.Users.Delete(ctx, user.UserID); err != nil {
+		t.Fatal(err)
+	}
+	if err := stores.Users.Delete(ctx, user.UserID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryUnique(t *testing.T) {
+	ctx := context.Background()
+	stores := NewMemory()
+
+	a := models.User{UserID: primitive.NewObjectID(), Email: "a@example.com"}
+	b := models.User{UserID: primitive.NewObjectID(), Email: "b@example.com"}
+	for _, u := range []*models.User{&a, &b} {
+		if err := stores.Users.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stores.Users.Create(ctx, &models.User{UserID: primitive.NewObjectID(), Email: "a@example.com"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("duplicate email: err = %v, want ErrDuplicate", err)
+	}
+	if err := stores.Users.Create(ctx, &models.User{UserID: a.UserID, Email: "c@example.com"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("duplicate _id: err = %v, want ErrDuplicate", err)
+	}
+
+	// Update ke email milik user lain ditolak tanpa mengubah dokumen
+	if err := stores.Users.Update(ctx, b.UserID, bson.M{"email": "a@example.com", "fullname": "B"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("update to a taken email: err = %v, want ErrDuplicate", err)
+	}
+	if got, _ := stores.Users.FindByID(ctx, b.UserID); got.Email != "b@example.com" || got.FullName != "" {
+		t.Errorf("failed update changed the user: %+v", got)
+	}
+	// Menyimpan ulang email sendiri bukan duplikat
+	if err := stores.Users.Update(ctx, a.UserID, bson.M{"email": "a@example.com"}); err != nil {
+		t.Errorf("update with own email: %v", err)
+	}
+
+	owner := primitive.NewObjectID()
+	for i, slug := range []string{"kos-a", "", ""} {
+		// Slug kosong tidak disimpan (omitempty), jadi tidak ikut unique seperti partial index
+		err := stores.BoardingHouses.Create(ctx, &models.BoardingHouse{BoardingHouseID: primitive.NewObjectID(), OwnerID: owner, Slug: slug})
+		if err != nil {
+			t.Errorf("boarding house %d with slug %q: %v", i, slug, err)
+		}
+	}
+	if err := stores.BoardingHouses.Create(ctx, &models.BoardingHouse{BoardingHouseID: primitive.NewObjectID(), Slug: "kos-a"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("duplicate slug: err = %v, want ErrDuplicate", err)
+	}
+
+	first := models.Transaction{TransactionID: primitive.NewObjectID(), TransactionCode: "KCT1"}
+	if err := stores.Transactions.Create(ctx, &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := stores.Transactions.Create(ctx, &models.Transaction{TransactionID: primitive.NewObjectID(), TransactionCode: "KCT1"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("duplicate transaction_code: err = %v, want ErrDuplicate", err)
+	}
+}
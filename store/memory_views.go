@@ -0,0 +1,273 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memViews meniru pipeline agregasi di pipelines.go untuk NewMemory.
+// Aturannya mengikuti MongoDB: $unwind tanpa preserveNullAndEmptyArrays
+// membuang dokumen yang join-nya kosong, dan path yang tidak ada tidak
+// ikut di-project.
+type memViews struct {
+	db *memDB
+}
+
+func (v memViews) boardingHouseDetails(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	v.db.mu.RLock()
+	defer v.db.mu.RUnlock()
+
+	boardingHouse := v.db.byID(CollectionBoardingHouses, id)
+	if boardingHouse == nil {
+		return nil, nil
+	}
+	category := v.db.byID(CollectionCategories, boardingHouse["category_id"])
+	owner := v.db.byID(CollectionUsers, boardingHouse["owner_id"])
+	if category == nil || owner == nil {
+		return nil, nil
+	}
+	facilities := v.lookup(CollectionFacilities, boardingHouse["facilities_id"])
+
+	result := bson.M{"_id": boardingHouse["_id"], "facilities": pluck(facilities, "name")}
+	copyField(result, "category_name", category, "name")
+	copyField(result, "owner_fullname", owner, "fullname")
+	return cloneAll([]bson.M{result})
+}
+
+func (v memViews) roomDetails(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	v.db.mu.RLock()
+	defer v.db.mu.RUnlock()
+
+	room := v.db.byID(CollectionRooms, id)
+	if room == nil {
+		return nil, nil
+	}
+	boardingHouse := v.db.byID(CollectionBoardingHouses, room["boarding_house_id"])
+	if boardingHouse == nil {
+		return nil, nil
+	}
+	owner := v.db.byID(CollectionUsers, boardingHouse["owner_id"])
+	if owner == nil {
+		return nil, nil
+	}
+
+	result := bson.M{
+		"_id":                     room["_id"],
+		"room_id":                 room["_id"],
+		"room_facilities":         pluck(v.lookup(CollectionFacilities, room["room_facilities"]), "name"),
+		"custom_facility_details": v.lookup(CollectionCustomFacilities, room["custom_facilities"]),
+	}
+	copyField(result, "boarding_house_name", boardingHouse, "name")
+	copyField(result, "owner_name", owner, "fullname")
+	return cloneAll([]bson.M{result})
+}
+
+func (v memViews) roomDetailPage(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	v.db.mu.RLock()
+	defer v.db.mu.RUnlock()
+
+	room := v.db.byID(CollectionRooms, id)
+	if room == nil {
+		return nil, nil
+	}
+	boardingHouse := v.db.byID(CollectionBoardingHouses, room["boarding_house_id"])
+	if boardingHouse == nil {
+		return nil, nil
+	}
+	owner := v.db.byID(CollectionUsers, boardingHouse["owner_id"])
+	category := v.db.byID(CollectionCategories, boardingHouse["category_id"])
+	if owner == nil || category == nil {
+		return nil, nil
+	}
+
+	result := bson.M{
+		"_id":               room["_id"],
+		"room_id":           room["_id"],
+		"boarding_house_id": boardingHouse["_id"],
+		"room_name":         concatStrings(boardingHouse["name"], " Tipe ", room["room_type"]),
+		"all_images":        concatArrays(room["images"], boardingHouse["images"]),
+		"facilities":        v.lookup(CollectionFacilities, boardingHouse["facilities_id"]),
+		"room_facilities":   v.lookup(CollectionFacilities, room["room_facilities"]),
+		"custom_facilities": v.lookup(CollectionCustomFacilities, room["custom_facilities"]),
+	}
+	copyField(result, "owner_id", boardingHouse, "owner_id")
+	copyField(result, "owner_fullname", owner, "fullname")
+	copyField(result, "category_name", category, "name")
+	copyField(result, "description", boardingHouse, "description")
+	copyField(result, "address", boardingHouse, "address")
+	copyField(result, "rules", boardingHouse, "rules")
+	for _, key := range []string{"price", "size", "number_available"} {
+		copyField(result, key, room, key)
+	}
+	return cloneAll([]bson.M{result})
+}
+
+func (v memViews) roomLandingPage(ctx context.Context, q query.Query) (query.Page[bson.M], error) {
+	if err := ctx.Err(); err != nil {
+		return query.Page[bson.M]{}, err
+	}
+	v.db.mu.RLock()
+	defer v.db.mu.RUnlock()
+
+	var results []bson.M
+	for _, room := range v.db.tables[CollectionRooms] {
+		boardingHouse := v.db.byID(CollectionBoardingHouses, room["boarding_house_id"])
+		if boardingHouse == nil {
+			boardingHouse = bson.M{}
+		}
+		category := v.db.byID(CollectionCategories, boardingHouse["category_id"])
+		if category == nil {
+			category = bson.M{}
+		}
+
+		result := bson.M{
+			"_id":       room["_id"],
+			"room_id":   room["_id"],
+			"room_name": concatStrings(boardingHouse["name"], " Tipe ", room["room_type"]),
+			"price":     landingPrice(room["price"]),
+			"images":    nil,
+			"status":    "Tidak Tersedia",
+		}
+		if images, ok := room["images"].(bson.A); ok {
+			result["images"] = images[:min(len(images), 1)]
+		}
+		if available, ok := toFloat(room["number_available"]); ok && available > 0 {
+			result["status"] = fmt.Sprintf("%v Kamar Tersedia", room["number_available"])
+		}
+		copyField(result, "address", boardingHouse, "address")
+		copyField(result, "category_name", category, "name")
+		copyField(result, "category_id", category, "_id")
+		copyField(result, "owner_id", boardingHouse, "owner_id")
+		results = append(results, result)
+	}
+
+	filter, err := toDoc(q.Filter)
+	if err != nil {
+		return query.Page[bson.M]{}, err
+	}
+	total, docs := pageDocs(results, filter, q)
+	page := trimPage(q, total, docs)
+	if page.Items, err = cloneAll(page.Items); err != nil {
+		return query.Page[bson.M]{}, err
+	}
+	return page, nil
+}
+
+func (v memViews) collectionVersion(ctx context.Context, name string) (collectionVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return collectionVersion{}, err
+	}
+	v.db.mu.RLock()
+	defer v.db.mu.RUnlock()
+	version := collectionVersion{Count: int64(len(v.db.tables[name]))}
+	for _, doc := range v.db.tables[name] {
+		if updated, ok := doc["updated_at"].(primitive.DateTime); ok && updated.Time().After(version.UpdatedAt) {
+			version.UpdatedAt = updated.Time()
+		}
+		if version.LastID == nil || compareValues(doc["_id"], version.LastID) > 0 {
+			version.LastID = doc["_id"]
+		}
+	}
+	return version, nil
+}
+
+// lookup meniru $lookup dengan localField berupa array _id; urutan hasil
+// mengikuti urutan dokumen di koleksi tujuan.
+func (v memViews) lookup(name string, ids interface{}) bson.A {
+	results := bson.A{}
+	wanted, ok := ids.(bson.A)
+	if !ok {
+		return results
+	}
+	for _, doc := range v.db.tables[name] {
+		for _, id := range wanted {
+			if doc["_id"] == id {
+				results = append(results, doc)
+				break
+			}
+		}
+	}
+	return results
+}
+
+// landingPrice memilih satu harga untuk landing page dengan urutan
+// quarterly, monthly, semi_annual lalu yearly.
+func landingPrice(price interface{}) bson.M {
+	prices, _ := price.(bson.M)
+	for _, key := range []string{"quarterly", "monthly", "semi_annual"} {
+		if value, ok := prices[key]; ok && value != nil {
+			return bson.M{key: value}
+		}
+	}
+	result := bson.M{}
+	copyField(result, "yearly", prices, "yearly")
+	return result
+}
+
+// cloneAll menyalin hasil agar tidak berbagi map dengan data yang tersimpan.
+func cloneAll(docs []bson.M) ([]bson.M, error) {
+	var clones []bson.M
+	for _, doc := range docs {
+		clone, err := toDoc(doc)
+		if err != nil {
+			return nil, err
+		}
+		clones = append(clones, clone)
+	}
+	return clones, nil
+}
+
+func copyField(dst bson.M, dstKey string, src bson.M, srcKey string) {
+	if value, ok := src[srcKey]; ok {
+		dst[dstKey] = value
+	}
+}
+
+func pluck(docs bson.A, key string) bson.A {
+	values := bson.A{}
+	for _, doc := range docs {
+		if value, ok := doc.(bson.M)[key]; ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// concatStrings meniru $concat: hasilnya null jika salah satu bagian tidak ada.
+func concatStrings(parts ...interface{}) interface{} {
+	var result string
+	for _, part := range parts {
+		s, ok := part.(string)
+		if !ok {
+			return nil
+		}
+		result += s
+	}
+	return result
+}
+
+// concatArrays meniru $concatArrays: hasilnya null jika salah satu array tidak ada.
+func concatArrays(arrays ...interface{}) interface{} {
+	result := bson.A{}
+	for _, array := range arrays {
+		values, ok := array.(bson.A)
+		if !ok {
+			return nil
+		}
+		result = append(result, values...)
+	}
+	return result
+}
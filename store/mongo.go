@@ -0,0 +1,288 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/metrics"
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// NewMongo membuat Stores yang membaca dan menulis ke database MongoDB.
+// Setiap operasi dibatasi timeout (0 berarti hanya mengikuti ctx pemanggil).
+func NewMongo(db *mongo.Database, timeout time.Duration) *Stores {
+	views := mongoViews{db: db, timeout: timeout}
+	return &Stores{
+		Users: &userStore{coll: newMongoCollection[models.User](db, CollectionUsers, timeout)},
+		BoardingHouses: &boardingHouseStore{
+			coll:  newMongoCollection[models.BoardingHouse](db, CollectionBoardingHouses, timeout),
+			views: views,
+		},
+		Rooms: &roomStore{
+			coll:  newMongoCollection[models.Room](db, CollectionRooms, timeout),
+			views: views,
+		},
+		Transactions:     &transactionStore{coll: newMongoCollection[models.Transaction](db, CollectionTransactions, timeout)},
+		Facilities:       &facilityStore{coll: newMongoCollection[models.Facility](db, CollectionFacilities, timeout)},
+		Categories:       &categoryStore{coll: newMongoCollection[models.Category](db, CollectionCategories, timeout)},
+		CustomFacilities: &customFacilityStore{coll: newMongoCollection[models.CustomFacility](db, CollectionCustomFacilities, timeout)},
+		Idempotency:      &idempotencyStore{coll: newMongoCollection[models.IdempotencyKey](db, CollectionIdempotencyKeys, timeout)},
+		Sessions:         &sessionStore{coll: newMongoCollection[models.Session](db, CollectionSessions, timeout)},
+		PasswordResets:   &passwordResetStore{coll: newMongoCollection[models.PasswordReset](db, CollectionPasswordResets, timeout)},
+		OAuthStates:      &oauthStateStore{coll: newMongoCollection[models.OAuthState](db, CollectionOAuthStates, timeout)},
+		ping: func(ctx context.Context) error {
+			ctx, cancel := withTimeout(ctx, timeout)
+			defer cancel()
+			defer metrics.ObserveMongo("", "ping", time.Now())
+			return mongoErr(db.Client().Ping(ctx, readpref.Primary()))
+		},
+		version: views.collectionVersion,
+	}
+}
+
+// withTimeout menurunkan ctx dengan batas waktu per operasi.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// mongoErr memastikan timeout dari driver bisa dikenali dengan
+// errors.Is(err, context.DeadlineExceeded) dan pelanggaran unique index
+// dengan errors.Is(err, ErrDuplicate) oleh pemanggil.
+func mongoErr(err error) error {
+	if err != nil && mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %v", ErrDuplicate, err)
+	}
+	if err != nil && mongo.IsTimeout(err) && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", context.DeadlineExceeded, err)
+	}
+	return err
+}
+
+type mongoCollection[T any] struct {
+	coll    *mongo.Collection
+	timeout time.Duration
+}
+
+func newMongoCollection[T any](db *mongo.Database, name string, timeout time.Duration) mongoCollection[T] {
+	return mongoCollection[T]{coll: db.Collection(name), timeout: timeout}
+}
+
+func (m mongoCollection[T]) insert(ctx context.Context, doc *T) error {
+	ctx, cancel := withTimeout(ctx, m.timeout)
+	defer cancel()
+	defer metrics.ObserveMongo(m.coll.Name(), "insert", time.Now())
+
+	_, err := m.coll.InsertOne(ctx, doc)
+	return mongoErr(err)
+}
+
+func (m mongoCollection[T]) findOne(ctx context.Context, filter bson.M) (*T, error) {
+	ctx, cancel := withTimeout(ctx, m.timeout)
+	defer cancel()
+	defer metrics.ObserveMongo(m.coll.Name(), "find_one", time.Now())
+
+	var doc T
+	if err := m.coll.FindOne(ctx, filter).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		return nil, mongoErr(err)
+	}
+	return &doc, nil
+}
+
+func (m mongoCollection[T]) find(ctx context.Context, filter bson.M) ([]T, error) {
+	ctx, cancel := withTimeout(ctx, m.timeout)
+	defer cancel()
+	defer metrics.ObserveMongo(m.coll.Name(), "find", time.Now())
+
+	cursor, err := m.coll.Find(ctx, filter)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []T
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, mongoErr(err)
+	}
+	return docs, nil
+}
+
+func (m mongoCollection[T]) list(ctx context.Context, filter bson.M, q query.Query) (query.Page[T], error) {
+	ctx, cancel := withTimeout(ctx, m.timeout)
+	defer cancel()
+	defer metrics.ObserveMongo(m.coll.Name(), "list", time.Now())
+
+	var page query.Page[T]
+	filter = q.Match(filter)
+	total, err := m.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return page, mongoErr(err)
+	}
+	page.Total = total
+
+	for key, value := range q.Seek() {
+		filter[key] = value
+	}
+	cursor, err := m.coll.Find(ctx, filter, q.FindOptions())
+	if err != nil {
+		return page, mongoErr(err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return page, mongoErr(err)
+	}
+	return decodePage[T](q, total, docs)
+}
+
+func (m mongoCollection[T]) update(ctx context.Context, filter, update bson.M) error {
+	ctx, cancel := withTimeout(ctx, m.timeout)
+	defer cancel()
+	defer metrics.ObserveMongo(m.coll.Name(), "update", time.Now())
+
+	res, err := m.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return mongoErr(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (m mongoCollection[T]) updateMany(ctx context.Context, filter, update bson.M) (int64, error) {
+	ctx, cancel := withTimeout(ctx, m.timeout)
+	defer cancel()
+	defer metrics.ObserveMongo(m.coll.Name(), "update_many", time.Now())
+
+	res, err := m.coll.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, mongoErr(err)
+	}
+	return res.MatchedCount, nil
+}
+
+func (m mongoCollection[T]) delete(ctx context.Context, filter bson.M) error {
+	ctx, cancel := withTimeout(ctx, m.timeout)
+	defer cancel()
+	defer metrics.ObserveMongo(m.coll.Name(), "delete", time.Now())
+
+	res, err := m.coll.DeleteOne(ctx, filter)
+	if err != nil {
+		return mongoErr(err)
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// mongoViews menjalankan pipeline agregasi di pipelines.go.
+type mongoViews struct {
+	db      *mongo.Database
+	timeout time.Duration
+}
+
+func (v mongoViews) aggregate(ctx context.Context, collectionName string, pipeline mongo.Pipeline) ([]bson.M, error) {
+	ctx, cancel := withTimeout(ctx, v.timeout)
+	defer cancel()
+	defer metrics.ObserveMongo(collectionName, "aggregate", time.Now())
+
+	cursor, err := v.db.Collection(collectionName).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mongoErr(err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, mongoErr(err)
+	}
+	return results, nil
+}
+
+// aggregatePage menjalankan pipeline ditambah q.Stages().
+func (v mongoViews) aggregatePage(ctx context.Context, collectionName string, pipeline mongo.Pipeline, q query.Query) (query.Page[bson.M], error) {
+	results, err := v.aggregate(ctx, collectionName, append(pipeline, q.Stages()...))
+	if err != nil || len(results) == 0 {
+		return query.Page[bson.M]{}, err
+	}
+
+	var facet struct {
+		Items []bson.M `bson:"items"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := fromDoc(results[0], &facet); err != nil {
+		return query.Page[bson.M]{}, err
+	}
+	var total int64
+	if len(facet.Total) > 0 {
+		total = facet.Total[0].Count
+	}
+	return trimPage(q, total, facet.Items), nil
+}
+
+// collectionVersion memakai jumlah dokumen dari metadata koleksi, satu
+// dokumen dengan updated_at terbaru (index updated_at, migrasi versi 5) dan
+// satu dokumen dengan _id terbesar.
+func (v mongoViews) collectionVersion(ctx context.Context, collectionName string) (collectionVersion, error) {
+	ctx, cancel := withTimeout(ctx, v.timeout)
+	defer cancel()
+	defer metrics.ObserveMongo(collectionName, "version", time.Now())
+
+	coll := v.db.Collection(collectionName)
+	count, err := coll.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return collectionVersion{}, mongoErr(err)
+	}
+	var latest struct {
+		UpdatedAt time.Time `bson:"updated_at"`
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "updated_at", Value: -1}}).SetProjection(bson.M{"updated_at": 1})
+	err = coll.FindOne(ctx, bson.M{}, opts).Decode(&latest)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return collectionVersion{}, mongoErr(err)
+	}
+
+	var last struct {
+		ID interface{} `bson:"_id"`
+	}
+	opts = options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}}).SetProjection(bson.M{"_id": 1})
+	err = coll.FindOne(ctx, bson.M{}, opts).Decode(&last)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return collectionVersion{}, mongoErr(err)
+	}
+	return collectionVersion{Count: count, UpdatedAt: latest.UpdatedAt, LastID: last.ID}, nil
+}
+
+func (v mongoViews) boardingHouseDetails(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	return v.aggregate(ctx, CollectionBoardingHouses, boardingHouseDetailsPipeline(id))
+}
+
+func (v mongoViews) roomDetails(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	return v.aggregate(ctx, CollectionRooms, roomDetailsPipeline(id))
+}
+
+func (v mongoViews) roomDetailPage(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	return v.aggregate(ctx, CollectionRooms, roomDetailPagePipeline(id))
+}
+
+func (v mongoViews) roomLandingPage(ctx context.Context, q query.Query) (query.Page[bson.M], error) {
+	return v.aggregatePage(ctx, CollectionRooms, roomLandingPagePipeline(), q)
+}
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"context"
+
+	"github.com/organisasi/kosconnectbackend/models"
+)
+
+// OAuthStateStore mencatat state OAuth yang sudah dipakai (koleksi
+// "oauth_states"). Record dihapus oleh TTL index setelah state-nya
+// kedaluwarsa, karena setelah itu cookie state sudah ditolak.
+type OAuthStateStore interface {
+	// Use mencatat state. ErrDuplicate jika state sudah pernah dipakai.
+	Use(ctx context.Context, state *models.OAuthState) error
+}
+
+type oauthStateStore struct {
+	coll collection[models.OAuthState]
+}
+
+func (s *oauthStateStore) Use(ctx context.Context, state *models.OAuthState) error {
+	return s.coll.insert(ctx, state)
+}
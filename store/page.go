@@ -0,0 +1,34 @@
+package store
+
+import (
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// trimPage membuang dokumen ke Limit+1 (hanya penanda masih ada halaman
+// berikutnya) dan membuat NextCursor dari dokumen terakhir yang tersisa.
+func trimPage(q query.Query, total int64, docs []bson.M) query.Page[bson.M] {
+	if docs == nil {
+		docs = []bson.M{}
+	}
+	page := query.Page[bson.M]{Items: docs, Total: total}
+	if len(docs) > q.Limit {
+		page.Items = docs[:q.Limit]
+		page.NextCursor = q.Next(page.Items[q.Limit-1])
+	}
+	return page
+}
+
+// decodePage seperti trimPage lalu mengubah dokumen menjadi model T.
+func decodePage[T any](q query.Query, total int64, docs []bson.M) (query.Page[T], error) {
+	trimmed := trimPage(q, total, docs)
+	page := query.Page[T]{Items: make([]T, 0, len(trimmed.Items)), Total: total, NextCursor: trimmed.NextCursor}
+	for _, doc := range trimmed.Items {
+		var out T
+		if err := fromDoc(doc, &out); err != nil {
+			return query.Page[T]{}, err
+		}
+		page.Items = append(page.Items, out)
+	}
+	return page, nil
+}
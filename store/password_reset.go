@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PasswordResetStore menyimpan token reset password (koleksi
+// "password_resets"). Token yang kedaluwarsa dihapus oleh TTL index di
+// MongoDB; pemanggil tetap harus mengecek PasswordReset.Usable.
+type PasswordResetStore interface {
+	Create(ctx context.Context, reset *models.PasswordReset) error
+	FindByTokenHash(ctx context.Context, hash string) (*models.PasswordReset, error)
+	// Use menandai token sudah dipakai. ErrNotFound jika token sudah
+	// dipakai lebih dulu, misal oleh request lain yang bersamaan.
+	Use(ctx context.Context, id primitive.ObjectID) error
+	// UseAll menandai semua token user yang belum dipakai, agar hanya link
+	// dari email terakhir yang berlaku.
+	UseAll(ctx context.Context, userID primitive.ObjectID) (int64, error)
+}
+
+type passwordResetStore struct {
+	coll collection[models.PasswordReset]
+}
+
+func (s *passwordResetStore) Create(ctx context.Context, reset *models.PasswordReset) error {
+	return s.coll.insert(ctx, reset)
+}
+
+func (s *passwordResetStore) FindByTokenHash(ctx context.Context, hash string) (*models.PasswordReset, error) {
+	return s.coll.findOne(ctx, bson.M{"token_hash": hash})
+}
+
+func (s *passwordResetStore) Use(ctx context.Context, id primitive.ObjectID) error {
+	return s.coll.update(ctx, bson.M{"_id": id, "used_at": nil}, bson.M{"$set": bson.M{"used_at": time.Now()}})
+}
+
+func (s *passwordResetStore) UseAll(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return s.coll.updateMany(ctx, bson.M{"user_id": userID, "used_at": nil}, bson.M{"$set": bson.M{"used_at": time.Now()}})
+}
@@ -0,0 +1,311 @@
+package store
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ownerLookup menggabungkan owner dari koleksi users ke field owner, tetapi
+// hanya membawa _id dan fullname. Password dan verification_token tidak
+// pernah masuk ke pipeline sehingga tidak bisa ikut ter-project.
+func ownerLookup(ownerIDPath string) bson.D {
+	return bson.D{
+		{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "users"},
+			{Key: "let", Value: bson.D{{Key: "owner_id", Value: ownerIDPath}}},
+			{Key: "pipeline", Value: mongo.Pipeline{
+				{{Key: "$match", Value: bson.D{{Key: "$expr", Value: bson.D{{Key: "$eq", Value: bson.A{"$_id", "$$owner_id"}}}}}}},
+				{{Key: "$project", Value: bson.D{{Key: "fullname", Value: 1}}}},
+			}},
+			{Key: "as", Value: "owner"},
+		}},
+	}
+}
+
+// boardingHouseDetailsPipeline mengambil nama kategori, nama owner, dan nama fasilitas sebuah kos
+func boardingHouseDetailsPipeline(objectID primitive.ObjectID) mongo.Pipeline {
+	// Pipeline untuk mengambil category_id, owner_id, dan facilities
+	return mongo.Pipeline{
+		{
+			{Key: "$match", Value: bson.D{
+				{Key: "_id", Value: objectID}, // Filter berdasarkan BoardingHouse ID
+			}},
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "categories"},        // Gabungkan dengan koleksi Categories
+				{Key: "localField", Value: "category_id"}, // Field referensi dari BoardingHouse
+				{Key: "foreignField", Value: "_id"},       // Field referensi di koleksi Categories
+				{Key: "as", Value: "category"},            // Hasil join disimpan dalam field category
+			}},
+		},
+		{
+			{Key: "$unwind", Value: bson.D{
+				{Key: "path", Value: "$category"}, // Unwind untuk mengubah array menjadi objek
+			}},
+		},
+		ownerLookup("$owner_id"), // Gabungkan dengan koleksi Users, hasil join di field owner
+		{
+			{Key: "$unwind", Value: bson.D{
+				{Key: "path", Value: "$owner"}, // Unwind untuk mengubah array menjadi objek
+			}},
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "facilities"},          // Gabungkan dengan koleksi Facilities
+				{Key: "localField", Value: "facilities_id"}, // Field referensi array dari BoardingHouse
+				{Key: "foreignField", Value: "_id"},         // Field referensi di koleksi Facilities
+				{Key: "as", Value: "facilities"},            // Hasil join disimpan dalam field facilities
+			}},
+		},
+		{
+			{Key: "$project", Value: bson.D{
+				{Key: "category_name", Value: "$category.name"},   // Ambil nama kategori
+				{Key: "owner_fullname", Value: "$owner.fullname"}, // Ambil fullname owner
+				{Key: "facilities", Value: "$facilities.name"},    // Ambil nama fasilitas
+			}},
+		},
+	}
+}
+
+// roomDetailsPipeline mengambil detail kamar beserta nama kos, owner, dan fasilitasnya
+func roomDetailsPipeline(objectID primitive.ObjectID) mongo.Pipeline {
+	// Pipeline untuk agregasi
+	return mongo.Pipeline{
+		{
+			{Key: "$match", Value: bson.D{{Key: "_id", Value: objectID}}}, // Match the room by ID
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "boardinghouses"},
+				{Key: "localField", Value: "boarding_house_id"},
+				{Key: "foreignField", Value: "_id"},
+				{Key: "as", Value: "boarding_house"},
+			}}, // Join with boardinghouses collection
+		},
+		{
+			{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$boarding_house"}}}, // Unwind the boarding house array
+		},
+		ownerLookup("$boarding_house.owner_id"), // Join with users collection to get owner name
+		{
+			{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$owner"}}}, // Unwind the owner array
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "facilities"},
+				{Key: "localField", Value: "room_facilities"},
+				{Key: "foreignField", Value: "_id"},
+				{Key: "as", Value: "room_facilities_details"},
+			}}, // Join with facilities collection
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "customFacility"},
+				{Key: "localField", Value: "custom_facilities"},
+				{Key: "foreignField", Value: "_id"},
+				{Key: "as", Value: "custom_facility_details"},
+			}}, // Join with customFacility collection
+		},
+		{
+			{Key: "$project", Value: bson.D{
+				{Key: "room_id", Value: "$_id"},
+				{Key: "boarding_house_name", Value: "$boarding_house.name"},      // Include boarding house name
+				{Key: "owner_name", Value: "$owner.fullname"},                    // Include owner name
+				{Key: "room_facilities", Value: "$room_facilities_details.name"}, // Include room facility names
+				{Key: "custom_facility_details", Value: "$custom_facility_details"},
+			}},
+		},
+	}
+}
+
+// roomDetailPagePipeline dipakai oleh halaman detail kamar di sisi publik
+func roomDetailPagePipeline(objectID primitive.ObjectID) mongo.Pipeline {
+	// Pipeline untuk menggabungkan data dan gambar, termasuk owner, kategori, fasilitas, dan custom fasilitas
+	return mongo.Pipeline{
+		{
+			{Key: "$match", Value: bson.D{
+				{Key: "_id", Value: objectID}, // Filter berdasarkan Room ID
+			}},
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "boardinghouses"},          // Gabungkan dengan koleksi BoardingHouse
+				{Key: "localField", Value: "boarding_house_id"}, // Field referensi dari koleksi Room
+				{Key: "foreignField", Value: "_id"},             // Field referensi di koleksi BoardingHouse
+				{Key: "as", Value: "boarding_house"},            // Hasil join disimpan dalam field boarding_house
+			}},
+		},
+		{
+			{Key: "$unwind", Value: bson.D{
+				{Key: "path", Value: "$boarding_house"}, // Unwind untuk mengubah array menjadi objek
+			}},
+		},
+		ownerLookup("$boarding_house.owner_id"), // Gabungkan dengan koleksi Users untuk mendapatkan Owner
+		{
+			{Key: "$unwind", Value: bson.D{
+				{Key: "path", Value: "$owner"}, // Unwind untuk mengubah array menjadi objek
+			}},
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "categories"},                       // Gabungkan dengan koleksi Categories untuk mendapatkan kategori
+				{Key: "localField", Value: "boarding_house.category_id"}, // Field referensi dari boarding_house
+				{Key: "foreignField", Value: "_id"},                      // Field referensi di koleksi Categories
+				{Key: "as", Value: "category"},                           // Hasil join disimpan dalam field category
+			}},
+		},
+		{
+			{Key: "$unwind", Value: bson.D{
+				{Key: "path", Value: "$category"}, // Unwind untuk mengubah array menjadi objek
+			}},
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "facilities"}, // Gabungkan dengan koleksi Facilities
+				{Key: "localField", Value: "boarding_house.facilities_id"},
+				{Key: "foreignField", Value: "_id"},
+				{Key: "as", Value: "facilities"}, // Hasil join disimpan dalam field room_facilities
+			}},
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "facilities"}, // Gabungkan dengan koleksi Facilities
+				{Key: "localField", Value: "room_facilities"},
+				{Key: "foreignField", Value: "_id"},
+				{Key: "as", Value: "room_facilities"}, // Hasil join disimpan dalam field room_facilities
+			}},
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "customFacility"}, // Gabungkan dengan koleksi Custom Facilities
+				{Key: "localField", Value: "custom_facilities"},
+				{Key: "foreignField", Value: "_id"},
+				{Key: "as", Value: "custom_facilities"}, // Hasil join disimpan dalam field custom_facilities
+			}},
+		},
+		{
+			{Key: "$addFields", Value: bson.D{
+				{Key: "all_images", Value: bson.D{
+					{Key: "$concatArrays", Value: bson.A{"$images", "$boarding_house.images"}}, // Gabungkan gambar
+				}},
+				{Key: "owner_id", Value: "$boarding_house.owner_id"},
+				{Key: "owner_fullname", Value: "$owner.fullname"},          // Tambahkan fullname dari owner
+				{Key: "category_name", Value: "$category.name"},            // Tambahkan nama kategori
+				{Key: "rules", Value: "$boarding_house.rules"},             // Tambahkan nama kategori
+				{Key: "description", Value: "$boarding_house.description"}, // Tambahkan nama kategori
+				{Key: "address", Value: "$boarding_house.address"},         // Tambahkan nama kategori
+				{Key: "room_name", Value: bson.D{
+					{Key: "$concat", Value: bson.A{"$boarding_house.name", " Tipe ", "$room_type"}}, // Gabungkan nama kos dan tipe kamar
+				}},
+			}},
+		},
+		{
+			{Key: "$project", Value: bson.D{
+				{Key: "room_id", Value: "$_id"},
+				{Key: "boarding_house_id", Value: "$boarding_house._id"},
+				{Key: "owner_id", Value: 1},
+				{Key: "room_name", Value: 1},
+				{Key: "all_images", Value: 1},
+				{Key: "owner_fullname", Value: 1},
+				{Key: "category_name", Value: 1},
+				{Key: "facilities", Value: "$facilities"},
+				{Key: "room_facilities", Value: "$room_facilities"},
+				{Key: "custom_facilities", Value: "$custom_facilities"},
+				{Key: "price", Value: "$price"},
+				{Key: "description", Value: 1},
+				{Key: "address", Value: 1},
+				{Key: "size", Value: 1},
+				{Key: "rules", Value: 1},
+				{Key: "number_available", Value: 1},
+			}},
+		},
+	}
+}
+
+// roomLandingPagePipeline menampilkan ringkasan semua kamar untuk landing page
+func roomLandingPagePipeline() mongo.Pipeline {
+	// Define aggregation pipeline
+	return mongo.Pipeline{
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "boardinghouses"},          // Join dengan koleksi BoardingHouse
+				{Key: "localField", Value: "boarding_house_id"}, // Field referensi dari koleksi Room
+				{Key: "foreignField", Value: "_id"},             // Field referensi di BoardingHouse
+				{Key: "as", Value: "boarding_house"},            // Hasil join disimpan di boarding_house
+			}},
+		},
+		{
+			{Key: "$unwind", Value: bson.D{
+				{Key: "path", Value: "$boarding_house"},          // Unwind array ke objek
+				{Key: "preserveNullAndEmptyArrays", Value: true}, // Pastikan tetap ada meskipun boarding house kosong
+			}},
+		},
+		{
+			{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "categories"},                       // Join dengan koleksi Categories
+				{Key: "localField", Value: "boarding_house.category_id"}, // Referensi kategori
+				{Key: "foreignField", Value: "_id"},
+				{Key: "as", Value: "category"},
+			}},
+		},
+		{
+			{Key: "$unwind", Value: bson.D{
+				{Key: "path", Value: "$category"},                // Unwind array ke objek
+				{Key: "preserveNullAndEmptyArrays", Value: true}, // Pastikan tetap ada meskipun kategori kosong
+			}},
+		},
+		{
+			{Key: "$project", Value: bson.D{
+				{Key: "room_id", Value: "$_id"}, // Tambahkan room_id
+				{Key: "room_name", Value: bson.D{
+					{Key: "$concat", Value: bson.A{"$boarding_house.name", " Tipe ", "$room_type"}},
+				}}, // Nama kamar gabungan
+				{Key: "address", Value: "$boarding_house.address"}, // Alamat kos
+				{Key: "price", Value: bson.D{
+					{Key: "$cond", Value: bson.D{
+						{Key: "if", Value: bson.D{{Key: "$gt", Value: bson.A{"$price.quarterly", nil}}}},
+						{Key: "then", Value: bson.D{
+							{Key: "quarterly", Value: "$price.quarterly"},
+						}},
+						{Key: "else", Value: bson.D{
+							{Key: "$cond", Value: bson.D{
+								{Key: "if", Value: bson.D{{Key: "$gt", Value: bson.A{"$price.monthly", nil}}}},
+								{Key: "then", Value: bson.D{
+									{Key: "monthly", Value: "$price.monthly"},
+								}},
+								{Key: "else", Value: bson.D{
+									{Key: "$cond", Value: bson.D{
+										{Key: "if", Value: bson.D{{Key: "$gt", Value: bson.A{"$price.semi_annual", nil}}}},
+										{Key: "then", Value: bson.D{
+											{Key: "semi_annual", Value: "$price.semi_annual"},
+										}},
+										{Key: "else", Value: bson.D{
+											{Key: "yearly", Value: "$price.yearly"},
+										}},
+									}},
+								}},
+							}},
+						}},
+					}},
+				}},
+				{Key: "category_name", Value: "$category.name"}, // Nama kategori
+				{Key: "category_id", Value: "$category._id"},    // ID kategori
+				{Key: "images", Value: bson.D{
+					{Key: "$slice", Value: bson.A{"$images", 1}}, // Gambar pertama
+				}},
+				{Key: "status", Value: bson.D{ // Hitung Status
+					{Key: "$cond", Value: bson.A{
+						bson.D{{Key: "$gt", Value: bson.A{"$number_available", 0}}},
+						bson.D{{Key: "$concat", Value: bson.A{
+							bson.D{{Key: "$toString", Value: "$number_available"}},
+							" Kamar Tersedia",
+						}}},
+						"Tidak Tersedia",
+					}},
+				}},
+				{Key: "owner_id", Value: "$boarding_house.owner_id"},
+			}},
+		},
+	}
+}
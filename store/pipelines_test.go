@@ -0,0 +1,87 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TestPipelinesProjectOwner memeriksa pipeline agregasi MongoDB yang tidak
+// ikut dijalankan oleh store memori: setiap $lookup ke users hanya
+// mem-project fullname, setiap pipeline diakhiri $project, dan tidak ada
+// stage yang menyalin dokumen owner utuh atau menyebut field rahasia.
+func TestPipelinesProjectOwner(t *testing.T) {
+	id := primitive.NewObjectID()
+	for name, pipeline := range map[string]mongo.Pipeline{
+		"boardingHouseDetails": boardingHouseDetailsPipeline(id),
+		"roomDetails":          roomDetailsPipeline(id),
+		"roomDetailPage":       roomDetailPagePipeline(id),
+		"roomLandingPage":      roomLandingPagePipeline(),
+	} {
+		last := pipeline[len(pipeline)-1]
+		if last[0].Key != "$project" {
+			t.Errorf("%s: last stage is %s, want $project so unlisted fields are dropped", name, last[0].Key)
+		}
+		for _, value := range last[0].Value.(bson.D) {
+			if value.Value == 0 || value.Value == false {
+				t.Errorf("%s: final $project excludes %s; only inclusion projections are allowed", name, value.Key)
+			}
+		}
+
+		walkStages(pipeline, func(stage bson.E) {
+			switch stage.Key {
+			case "$lookup":
+				lookup := stage.Value.(bson.D)
+				if lookupField(lookup, "from") != CollectionUsers {
+					return
+				}
+				inner, ok := lookupField(lookup, "pipeline").(mongo.Pipeline)
+				if !ok || len(inner) == 0 {
+					t.Errorf("%s: $lookup from users without a pipeline returns whole user documents", name)
+					return
+				}
+				project := inner[len(inner)-1]
+				want := bson.D{{Key: "$project", Value: bson.D{{Key: "fullname", Value: 1}}}}
+				if fmt.Sprint(project) != fmt.Sprint(want) {
+					t.Errorf("%s: users $lookup ends with %v, want %v", name, project, want)
+				}
+			case "$project", "$addFields":
+				for _, field := range stage.Value.(bson.D) {
+					text := fmt.Sprint(field.Value)
+					if field.Value == "$owner" || strings.Contains(text, "password") || strings.Contains(text, "verification_token") {
+						t.Errorf("%s: %s %s = %v exposes user fields", name, stage.Key, field.Key, field.Value)
+					}
+				}
+			}
+		})
+	}
+}
+
+// walkStages memanggil fn untuk setiap stage, termasuk stage di dalam
+// pipeline $lookup.
+func walkStages(pipeline mongo.Pipeline, fn func(bson.E)) {
+	for _, stage := range pipeline {
+		for _, e := range stage {
+			fn(e)
+			if e.Key != "$lookup" {
+				continue
+			}
+			if inner, ok := lookupField(e.Value.(bson.D), "pipeline").(mongo.Pipeline); ok {
+				walkStages(inner, fn)
+			}
+		}
+	}
+}
+
+func lookupField(lookup bson.D, key string) interface{} {
+	for _, e := range lookup {
+		if e.Key == key {
+			return e.Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RoomStore menyimpan data kamar (koleksi "rooms") beserta agregasi
+// untuk halaman publik.
+type RoomStore interface {
+	Create(ctx context.Context, room *models.Room) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Room, error)
+	FindAll(ctx context.Context) ([]models.Room, error)
+	List(ctx context.Context, q query.Query) (query.Page[models.Room], error)
+	FindByBoardingHouse(ctx context.Context, boardingHouseID primitive.ObjectID) ([]models.Room, error)
+	Details(ctx context.Context, id primitive.ObjectID) ([]bson.M, error)
+	DetailPage(ctx context.Context, id primitive.ObjectID) ([]bson.M, error)
+	LandingPage(ctx context.Context, q query.Query) (query.Page[bson.M], error)
+	Update(ctx context.Context, id primitive.ObjectID, set interface{}) error
+	// DecrementAvailable mengurangi number_available satu, hanya jika masih
+	// lebih dari 0. ErrNotFound jika kamar tidak ada atau sudah penuh.
+	DecrementAvailable(ctx context.Context, id primitive.ObjectID) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// roomViews menjalankan query gabungan (join) untuk kamar.
+type roomViews interface {
+	roomDetails(ctx context.Context, id primitive.ObjectID) ([]bson.M, error)
+	roomDetailPage(ctx context.Context, id primitive.ObjectID) ([]bson.M, error)
+	roomLandingPage(ctx context.Context, q query.Query) (query.Page[bson.M], error)
+}
+
+type roomStore struct {
+	coll  collection[models.Room]
+	views roomViews
+}
+
+func (s *roomStore) Create(ctx context.Context, room *models.Room) error {
+	return s.coll.insert(ctx, room)
+}
+
+func (s *roomStore) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Room, error) {
+	return s.coll.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *roomStore) FindAll(ctx context.Context) ([]models.Room, error) {
+	return s.coll.find(ctx, bson.M{})
+}
+
+func (s *roomStore) List(ctx context.Context, q query.Query) (query.Page[models.Room], error) {
+	return s.coll.list(ctx, bson.M{}, q)
+}
+
+func (s *roomStore) FindByBoardingHouse(ctx context.Context, boardingHouseID primitive.ObjectID) ([]models.Room, error) {
+	return s.coll.find(ctx, bson.M{"boarding_house_id": boardingHouseID})
+}
+
+func (s *roomStore) Details(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	return s.views.roomDetails(ctx, id)
+}
+
+func (s *roomStore) DetailPage(ctx context.Context, id primitive.ObjectID) ([]bson.M, error) {
+	return s.views.roomDetailPage(ctx, id)
+}
+
+func (s *roomStore) LandingPage(ctx context.Context, q query.Query) (query.Page[bson.M], error) {
+	return s.views.roomLandingPage(ctx, q)
+}
+
+func (s *roomStore) Update(ctx context.Context, id primitive.ObjectID, set interface{}) error {
+	return s.coll.update(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+}
+
+func (s *roomStore) DecrementAvailable(ctx context.Context, id primitive.ObjectID) error {
+	// Syarat $gt ada di filter agar cek dan pengurangan atomik: dua booking
+	// bersamaan untuk kamar terakhir tidak bisa sama-sama lolos
+	return s.coll.update(ctx, bson.M{"_id": id, "number_available": bson.M{"$gt": 0}}, bson.M{
+		"$inc": bson.M{"number_available": -1},
+		"$set": bson.M{"updated_at": time.Now()}, // Status di landing page ikut berubah
+	})
+}
+
+func (s *roomStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return s.coll.delete(ctx, bson.M{"_id": id})
+}
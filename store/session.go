@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SessionStore menyimpan session login (koleksi "sessions"). Session yang
+// kedaluwarsa dihapus oleh TTL index di MongoDB; pemanggil tetap harus
+// mengecek Session.Active.
+type SessionStore interface {
+	Create(ctx context.Context, session *models.Session) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Session, error)
+	// Rotate mengganti refresh_hash hanya jika hash lama masih cocok dan
+	// session belum dicabut. ErrNotFound jika tidak, misal karena refresh
+	// token yang sama sudah dipakai request lain.
+	Rotate(ctx context.Context, id primitive.ObjectID, oldHash, newHash string, expiresAt time.Time) error
+	// Revoke mencabut satu session. ErrNotFound jika sudah dicabut.
+	Revoke(ctx context.Context, id primitive.ObjectID) error
+	// RevokeAll mencabut semua session aktif milik user.
+	RevokeAll(ctx context.Context, userID primitive.ObjectID) (int64, error)
+}
+
+type sessionStore struct {
+	coll collection[models.Session]
+}
+
+func (s *sessionStore) Create(ctx context.Context, session *models.Session) error {
+	return s.coll.insert(ctx, session)
+}
+
+func (s *sessionStore) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Session, error) {
+	return s.coll.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *sessionStore) Rotate(ctx context.Context, id primitive.ObjectID, oldHash, newHash string, expiresAt time.Time) error {
+	return s.coll.update(ctx,
+		bson.M{"_id": id, "refresh_hash": oldHash, "revoked_at": nil},
+		bson.M{"$set": bson.M{"refresh_hash": newHash, "refreshed_at": time.Now(), "expires_at": expiresAt}},
+	)
+}
+
+func (s *sessionStore) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	return s.coll.update(ctx, bson.M{"_id": id, "revoked_at": nil}, bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+}
+
+func (s *sessionStore) RevokeAll(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return s.coll.updateMany(ctx, bson.M{"user_id": userID, "revoked_at": nil}, bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Nama koleksi MongoDB yang dipakai aplikasi
+const (
+	CollectionUsers            = "users"
+	CollectionBoardingHouses   = "boardinghouses"
+	CollectionRooms            = "rooms"
+	CollectionTransactions     = "transactions"
+	CollectionFacilities       = "facilities"
+	CollectionCategories       = "categories"
+	CollectionCustomFacilities = "customFacility"
+	CollectionIdempotencyKeys  = "idempotency_keys"
+	CollectionSessions         = "sessions"
+	CollectionPasswordResets   = "password_resets"
+	CollectionOAuthStates      = "oauth_states"
+)
+
+// ErrNotFound dikembalikan ketika dokumen yang dicari tidak ada
+// (atau tidak ada dokumen yang cocok dengan filter update/delete).
+var ErrNotFound = errors.New("store: document not found")
+
+// ErrDuplicate dikembalikan ketika insert/update melanggar unique index,
+// misal email yang sudah terdaftar (lihat package migrations).
+var ErrDuplicate = errors.New("store: duplicate key")
+
+// Stores mengelompokkan semua repository yang dibutuhkan controller.
+type Stores struct {
+	Users            UserStore
+	BoardingHouses   BoardingHouseStore
+	Rooms            RoomStore
+	Transactions     TransactionStore
+	Facilities       FacilityStore
+	Categories       CategoryStore
+	CustomFacilities CustomFacilityStore
+	Idempotency      IdempotencyStore
+	Sessions         SessionStore
+	PasswordResets   PasswordResetStore
+	OAuthStates      OAuthStateStore
+
+	ping    func(ctx context.Context) error
+	version func(ctx context.Context, collection string) (collectionVersion, error)
+}
+
+// Ping memeriksa apakah database bisa dihubungi. Dipakai oleh /readyz.
+func (s *Stores) Ping(ctx context.Context) error {
+	if s.ping == nil {
+		return nil
+	}
+	return s.ping(ctx)
+}
+
+// collection adalah operasi dasar yang dibutuhkan store per koleksi.
+// Filter dan update memakai dokumen bson (mis. {"$set": ...}) agar
+// implementasi Mongo dan in-memory berperilaku sama.
+type collection[T any] interface {
+	insert(ctx context.Context, doc *T) error
+	findOne(ctx context.Context, filter bson.M) (*T, error)
+	find(ctx context.Context, filter bson.M) ([]T, error)
+	// list mengembalikan satu halaman dokumen yang cocok dengan filter
+	// ditambah filter, sort dan pagination dari q.
+	list(ctx context.Context, filter bson.M, q query.Query) (query.Page[T], error)
+	update(ctx context.Context, filter, update bson.M) error
+	// updateMany mengubah semua dokumen yang cocok dan mengembalikan jumlahnya.
+	updateMany(ctx context.Context, filter, update bson.M) (int64, error)
+	delete(ctx context.Context, filter bson.M) error
+}
+
+// scoped membuat filter berdasarkan _id dan menambahkan owner_id jika
+// ownerID diisi.
+func scoped(id, ownerID primitive.ObjectID) bson.M {
+	filter := bson.M{"_id": id}
+	if !ownerID.IsZero() {
+		filter["owner_id"] = ownerID
+	}
+	return filter
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TransactionStore menyimpan data transaksi (koleksi "transactions").
+type TransactionStore interface {
+	Create(ctx context.Context, transaction *models.Transaction) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Transaction, error)
+	FindAll(ctx context.Context) ([]models.Transaction, error)
+	List(ctx context.Context, q query.Query) (query.Page[models.Transaction], error)
+	FindByUser(ctx context.Context, userID primitive.ObjectID) ([]models.Transaction, error)
+	FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]models.Transaction, error)
+	FindByPaymentStatus(ctx context.Context, status string) ([]models.Transaction, error)
+	FindByRoom(ctx context.Context, roomID primitive.ObjectID) ([]models.Transaction, error)
+	Update(ctx context.Context, id primitive.ObjectID, set interface{}) error
+	UpdateByCode(ctx context.Context, code string, set interface{}) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// ActivePaymentStatuses adalah status transaksi yang masih memakai kamar.
+// Status dari Midtrans (settlement, capture) dan dari admin (paid) dianggap
+// sama; expire, deny, cancel, failed dan cancelled melepas kamar.
+var ActivePaymentStatuses = []string{"pending", "paid", "settlement", "capture"}
+
+// CountActive menghitung transaksi yang masih memakai kamar.
+func CountActive(transactions []models.Transaction) int {
+	n := 0
+	for _, t := range transactions {
+		for _, status := range ActivePaymentStatuses {
+			if t.PaymentStatus == status {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+type transactionStore struct {
+	coll collection[models.Transaction]
+}
+
+func (s *transactionStore) Create(ctx context.Context, transaction *models.Transaction) error {
+	return s.coll.insert(ctx, transaction)
+}
+
+func (s *transactionStore) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Transaction, error) {
+	return s.coll.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *transactionStore) FindAll(ctx context.Context) ([]models.Transaction, error) {
+	return s.coll.find(ctx, bson.M{})
+}
+
+func (s *transactionStore) List(ctx context.Context, q query.Query) (query.Page[models.Transaction], error) {
+	return s.coll.list(ctx, bson.M{}, q)
+}
+
+func (s *transactionStore) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]models.Transaction, error) {
+	return s.coll.find(ctx, bson.M{"user_id": userID})
+}
+
+func (s *transactionStore) FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]models.Transaction, error) {
+	return s.coll.find(ctx, bson.M{"owner_id": ownerID})
+}
+
+func (s *transactionStore) FindByPaymentStatus(ctx context.Context, status string) ([]models.Transaction, error) {
+	return s.coll.find(ctx, bson.M{"payment_status": status})
+}
+
+func (s *transactionStore) FindByRoom(ctx context.Context, roomID primitive.ObjectID) ([]models.Transaction, error) {
+	return s.coll.find(ctx, bson.M{"room_id": roomID})
+}
+
+func (s *transactionStore) Update(ctx context.Context, id primitive.ObjectID, set interface{}) error {
+	return s.coll.update(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+}
+
+func (s *transactionStore) UpdateByCode(ctx context.Context, code string, set interface{}) error {
+	return s.coll.update(ctx, bson.M{"transaction_code": code}, bson.M{"$set": set})
+}
+
+func (s *transactionStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return s.coll.delete(ctx, bson.M{"_id": id})
+}
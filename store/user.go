@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/organisasi/kosconnectbackend/models"
+	"github.com/organisasi/kosconnectbackend/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserStore menyimpan data pengguna (koleksi "users").
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByVerificationToken(ctx context.Context, token string) (*models.User, error)
+	FindAll(ctx context.Context) ([]models.User, error)
+	List(ctx context.Context, q query.Query) (query.Page[models.User], error)
+	ListByRole(ctx context.Context, role string, q query.Query) (query.Page[models.User], error)
+	FindByRole(ctx context.Context, role string) ([]models.User, error)
+	Update(ctx context.Context, id primitive.ObjectID, set interface{}) error
+	UpdateByEmail(ctx context.Context, email string, set interface{}) error
+	// SetVerificationToken mengganti token verifikasi email, sehingga link
+	// dari email sebelumnya tidak berlaku lagi.
+	SetVerificationToken(ctx context.Context, id primitive.ObjectID, token string, expiresAt time.Time) error
+	// MarkEmailVerified menandai email terverifikasi. Token tetap disimpan
+	// agar link yang dibuka ulang bisa dijawab "sudah terverifikasi".
+	MarkEmailVerified(ctx context.Context, id primitive.ObjectID) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type userStore struct {
+	coll collection[models.User]
+}
+
+func (s *userStore) Create(ctx context.Context, user *models.User) error {
+	return s.coll.insert(ctx, user)
+}
+
+func (s *userStore) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	return s.coll.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *userStore) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.coll.findOne(ctx, bson.M{"email": email})
+}
+
+func (s *userStore) FindByVerificationToken(ctx context.Context, token string) (*models.User, error) {
+	return s.coll.findOne(ctx, bson.M{"verification_token": token})
+}
+
+func (s *userStore) FindAll(ctx context.Context) ([]models.User, error) {
+	return s.coll.find(ctx, bson.M{})
+}
+
+func (s *userStore) List(ctx context.Context, q query.Query) (query.Page[models.User], error) {
+	return s.coll.list(ctx, bson.M{}, q)
+}
+
+func (s *userStore) ListByRole(ctx context.Context, role string, q query.Query) (query.Page[models.User], error) {
+	return s.coll.list(ctx, bson.M{"role": role}, q)
+}
+
+func (s *userStore) FindByRole(ctx context.Context, role string) ([]models.User, error) {
+	return s.coll.find(ctx, bson.M{"role": role})
+}
+
+func (s *userStore) Update(ctx context.Context, id primitive.ObjectID, set interface{}) error {
+	return s.coll.update(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+}
+
+func (s *userStore) UpdateByEmail(ctx context.Context, email string, set interface{}) error {
+	return s.coll.update(ctx, bson.M{"email": email}, bson.M{"$set": set})
+}
+
+func (s *userStore) SetVerificationToken(ctx context.Context, id primitive.ObjectID, token string, expiresAt time.Time) error {
+	now := time.Now()
+	return s.coll.update(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"verification_token":      token,
+		"verification_expires_at": expiresAt,
+		"verification_sent_at":    now,
+		"updated_at":              now,
+	}})
+}
+
+func (s *userStore) MarkEmailVerified(ctx context.Context, id primitive.ObjectID) error {
+	return s.coll.update(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"verified_email": true, "updated_at": time.Now()},
+	})
+}
+
+func (s *userStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return s.coll.delete(ctx, bson.M{"_id": id})
+}
@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// collectionVersion meringkas isi satu koleksi: jumlah dokumen, updated_at
+// terbaru dan _id terbesar.
+type collectionVersion struct {
+	Count     int64
+	UpdatedAt time.Time
+	LastID    interface{}
+}
+
+// Version meringkas isi koleksi-koleksi yang dibaca sebuah endpoint,
+// dipakai sebagai dasar ETag (lihat middlewares.ETag). Hasilnya berubah
+// setiap ada insert, delete, atau update yang mengisi updated_at, jadi
+// setiap update ke koleksi ini harus ikut mengisi updated_at.
+//
+// Jumlah dokumen saja tidak cukup untuk delete lalu insert, dan updated_at
+// dokumen baru bisa sama dengan yang dihapus (milidetik yang sama, atau
+// nilai dari fixture/import). _id terbesar menangkapnya karena ObjectID
+// baru selalu lebih besar dari yang sudah ada.
+//
+// Resolusi updated_at adalah milidetik seperti di MongoDB; dua update pada
+// milidetik yang sama tanpa perubahan jumlah dokumen tidak terbedakan.
+func (s *Stores) Version(ctx context.Context, collections ...string) (string, error) {
+	parts := make([]string, 0, len(collections))
+	for _, name := range collections {
+		v, err := s.version(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("store: version of %s: %w", name, err)
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d:%d:%v", name, v.Count, v.UpdatedAt.UnixMilli(), v.LastID))
+	}
+	return strings.Join(parts, ";"), nil
+}
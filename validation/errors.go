@@ -0,0 +1,137 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError adalah satu kegagalan validasi, dikirim di error.details.fields.
+// Field memakai nama dari tag json/form, field bersarang dipisah titik
+// (misal "personal_info.email"). Field kosong berarti request secara umum.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Errors menerjemahkan error dari ShouldBind* menjadi daftar FieldError.
+func Errors(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, FieldError{Field: fieldName(fe), Rule: fe.Tag(), Message: message(fe)})
+		}
+		return fields
+	case errors.As(err, &typeErr):
+		return []FieldError{{Field: typeErr.Field, Rule: "type", Message: "must be " + typeName(typeErr.Type)}}
+	case errors.Is(err, io.EOF):
+		return []FieldError{{Rule: "required", Message: "request body is required"}}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return []FieldError{{Rule: "json", Message: "request body must be valid JSON"}}
+	}
+	return []FieldError{{Rule: "invalid", Message: "request could not be parsed"}}
+}
+
+// fieldName membuang nama struct di depan namespace: "RegisterRequest.email" -> "email".
+func fieldName(fe validator.FieldError) string {
+	_, name, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return name
+}
+
+func message(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", param)
+		}
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at least %s items", param)
+		}
+		return "must be at least " + param
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", param)
+		}
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at most %s items", param)
+		}
+		return "must be at most " + param
+	case "gte":
+		return "must be greater than or equal to " + param
+	case "gt":
+		return "must be greater than " + param
+	case "number", "numeric":
+		return "must be a whole number without separators"
+	case "json":
+		return "must be valid JSON"
+	case "nefield":
+		return "must be different from " + snakeCase(param)
+	case "objectid":
+		return "must be a 24-character hex ObjectID"
+	case "phone_id":
+		return "must be an Indonesian phone number, e.g. 081234567890 or +6281234567890"
+	case "payment_term":
+		return "must be one of: " + strings.Join(PaymentTerms, ", ")
+	case "future_date":
+		return "must be a date in YYYY-MM-DD format that is today or later"
+	}
+	return fmt.Sprintf("failed on the '%s' rule", fe.Tag())
+}
+
+// typeName memberi nama tipe JSON untuk tipe Go.
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
+
+// snakeCase mengubah nama field Go di param (misal nefield=OldPassword)
+// menjadi nama JSON-nya, "old_password".
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
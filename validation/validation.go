@@ -0,0 +1,102 @@
+// Package validation memasang validator kustom ke binding gin dan
+// menerjemahkan error binding menjadi error per field.
+//
+// DTO di package dto memakai tag `binding` seperti biasa, ditambah tag kustom:
+//
+//	objectid      string berupa ObjectID hex 24 karakter
+//	phone_id      nomor HP Indonesia: 08xx, 628xx atau +628xx
+//	payment_term  monthly, quarterly, semi_annual atau yearly
+//	future_date   tanggal YYYY-MM-DD (atau time.Time) hari ini atau setelahnya
+package validation
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DateLayout adalah format tanggal yang diterima dari client, misal check_in_date.
+const DateLayout = "2006-01-02"
+
+// PaymentTerms adalah periode sewa yang bisa dipilih saat membuat transaksi.
+var PaymentTerms = []string{"monthly", "quarterly", "semi_annual", "yearly"}
+
+// Nomor HP Indonesia: awalan 0, 62 atau +62, lalu 8 dan 7-11 digit berikutnya.
+// Spasi dan tanda hubung diabaikan.
+var phoneID = regexp.MustCompile(`^(\+62|62|0)8[1-9][0-9]{6,10}$`)
+
+var setupOnce sync.Once
+
+// Setup memasang validator kustom dan nama field dari tag json/form ke
+// validator bawaan gin. Dipanggil server.NewServer, aman dipanggil berkali-kali.
+func Setup() {
+	setupOnce.Do(func() {
+		if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+			register(v)
+		}
+	})
+}
+
+func register(v *validator.Validate) {
+	// Pakai nama field dari tag json atau form agar sama dengan yang dikirim client
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+
+	v.RegisterValidation("objectid", func(fl validator.FieldLevel) bool {
+		return primitive.IsValidObjectID(fl.Field().String())
+	})
+	v.RegisterValidation("phone_id", func(fl validator.FieldLevel) bool {
+		return ValidPhone(fl.Field().String())
+	})
+	v.RegisterValidation("payment_term", func(fl validator.FieldLevel) bool {
+		return ValidPaymentTerm(fl.Field().String())
+	})
+	v.RegisterValidation("future_date", func(fl validator.FieldLevel) bool {
+		switch value := fl.Field().Interface().(type) {
+		case time.Time:
+			return notBeforeToday(value)
+		case string:
+			date, err := time.ParseInLocation(DateLayout, value, time.Local)
+			return err == nil && notBeforeToday(date)
+		}
+		return false
+	})
+}
+
+// ValidPhone mengecek nomor HP Indonesia.
+func ValidPhone(phone string) bool {
+	phone = strings.NewReplacer(" ", "", "-", "").Replace(phone)
+	return phoneID.MatchString(phone)
+}
+
+// ValidPaymentTerm mengecek apakah term ada di PaymentTerms.
+func ValidPaymentTerm(term string) bool {
+	for _, t := range PaymentTerms {
+		if term == t {
+			return true
+		}
+	}
+	return false
+}
+
+func notBeforeToday(t time.Time) bool {
+	y, m, d := time.Now().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	return !t.Before(today)
+}
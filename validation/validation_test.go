@@ -0,0 +1,164 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// newValidator membuat validator seperti milik gin (tag `binding`) tanpa
+// menyentuh binding.Validator global.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	register(v)
+	return v
+}
+
+func TestCustomValidators(t *testing.T) {
+	v := newValidator()
+	today := time.Now()
+	for _, tc := range []struct {
+		tag   string
+		value any
+		valid bool
+	}{
+		{"objectid", "65a1b2c3d4e5f60718293a4b", true},
+		{"objectid", "65A1B2C3D4E5F60718293A4B", true},
+		{"objectid", "65a1b2c3d4e5f60718293a4", false},
+		{"objectid", "65a1b2c3d4e5f60718293a4g", false},
+		{"objectid", "", false},
+
+		{"phone_id", "081234567890", true},
+		{"phone_id", "6281234567890", true},
+		{"phone_id", "+6281234567890", true},
+		{"phone_id", "0812-3456-7890", true},
+		{"phone_id", "0812 3456 7890", true},
+		{"phone_id", "08123456", false},         // terlalu pendek
+		{"phone_id", "0812345678901234", false}, // terlalu panjang
+		{"phone_id", "0801234567", false},       // 080 bukan awalan operator
+		{"phone_id", "071234567890", false},     // bukan nomor HP
+		{"phone_id", "+6581234567", false},      // kode negara lain
+		{"phone_id", "08123456789a", false},
+
+		{"payment_term", "monthly", true},
+		{"payment_term", "quarterly", true},
+		{"payment_term", "semi_annual", true},
+		{"payment_term", "yearly", true},
+		{"payment_term", "Monthly", false},
+		{"payment_term", "weekly", false},
+		{"payment_term", "", false},
+
+		{"future_date", today.Format(DateLayout), true},
+		{"future_date", today.AddDate(0, 0, 1).Format(DateLayout), true},
+		{"future_date", today.AddDate(0, 0, -1).Format(DateLayout), false},
+		{"future_date", today.Format("02-01-2006"), false},
+		{"future_date", "2030-02-30", false},
+		{"future_date", "", false},
+		{"future_date", today, true},
+		{"future_date", today.AddDate(0, 0, -1), false},
+		{"future_date", 20300101, false},
+	} {
+		err := v.Var(tc.value, tc.tag)
+		if (err == nil) != tc.valid {
+			t.Errorf("%s(%#v): err = %v, want valid = %v", tc.tag, tc.value, err, tc.valid)
+		}
+	}
+}
+
+type address struct {
+	City string `json:"city" binding:"required"`
+}
+
+type sample struct {
+	ID          string   `json:"id" binding:"objectid"`
+	Phone       string   `json:"phonenumber" binding:"phone_id"`
+	Term        string   `json:"payment_term" binding:"payment_term"`
+	CheckIn     string   `json:"check_in_date" binding:"future_date"`
+	Email       string   `json:"email" binding:"required,email"`
+	Name        string   `json:"fullname" binding:"min=2"`
+	Tags        []string `json:"tags" binding:"max=1"`
+	Price       int      `json:"price" binding:"gt=0"`
+	Status      string   `json:"status" binding:"oneof=pending paid"`
+	Sort        string   `form:"sort" binding:"omitempty,oneof=asc desc"`
+	OldPassword string   `json:"old_password"`
+	NewPassword string   `json:"new_password" binding:"nefield=OldPassword"`
+	Address     address  `json:"address"`
+	Internal    string   `json:"-" binding:"required"`
+}
+
+func TestErrors(t *testing.T) {
+	err := newValidator().Struct(sample{
+		ID:          "abc",
+		Phone:       "12345",
+		Term:        "weekly",
+		CheckIn:     "2000-01-01",
+		Name:        "A",
+		Tags:        []string{"a", "b"},
+		Status:      "unknown",
+		Sort:        "random",
+		OldPassword: "secret",
+		NewPassword: "secret",
+	})
+	want := []FieldError{
+		{"id", "objectid", "must be a 24-character hex ObjectID"},
+		{"phonenumber", "phone_id", "must be an Indonesian phone number, e.g. 081234567890 or +6281234567890"},
+		{"payment_term", "payment_term", "must be one of: monthly, quarterly, semi_annual, yearly"},
+		{"check_in_date", "future_date", "must be a date in YYYY-MM-DD format that is today or later"},
+		{"email", "required", "is required"},
+		{"fullname", "min", "must be at least 2 characters"},
+		{"tags", "max", "must contain at most 1 items"},
+		{"price", "gt", "must be greater than 0"},
+		{"status", "oneof", "must be one of: pending, paid"},
+		// Nama dari tag form dipakai jika tidak ada tag json
+		{"sort", "oneof", "must be one of: asc, desc"},
+		{"new_password", "nefield", "must be different from old_password"},
+		// Field bersarang dipisah titik
+		{"address.city", "required", "is required"},
+		// json:"-" tidak punya nama untuk client, jadi memakai nama field Go
+		{"Internal", "required", "is required"},
+	}
+	if got := Errors(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Errors() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestErrorsDecoding(t *testing.T) {
+	var target struct {
+		Price int `json:"price"`
+	}
+	typeErr := json.Unmarshal([]byte(`{"price":"mahal"}`), &target)
+	syntaxErr := json.Unmarshal([]byte(`{"price":}`), &target)
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want FieldError
+	}{
+		{"wrong type", typeErr, FieldError{"price", "type", "must be an integer"}},
+		{"empty body", io.EOF, FieldError{"", "required", "request body is required"}},
+		{"invalid JSON", syntaxErr, FieldError{"", "json", "request body must be valid JSON"}},
+		{"truncated body", io.ErrUnexpectedEOF, FieldError{"", "json", "request body must be valid JSON"}},
+		{"anything else", errors.New("boom"), FieldError{"", "invalid", "request could not be parsed"}},
+	} {
+		if got := Errors(tc.err); len(got) != 1 || got[0] != tc.want {
+			t.Errorf("%s: Errors() = %v, want [%v]", tc.name, got, tc.want)
+		}
+	}
+}
+
+// FieldError dikirim ke client di error.details.fields, jadi nama key-nya bagian dari API.
+func TestFieldErrorJSON(t *testing.T) {
+	body, err := json.Marshal(FieldError{Field: "personal_info.email", Rule: "email", Message: "must be a valid email address"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"field":"personal_info.email","rule":"email","message":"must be a valid email address"}`; string(body) != want {
+		t.Errorf("got %s, want %s", body, want)
+	}
+}